@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life"
+)
+
+// animate runs a game with run, which steps it as life.Life.Run does with
+// the options it is given, for the number of generations given, or without
+// end if it is 0, at the rate set by -steps-per-second, while drawing it in
+// the terminal at the rate set by -fps, until the game stops or is
+// interrupted. Each frame is drawn with draw between steps and written by a
+// goroutine of its own, frames
+// falling due while the previous one is being written being dropped, so
+// that a slow terminal never holds back the game; the final generation is
+// always drawn. Each frame ends with a status line giving the current
+// generation, as returned by gen, the generations computed per second
+// since the previous frame and the time a step took on average, the last
+// frame giving the rate over the whole run. Unless -redraw is set, only the
+// cells that changed since the previous frame are written.
+func animate(generations int, gen func() int64, run func(ctx context.Context, opts life.RunOptions) error, draw func(w io.Writer) error) {
+	if *stepsPerSecond < 0 {
+		log.Fatalf("invalid -steps-per-second %v", *stepsPerSecond)
+	}
+	if *fps <= 0 {
+		log.Fatalf("invalid -fps %v", *fps)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var scr screen
+	var text bytes.Buffer
+	begin := time.Now()
+	p := pace{start: begin}
+	steps := 0
+	frame := func(buf *bytes.Buffer, final bool) error {
+		buf.Reset()
+		text.Reset()
+		if err := draw(&text); err != nil {
+			return err
+		}
+		if final {
+			d := time.Since(begin)
+			fmt.Fprintf(&text, "generation %d, %d generations in %v, %.1f gen/s\n",
+				gen(), steps, d.Round(time.Millisecond), float64(steps)/d.Seconds())
+		} else {
+			fmt.Fprintf(&text, "generation %d, ", gen())
+			p.report(&text)
+		}
+		if *redraw {
+			buf.WriteString("\x0c") // Clear screen and print field.
+			buf.Write(text.Bytes())
+		} else {
+			scr.draw(buf, text.Bytes())
+		}
+		return nil
+	}
+	// The buffer of frames passes to the writer and back through free and
+	// out, so that a frame is only drawn once the previous one is written.
+	free, out, written := make(chan *bytes.Buffer, 1), make(chan *bytes.Buffer), make(chan struct{})
+	free <- new(bytes.Buffer)
+	go func() {
+		defer close(written)
+		for buf := range out {
+			os.Stdout.Write(buf.Bytes())
+			free <- buf
+		}
+	}()
+	err := run(ctx, life.RunOptions{
+		Generations:    int64(generations),
+		StepsPerSecond: *stepsPerSecond,
+		FrameRate:      *fps,
+		Frame: func(final bool) error {
+			var buf *bytes.Buffer
+			if final {
+				buf = <-free
+			} else {
+				select {
+				case buf = <-free:
+				default:
+					return nil
+				}
+			}
+			if err := frame(buf, final); err != nil {
+				return err
+			}
+			out <- buf
+			return nil
+		},
+		StepTime: func(d time.Duration) {
+			recordStep(d)
+			steps++
+			p.steps++
+			p.busy += d
+		},
+	})
+	close(out)
+	<-written
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatal(err)
+	}
+}
+
+// stepping returns a function running a game for animate that computes
+// each generation with step.
+func stepping(step func()) func(ctx context.Context, opts life.RunOptions) error {
+	return func(ctx context.Context, opts life.RunOptions) error {
+		return life.RunSteps(ctx, step, opts)
+	}
+}
+
+// pace measures the rate at which a game is stepped between frames.
+type pace struct {
+	steps int           // steps taken since start
+	busy  time.Duration // time spent in them
+	start time.Time
+}
+
+// report writes the status line for the steps taken since start, then
+// starts measuring again.
+func (p *pace) report(w io.Writer) {
+	elapsed := time.Since(p.start)
+	var per time.Duration
+	if p.steps > 0 {
+		per = p.busy / time.Duration(p.steps)
+	}
+	fmt.Fprintf(w, "%.1f gen/s, %v per step\n", float64(p.steps)/elapsed.Seconds(), per.Round(time.Microsecond))
+	*p = pace{start: time.Now()}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPaceReport checks the status line written for the steps taken since
+// the previous frame, and that measuring starts again after it.
+func TestPaceReport(t *testing.T) {
+	p := pace{steps: 4, busy: 8 * time.Millisecond, start: time.Now().Add(-2 * time.Second)}
+	var b strings.Builder
+	p.report(&b)
+	if want := "2.0 gen/s, 2ms per step\n"; b.String() != want {
+		t.Errorf("report wrote %q, want %q", b.String(), want)
+	}
+	if p.steps != 0 || p.busy != 0 || time.Since(p.start) > time.Second {
+		t.Errorf("pace after report: %+v", p)
+	}
+	b.Reset()
+	p.report(&b)
+	if !strings.HasSuffix(b.String(), " gen/s, 0s per step\n") {
+		t.Errorf("report of no steps wrote %q", b.String())
+	}
+}
+
+// TestAnimate checks, by running gol, that the game is stepped at its own
+// rate rather than that of the frames, and that the final generation is
+// always drawn.
+func TestAnimate(t *testing.T) {
+	out, err := gol(t, "", "-steps-per-second", "0", "-fps", "2", "-generations", "200", "-redraw", "-width", "10", "-height", "4")
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	if n := strings.Count(out, "\x0c"); n > 2 {
+		t.Errorf("%d frames drawn at 2 frames per second for steps as fast as possible", n)
+	}
+	if !strings.Contains(out, "generation 200, 200 generations in ") {
+		t.Errorf("final generation not drawn:\n%s", out)
+	}
+	start := time.Now()
+	if out, err := gol(t, "", "-steps-per-second", "50", "-fps", "1000", "-generations", "5", "-redraw"); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	if d := time.Since(start); d < 80*time.Millisecond {
+		t.Errorf("5 generations at 50 per second took %v", d)
+	}
+}
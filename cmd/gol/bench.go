@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life"
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life/patterns"
+)
+
+// A workload is a standard run timed by gol bench.
+type workload struct {
+	name string
+	gens int64
+	seed func() *life.Field
+}
+
+// workloads are the runs timed by gol bench, each seeding every engine with
+// the same field. The unbounded engines run the field as a region of the
+// infinite plane.
+var workloads = []workload{
+	{"soup 256²", 1000, func() *life.Field {
+		r := rand.New(rand.NewSource(1))
+		f := life.NewField(256, 256)
+		for i := 0; i < 256*256/4; i++ {
+			f.Set(r.Intn(256), r.Intn(256), true)
+		}
+		return f
+	}},
+	{"glider gun 1024²", 1000, func() *life.Field { return patternField("glider-gun", 1024) }},
+	{"acorn", 5000, func() *life.Field { return patternField("acorn", 1024) }},
+}
+
+// patternField returns the named built-in pattern centered on a square
+// field with dead edges of the given side.
+func patternField(name string, side int) *life.Field {
+	p, err := patterns.Get(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	f := life.NewFieldWithTopology(side, side, life.Plane)
+	f.Place(p, (side-p.Width())/2, (side-p.Height())/2, life.PlaceOr)
+	return f
+}
+
+// A benchEngine is an engine timed by gol bench, whose make function
+// returns a function advancing a game seeded with f by n generations.
+type benchEngine struct {
+	name string
+	make func(f *life.Field) (func(n int64), error)
+}
+
+// benchEngines are the engines timed by gol bench.
+var benchEngines = []benchEngine{
+	{"field", func(f *life.Field) (func(int64), error) {
+		g := life.NewLifeFromField(f)
+		g.SetWorkers(*workers)
+		return func(n int64) { g.StepN(int(n)) }, nil
+	}},
+	{"infinite", func(f *life.Field) (func(int64), error) {
+		return stepper(life.NewInfiniteFromField(f))
+	}},
+	{"sparse", func(f *life.Field) (func(int64), error) {
+		return stepper(life.NewSparseFieldFromField(f))
+	}},
+	{"hashlife", func(f *life.Field) (func(int64), error) {
+		h, err := life.NewHashLifeFromField(f)
+		if err != nil {
+			return nil, err
+		}
+		return h.Advance, nil
+	}},
+	{"quadtree", func(f *life.Field) (func(int64), error) {
+		return stepper(life.NewQuadFieldFromField(f))
+	}},
+}
+
+// stepper returns a function stepping p n times.
+func stepper(p unbounded, err error) (func(int64), error) {
+	if err != nil {
+		return nil, err
+	}
+	return func(n int64) {
+		for i := int64(0); i < n; i++ {
+			p.Step()
+		}
+	}, nil
+}
+
+// runBench times the standard workloads on the engines named, or on every
+// engine if there are none, printing the generations computed per second
+// and the memory allocated per generation.
+func runBench(names []string) {
+	for _, name := range names {
+		if !slices.ContainsFunc(benchEngines, func(e benchEngine) bool { return e.name == name }) {
+			log.Fatalf("unknown engine %q", name)
+		}
+	}
+	const row = "%-16s %-8s %11v %9v %9v %10v %9v\n"
+	fmt.Printf(row, "workload", "engine", "generations", "time", "gen/s", "allocs/gen", "B/gen")
+	for _, wl := range workloads {
+		for _, e := range benchEngines {
+			if len(names) > 0 && !slices.Contains(names, e.name) {
+				continue
+			}
+			step, err := e.make(wl.seed())
+			if err != nil {
+				log.Fatal(err)
+			}
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+			start := time.Now()
+			step(wl.gens)
+			d := time.Since(start)
+			runtime.ReadMemStats(&after)
+			n := float64(wl.gens)
+			fmt.Printf(row, wl.name, e.name, wl.gens, d.Round(time.Millisecond), int64(n/d.Seconds()),
+				fmt.Sprintf("%.1f", float64(after.Mallocs-before.Mallocs)/n), int64(float64(after.TotalAlloc-before.TotalAlloc)/n))
+		}
+	}
+}
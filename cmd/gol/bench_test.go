@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life"
+)
+
+// TestBench checks that each workload seeds every engine alike, and that
+// every engine can be made and stepped from a seed.
+func TestBench(t *testing.T) {
+	for _, wl := range workloads {
+		if a, b := wl.seed(), wl.seed(); !a.Equal(b) || a.Population() == 0 {
+			t.Errorf("%s: seeds of %d and %d cells differ", wl.name, a.Population(), b.Population())
+		}
+	}
+	f := patternField("glider-gun", 64)
+	if f.Width() != 64 || f.Height() != 64 || f.Population() != 36 || f.Topology() != life.Plane {
+		t.Errorf("glider gun field: %d×%d on %v with %d cells", f.Width(), f.Height(), f.Topology(), f.Population())
+	}
+	for _, e := range benchEngines {
+		step, err := e.make(f.Clone())
+		if err != nil {
+			t.Errorf("%s: %v", e.name, err)
+			continue
+		}
+		step(8)
+	}
+	if out, err := gol(t, "", "bench", "no-such-engine"); err == nil {
+		t.Errorf("gol bench no-such-engine: no error\n%s", out)
+	}
+}
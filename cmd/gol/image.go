@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life"
+)
+
+// imageOptions returns the image rendering options selected by the flags,
+// exiting the program if they are invalid.
+func imageOptions() *life.ImageOptions {
+	alive, err := parseColor(*aliveColor)
+	if err != nil {
+		log.Fatal(err)
+	}
+	dead, err := parseColor(*deadColor)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return &life.ImageOptions{CellSize: *cellSize, Alive: alive, Dead: dead}
+}
+
+// readImage decodes the image stored in the named file.
+func readImage(name string) (image.Image, error) {
+	r, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return img, nil
+}
+
+// parseColor parses a color written as #rrggbb.
+func parseColor(s string) (color.Color, error) {
+	var c color.RGBA
+	if _, err := fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B); err != nil || len(s) != 7 {
+		return nil, fmt.Errorf("invalid color %q, want #rrggbb", s)
+	}
+	c.A = 0xff
+	return c, nil
+}
+
+// writeFile creates the named file and fills it using write.
+func writeFile(name string, write func(io.Writer) error) error {
+	w, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := write(w); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// writePNG writes the field to the named file as a PNG image.
+func writePNG(name string, f *life.Field, opt *life.ImageOptions) error {
+	return writeFile(name, func(w io.Writer) error {
+		return png.Encode(w, f.Image(opt))
+	})
+}
+
+// writeGIF records n generations of the game to the named file as an
+// animated GIF.
+func writeGIF(name string, grid *life.Life, n, delay int, opt *life.ImageOptions) error {
+	return writeFile(name, func(w io.Writer) error {
+		return grid.WriteGIF(w, n, delay, opt)
+	})
+}
+
+// writeFrames records n generations of the game to the named directory,
+// which is created if necessary, writing every nth generation as a PNG.
+// The frames are numbered consecutively from zero, as tools such as ffmpeg
+// expect.
+func writeFrames(dir string, grid *life.Life, n, every int, opt *life.ImageOptions) error {
+	if every < 1 {
+		return fmt.Errorf("invalid frame interval %d", every)
+	}
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			grid.Step()
+		}
+		if i%every != 0 {
+			continue
+		}
+		name := filepath.Join(dir, fmt.Sprintf("frame%06d.png", i/every))
+		if err := writePNG(name, grid.State(), opt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSVG writes the field to the named file as an SVG image.
+func writeSVG(name string, f *life.Field, opt *life.ImageOptions) error {
+	return writeFile(name, func(w io.Writer) error {
+		return f.WriteSVG(w, opt)
+	})
+}
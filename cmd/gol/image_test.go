@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life"
+)
+
+// TestWriteFrames checks that every nth generation is written as a PNG,
+// numbered consecutively from zero.
+func TestWriteFrames(t *testing.T) {
+	seed := life.NewField(6, 6)
+	for _, p := range [][2]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}} {
+		seed.Set(p[0], p[1], true)
+	}
+	want := life.NewLifeFromField(seed.Clone())
+	dir := filepath.Join(t.TempDir(), "frames")
+	if err := writeFrames(dir, life.NewLifeFromField(seed), 7, 3, nil); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("%d frames written for generations 0, 3 and 6", len(entries))
+	}
+	for i := 0; i < 3; i++ {
+		file, err := os.Open(filepath.Join(dir, fmt.Sprintf("frame%06d.png", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(file)
+		file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !life.FieldFromImage(img, 128).Equal(want.State()) {
+			t.Errorf("frame %d differs from generation %d", i, 3*i)
+		}
+		want.StepN(3)
+	}
+	if err := writeFrames(dir, want, 3, 0, nil); err == nil {
+		t.Error("writeFrames every 0 generations: no error")
+	}
+}
@@ -0,0 +1,956 @@
+// Command gol runs Conway's Game of Life in the terminal.
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"io"
+	"io/fs"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life"
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life/patterns"
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life/rules"
+)
+
+var (
+	boardWidth     = flag.Int("width", 40, "`width` of the board in cells")
+	boardHeight    = flag.Int("height", 15, "`height` of the board in cells")
+	generations    = flag.Int("generations", 1000, "`number` of generations to run the game for, or 0 to run it until interrupted")
+	randSeed       = flag.Int64("seed", 0, "seed the random soup with the given `number`, so that runs can be repeated (default a seed from the clock)")
+	density        = flag.Float64("density", 0.25, "`probability` of each cell of the random soup being alive")
+	rleFile        = flag.String("rle", "", "start from the pattern in the given RLE `file` instead of a random soup")
+	cellsFile      = flag.String("cells", "", "start from the pattern in the given plaintext .cells `file`")
+	loadFile       = flag.String("load", "", "start from the pattern in the given `file` or http(s) URL, in the format implied by its extension")
+	pattern        = flag.String("pattern", "", "start from the named built-in `pattern` (see gol list-patterns)")
+	imageFile      = flag.String("image", "", "start from the given PNG, JPEG or GIF `file`, with dark pixels as live cells")
+	threshold      = flag.Uint("threshold", 128, "`luminance` below which image pixels become live cells")
+	rule           = flag.String("rule", "", "play by the given `rule`: a name such as highlife, B/S notation such as B36/S23, Hensel notation such as B2-a/S12, a stochastic rule such as B3(0.98)/S23(0.99), a hexagonal rule such as B2/S34H, a rule with a neighbor mask such as B3/S23N@F78, a Margolus rule such as critters, a Larger than Life rule such as R5,C0,M1,S34..58,B34..45,NM, a Generations rule such as /2/3, wireworld, or a Golly .rule file (default the pattern's rule, or B3/S23; see gol list-rules)")
+	schedule       = flag.String("rule-schedule", "", "switch rules following the given `schedule` of generations and rules, such as 0:B3/S23,500:B36/S23")
+	topology       = flag.String("topology", "", "behavior of the board beyond its edges: wrap, dead, mirror or flip, one of these for each axis as in wrap/dead, or klein, mobius or projective (default the pattern's bounded grid, or wrap)")
+	plane          = flag.Bool("plane", false, "treat cells beyond the edges of the board as dead, like -topology dead")
+	infinite       = flag.Bool("infinite", false, "play on an unbounded plane that grows as the pattern spreads, under a B/S rule, showing a window of the board's size that follows the pattern")
+	sparse         = flag.Bool("sparse", false, "with -infinite, store the set of live cells instead of chunks of the plane, which is faster for sparse patterns")
+	engine         = flag.String("engine", "field", "`engine` computing the game: field, or on an unbounded plane as with -infinite, hashlife for HashLife or quadtree for a quadtree of populated blocks")
+	maskFlag       = flag.String("mask", "", "count as neighbors only the cells set in the given `mask` of rows, such as 111/101/000, under the B/S rule")
+	nbhd           = flag.String("neighborhood", "moore", "count neighbors in the `moore` (8 cells) or vonneumann (4 cells) neighborhood")
+	weights        = flag.String("weights", "", "play by the weighted neighborhood rule in the given `file`")
+	noise          = flag.Float64("noise", 0, "flip each cell with the given `probability` after every generation")
+	stopOnCycle    = flag.Bool("stop-on-cycle", false, "stop the game once the board repeats a recent generation, reporting the period of the cycle and the generation it began at")
+	skip           = flag.Int64("skip", 0, "advance the game by the given `number` of generations before showing or recording it")
+	pprofAddr      = flag.String("pprof", "", "serve net/http/pprof profiles and per-generation timing metrics on the given `address`, such as :6060")
+	stepsPerSecond = flag.Float64("steps-per-second", 5, "`rate` at which generations are computed, or 0 for as fast as possible")
+	fps            = flag.Float64("fps", 5, "`rate` at which the board is redrawn in the terminal, independently of -steps-per-second")
+	glyphs         = flag.String("glyphs", "", "show live and dead cells in the terminal as the two given `characters`, such as \"█·\" (default \"* \")")
+	redraw         = flag.Bool("redraw", false, "reprint the whole board for every frame instead of updating the cells that changed, for terminals without ANSI escapes")
+	verify         = flag.String("verify", "", "run the two `engines` given, such as field,naive or hashlife,quadtree, in lockstep without showing the game, panicking with the cells that differ at the first generation the engines disagree")
+	workers        = flag.Int("workers", 0, "`number` of goroutines computing each generation (default GOMAXPROCS)")
+	colors         = flag.Bool("color", false, "color the cells of multi-state rules in the terminal")
+	species        = flag.Int("species", 0, "play the rule with the given `number` of competing species, newborns joining the majority species of their parents, and show the population of each")
+	teams          = flag.Bool("teams", false, "with -species or the colored rules immigration and quadlife, seed each color in its own band of the board and show the population of each")
+	stdinRLE       = flag.Bool("stdin", false, "read an RLE snippet from standard input and paste it onto the board")
+	pasteAt        = flag.String("at", "", "paste the -stdin snippet with its top-left corner at `x,y` (default centered)")
+	place          = flag.String("place", "", "paste the given `patterns`, built-in names or files, each with its top-left corner at a position, as in \"glider@10,5 blinker@20,5\"")
+	placeMode      = flag.String("place-mode", "or", "combine the -stdin and -place patterns with the board by the given `mode`: or to add their live cells, xor to toggle them, or overwrite to copy their dead cells too")
+	antTurns       = flag.String("ant", "", "run Langton's Ant with the given `turns` for each cell state, such as RL or LLRR, instead of a game")
+	oneD           = flag.Bool("1d", false, "run the elementary one-dimensional automaton whose Wolfram code is given by -rule (default 30), printing one generation per line")
+	threeD         = flag.Bool("3d", false, "run three-dimensional Life under the Bays rule given by -rule, such as 5766 (default 4555), drawing its layers side by side")
+	antCount       = flag.Int("ants", 1, "`number` of ants for -ant, spaced along the middle row")
+
+	saveFile   = flag.String("save", "", "write the final generation to the given `file`, in the format implied by its extension")
+	csvFile    = flag.String("csv", "", "append the live cells of each generation to the given CSV `file`")
+	pngFile    = flag.String("png", "", "write the final generation to the given PNG `file`")
+	svgFile    = flag.String("svg", "", "write the final generation to the given SVG `file`")
+	gifFile    = flag.String("gif", "", "record the game to the given animated GIF `file` instead of animating it")
+	gifFrames  = flag.Int("gif-frames", 100, "`number` of generations to record with -gif")
+	gifDelay   = flag.Int("gif-delay", 10, "`delay` between GIF frames in hundredths of a second")
+	framesDir  = flag.String("frames", "", "write each generation as a numbered PNG in the given `directory` instead of animating it")
+	every      = flag.Int("every", 1, "with -frames, write only every `n`th generation")
+	cellSize   = flag.Int("cell-size", 4, "side of each cell in `pixels` in image output")
+	aliveColor = flag.String("alive-color", "#000000", "`color` of live cells in image output")
+	deadColor  = flag.String("dead-color", "#ffffff", "`color` of dead cells in image output")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gol: ")
+	flag.Usage = usage
+	flag.Parse()
+	if *pprofAddr != "" {
+		servePprof(*pprofAddr)
+	}
+	switch flag.Arg(0) {
+	case "":
+	case "list-patterns":
+		listPatterns()
+		return
+	case "list-rules":
+		listRules()
+		return
+	case "bench":
+		runBench(flag.Args()[1:])
+		return
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if *boardWidth <= 0 || *boardHeight <= 0 {
+		log.Fatalf("invalid board size %d×%d", *boardWidth, *boardHeight)
+	}
+	if *generations < 0 {
+		log.Fatalf("invalid -generations %d", *generations)
+	}
+
+	if *oneD {
+		runElementary()
+		return
+	}
+	if *threeD {
+		runLife3D()
+		return
+	}
+	if *antTurns != "" {
+		runAnts()
+		return
+	}
+	if m := loadAutomaton(); m != nil {
+		runAutomaton(m)
+		return
+	}
+	if h := loadHashLife(); h != nil {
+		runUnbounded(h, *boardWidth, *boardHeight)
+		return
+	}
+	if *density < 0 || *density > 1 {
+		log.Fatalf("invalid -density %v, want a probability from 0 to 1", *density)
+	}
+	src := rand.NewSource(*randSeed)
+	if *randSeed == 0 {
+		src = rand.NewSource(time.Now().UnixNano())
+	}
+	grid := life.NewLife(*boardWidth, *boardHeight, life.WithRandom(*density, src))
+	seed := initialField()
+	mode, err := life.ParsePlaceMode(*placeMode)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *stdinRLE {
+		p, err := life.LoadRLE(os.Stdin)
+		if err != nil {
+			log.Fatalf("stdin: %v", err)
+		}
+		if seed == nil {
+			seed = life.NewField(max(*boardWidth, p.Width()), max(*boardHeight, p.Height()))
+		}
+		x, y := (seed.Width()-p.Width())/2, (seed.Height()-p.Height())/2
+		if *pasteAt != "" {
+			if _, err := fmt.Sscanf(*pasteAt, "%d,%d", &x, &y); err != nil {
+				log.Fatalf("invalid -at position %q, want x,y", *pasteAt)
+			}
+		}
+		seed.Place(p, x, y, mode)
+	}
+	if *place != "" {
+		if seed == nil {
+			seed = life.NewField(*boardWidth, *boardHeight)
+		}
+		placePatterns(seed, *place, mode)
+	}
+	if seed != nil {
+		grid = life.NewLifeFromField(seed)
+	}
+	switch *engine {
+	case "field":
+	case "hashlife", "quadtree":
+		*infinite = true
+	default:
+		log.Fatalf("unknown engine %q", *engine)
+	}
+	if *infinite {
+		runInfinite(grid.Field())
+		return
+	}
+	if *sparse {
+		log.Fatal("-sparse requires -infinite")
+	}
+	switch *nbhd {
+	case "moore":
+	case "vonneumann", "von-neumann":
+		grid.SetNeighborhood(life.VonNeumann)
+	default:
+		log.Fatalf("unknown neighborhood %q", *nbhd)
+	}
+	if *topology != "" {
+		top, err := life.ParseTopology(*topology)
+		if err != nil {
+			log.Fatal(err)
+		}
+		grid.SetTopology(top)
+	}
+	if *plane {
+		if *topology != "" {
+			log.Fatal("-plane is not supported with -topology")
+		}
+		grid.SetTopology(life.Plane)
+	}
+	if *noise < 0 || *noise > 1 {
+		log.Fatalf("invalid -noise %v, want a probability from 0 to 1", *noise)
+	}
+	grid.SetNoise(*noise)
+	grid.SetWorkers(*workers)
+	if *weights != "" {
+		// The weighted rule replaces the rule of the game, which -rule and
+		// -mask would in turn replace.
+		if *rule != "" {
+			log.Fatal("-weights is not supported with -rule")
+		}
+		if *maskFlag != "" {
+			log.Fatal("-weights is not supported with -mask")
+		}
+		w, err := loadWeighted(*weights)
+		if err != nil {
+			log.Fatal(err)
+		}
+		grid.SetStepper(w)
+	}
+	if *rule != "" {
+		if m := setRule(grid, *rule); m != nil {
+			runAutomaton(m)
+			return
+		}
+	}
+	if *maskFlag != "" {
+		mask, err := life.ParseMask(*maskFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		m, err := life.NewMaskRule(grid.Rule(), mask)
+		if err != nil {
+			log.Fatal(err)
+		}
+		grid.SetStepper(m)
+	}
+	if *schedule != "" {
+		if err := scheduleRules(grid, *schedule); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *species != 0 {
+		sp, err := life.NewSpecies(grid.Rule(), *species)
+		if err != nil {
+			log.Fatal(err)
+		}
+		runAutomaton(life.NewAutomatonFromGrid(seedGrid(grid.Field(), sp), sp))
+		return
+	}
+	if *verify != "" {
+		runVerify(grid, *verify)
+		return
+	}
+	grid.StepN(int(*skip))
+	if *gifFile != "" {
+		if err := writeGIF(*gifFile, grid, *gifFrames, *gifDelay, imageOptions()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *framesDir != "" {
+		if *generations == 0 {
+			log.Fatal("-frames needs a number of -generations to record")
+		}
+		if err := writeFrames(*framesDir, grid, *generations, *every, imageOptions()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	// The recorders of the game observe it, being told of each generation
+	// it steps to in the order they are added.
+	if *csvFile != "" {
+		w, err := os.Create(*csvFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer w.Close()
+		csvOut := bufio.NewWriter(w)
+		defer csvOut.Flush()
+		header := true
+		grid.AddObserver(life.ObserverFunc(func(int64, *life.Field) {
+			if err := grid.WriteCSV(csvOut, header); err != nil {
+				log.Fatal(err)
+			}
+			header = false
+		}))
+	}
+	if *pprofAddr != "" {
+		observeMetrics(grid)
+	}
+	period := 0
+	if *stopOnCycle {
+		grid.DetectCycle()
+		grid.AddObserver(life.ObserverFunc(func(int64, *life.Field) {
+			if p, ok := grid.DetectCycle(); ok {
+				period = p
+			}
+		}))
+	}
+	if *glyphs != "" {
+		g := []rune(*glyphs)
+		if len(g) != 2 {
+			log.Fatalf("invalid -glyphs %q, want a live and a dead character", *glyphs)
+		}
+		grid.SetGlyphs(g[0], g[1])
+	}
+	_, hex := grid.Stepper().(life.HexRule)
+	animate(*generations, grid.Generation, func(ctx context.Context, opts life.RunOptions) error {
+		opts.Stop = func() bool { return period > 0 }
+		return grid.Run(ctx, opts)
+	}, func(w io.Writer) error {
+		if hex {
+			_, err := io.WriteString(w, grid.Field().HexString())
+			return err
+		}
+		return grid.Render(w)
+	})
+	if period > 0 {
+		fmt.Printf("stabilized with period %d at generation %d\n", period, grid.Generation()-int64(period))
+	}
+	if *saveFile != "" {
+		if err := life.Save(*saveFile, grid.State()); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *pngFile != "" {
+		if err := writePNG(*pngFile, grid.State(), imageOptions()); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *svgFile != "" {
+		if err := writeSVG(*svgFile, grid.State(), imageOptions()); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// setRule makes grid play by the named rule, which may be a B/S rule, a
+// Larger than Life rule, a Margolus block rule, a hexagonal rule, a rule
+// with a neighbor mask, an isotropic rule in Hensel notation, a stochastic rule, a multi-state rule
+// or the name of a Golly .rule file, exiting the program if it is none of these. For the multi-state rules it
+// returns an automaton seeded from grid instead.
+func setRule(grid *life.Life, name string) *life.Automaton {
+	r, err := rules.Parse(name)
+	if err == nil {
+		grid.SetRule(r)
+		return nil
+	}
+	if r, lerr := rules.ParseLtL(name); lerr == nil {
+		grid.SetStepper(r)
+		return nil
+	}
+	if r, merr := rules.ParseMargolus(name); merr == nil {
+		grid.SetStepper(r)
+		return nil
+	}
+	if r, herr := life.ParseHexRule(name); herr == nil {
+		grid.SetStepper(r)
+		return nil
+	}
+	if r, merr := life.ParseMaskRule(name); merr == nil {
+		grid.SetStepper(r)
+		return nil
+	}
+	if r, ierr := life.ParseIsotropic(name); ierr == nil {
+		grid.SetStepper(r)
+		return nil
+	}
+	if r, serr := life.ParseStochastic(name, nil); serr == nil {
+		grid.SetStepper(r)
+		return nil
+	}
+	if strings.HasSuffix(name, ".rule") {
+		m, err := loadGollyRule(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return life.NewAutomatonFromGrid(seedGrid(grid.Field(), m), m)
+	}
+	if m, serr := rules.ParseStateRule(name); serr == nil {
+		return life.NewAutomatonFromGrid(seedGrid(grid.Field(), m), m)
+	}
+	log.Fatal(err)
+	return nil
+}
+
+// scheduleRules schedules the rule changes listed in s, a comma-separated
+// list of generations and B/S rules or rule names such as
+// "0:B3/S23,500:highlife".
+func scheduleRules(grid *life.Life, s string) error {
+	for _, change := range strings.Split(s, ",") {
+		g, name, ok := strings.Cut(change, ":")
+		gen, err := strconv.ParseInt(strings.TrimSpace(g), 10, 64)
+		if !ok || err != nil || gen < 0 {
+			return fmt.Errorf("invalid rule change %q, want generation:rule", change)
+		}
+		r, err := rules.Parse(name)
+		if err != nil {
+			return err
+		}
+		grid.ScheduleRule(gen, r)
+	}
+	return nil
+}
+
+// seedGrid returns the multi-state grid for the initial field f under the
+// rule m: the live cells of f are in state 1, or for the colored variants of
+// Life in a random color, or with -teams in the color of the vertical band
+// of the board they are in.
+func seedGrid(f *life.Field, m life.StateRule) *life.Grid {
+	g := life.GridFromField(f, 1)
+	if c, ok := m.(colored); ok {
+		n := c.Colors()
+		for y := 0; y < f.Height(); y++ {
+			for x := 0; x < f.Width(); x++ {
+				if !f.Alive(x, y) {
+					continue
+				}
+				if *teams {
+					g.Set(x, y, life.State(1+x*n/f.Width()))
+				} else {
+					g.Set(x, y, life.State(1+rand.Intn(n)))
+				}
+			}
+		}
+	}
+	return g
+}
+
+// colored is implemented by the colored variants of Life, whose live cells
+// are in states 1 to Colors.
+type colored interface {
+	Colors() int
+}
+
+// scores returns the population of each color of a colored automaton.
+func scores(m *life.Automaton, c colored) string {
+	var buf strings.Builder
+	for i, n := range m.Grid().Census(c.Colors() + 1)[1:] {
+		fmt.Fprintf(&buf, "team %d: %-6d", i+1, n)
+	}
+	return buf.String()
+}
+
+// loadWeighted reads the weighted rule stored in the named file.
+func loadWeighted(name string) (life.Weighted, error) {
+	r, err := os.Open(name)
+	if err != nil {
+		return life.Weighted{}, err
+	}
+	defer r.Close()
+	w, err := life.LoadWeighted(r)
+	if err != nil {
+		return life.Weighted{}, fmt.Errorf("%s: %v", name, err)
+	}
+	return w, nil
+}
+
+// A multiState is a multi-state automaton that can be animated in the
+// terminal.
+type multiState interface {
+	Step()
+	Generation() int64
+	String() string
+	ColorString() string
+}
+
+// runAutomaton animates a multi-state automaton. Only terminal output is
+// supported for such rules.
+func runAutomaton(m multiState) {
+	rejectExports("multi-state rules")
+	for i := int64(0); i < *skip; i++ {
+		m.Step()
+	}
+	animate(*generations, m.Generation, stepping(m.Step), func(w io.Writer) error {
+		if *colors {
+			io.WriteString(w, m.ColorString())
+		} else {
+			fmt.Fprint(w, m)
+		}
+		if a, ok := m.(*life.Automaton); ok && (*teams || *species != 0) {
+			if c, ok := a.Rule().(colored); ok {
+				fmt.Fprintln(w, scores(a, c))
+			}
+		}
+		return nil
+	})
+}
+
+// rejectExports exits the program if any of the image or file export flags
+// other than those allowed is set, as they are not supported with the given
+// mode.
+func rejectExports(mode string, allowed ...string) {
+	for _, name := range []string{"save", "csv", "png", "svg", "gif", "frames"} {
+		if f := flag.Lookup(name); f.Value.String() != f.DefValue && !slices.Contains(allowed, name) {
+			log.Fatalf("-%s is not supported with %s", name, mode)
+		}
+	}
+}
+
+// runElementary prints successive generations of the elementary automaton
+// selected by -rule, one per line, starting from a single live cell.
+func runElementary() {
+	code := uint64(30)
+	if *rule != "" {
+		var err error
+		if code, err = strconv.ParseUint(*rule, 10, 8); err != nil {
+			log.Fatalf("invalid elementary rule %q, want a number from 0 to 255", *rule)
+		}
+	}
+	rejectExports("-1d")
+	e := life.NewElementary(79, uint8(code))
+	for i := 0; *generations == 0 || i < *generations; i++ {
+		fmt.Print(e)
+		e.Step()
+		time.Sleep(time.Second / 10)
+	}
+}
+
+// runLife3D animates three-dimensional Life under the rule selected by
+// -rule, starting from a random soup. With -png, each layer of the final
+// generation is written to its own image, numbered after the file name.
+func runLife3D() {
+	r := life.Life4555
+	if *rule != "" {
+		var err error
+		if r, err = life.ParseRule3D(*rule); err != nil {
+			log.Fatal(err)
+		}
+	}
+	rejectExports("-3d", "png")
+	grid := life.NewLife3D(16, 8, 8, r)
+	animate(*generations, grid.Generation, stepping(grid.Step), func(w io.Writer) error {
+		_, err := fmt.Fprint(w, grid)
+		return err
+	})
+	if *pngFile != "" {
+		f := grid.Field()
+		ext := filepath.Ext(*pngFile)
+		for z := 0; z < f.Depth(); z++ {
+			name := fmt.Sprintf("%s-z%d%s", strings.TrimSuffix(*pngFile, ext), z, ext)
+			if err := writePNG(name, f.Slice(z), imageOptions()); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+}
+
+// unbounded is implemented by the unbounded planes of cells.
+type unbounded interface {
+	Step()
+	Generation() int64
+	Population() int
+	Bounds() image.Rectangle
+	Field(r image.Rectangle) *life.Field
+}
+
+// runInfinite animates the game on an unbounded plane seeded with f, under
+// the B/S rule given by -rule or that of f, computed by the engine given by
+// -engine or else stored as selected by -sparse. The window shown has the
+// size of f.
+func runInfinite(f *life.Field) {
+	if *rule != "" {
+		r, err := rules.Parse(*rule)
+		if err != nil {
+			log.Fatal(err)
+		}
+		f.SetRule(r)
+	}
+	var p unbounded
+	var err error
+	if *sparse && *engine != "field" {
+		log.Fatalf("-sparse is not supported with -engine %s", *engine)
+	}
+	switch {
+	case *engine == "hashlife":
+		p, err = life.NewHashLifeFromField(f)
+	case *engine == "quadtree":
+		p, err = life.NewQuadFieldFromField(f)
+	case *sparse:
+		p, err = life.NewSparseFieldFromField(f)
+	default:
+		p, err = life.NewInfiniteFromField(f)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	runUnbounded(p, f.Width(), f.Height())
+}
+
+// runUnbounded animates the game on the unbounded plane p, showing a window
+// of the given size centered on the live cells. With -save, -png or -svg,
+// the smallest rectangle holding the final live cells is written.
+func runUnbounded(p unbounded, width, height int) {
+	rejectExports("-infinite", "save", "png", "svg")
+	if h, ok := p.(*life.HashLife); ok {
+		h.Advance(*skip)
+	} else {
+		for i := int64(0); i < *skip; i++ {
+			p.Step()
+		}
+	}
+	animate(*generations, p.Generation, stepping(p.Step), func(w io.Writer) error {
+		b := p.Bounds()
+		_, err := fmt.Fprintf(w, "%vpopulation %d, x %d..%d, y %d..%d\n", life.NewLifeFromField(p.Field(window(b, width, height))),
+			p.Population(), b.Min.X, b.Max.X-1, b.Min.Y, b.Max.Y-1)
+		return err
+	})
+	// The final generation is only expanded into a field for the exports
+	// that need one, since the universe may be far too large for it.
+	final := func() *life.Field { return p.Field(p.Bounds()) }
+	if *saveFile != "" {
+		var err error
+		if h, ok := p.(*life.HashLife); ok && filepath.Ext(strings.TrimSuffix(*saveFile, ".gz")) == ".mc" {
+			err = saveMacrocell(*saveFile, h)
+		} else {
+			err = life.Save(*saveFile, final())
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *pngFile != "" {
+		if err := writePNG(*pngFile, final(), imageOptions()); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *svgFile != "" {
+		if err := writeSVG(*svgFile, final(), imageOptions()); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// window returns the width by height rectangle centered on the bounding
+// box b of a pattern.
+func window(b image.Rectangle, width, height int) image.Rectangle {
+	c := b.Min.Add(b.Max).Div(2)
+	r := image.Rectangle{Min: image.Pt(c.X-width/2, c.Y-height/2)}
+	r.Max = r.Min.Add(image.Pt(width, height))
+	return r
+}
+
+// saveMacrocell writes the universe h to the named macrocell file, gzipped
+// if the name ends in .gz, node for node.
+func saveMacrocell(name string, h *life.HashLife) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = file
+	var zw *gzip.Writer
+	if strings.HasSuffix(name, ".gz") {
+		zw = gzip.NewWriter(file)
+		w = zw
+	}
+	err = h.WriteMacrocell(w)
+	if zw != nil && err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// runAnts animates Langton's Ant with the turns given by -ant. The ants
+// start heading north, evenly spaced along the middle row.
+func runAnts() {
+	width, h := *boardWidth, *boardHeight
+	n := max(*antCount, 1)
+	ants := make([]life.Ant, n)
+	for i := range ants {
+		ants[i] = life.Ant{X: (2*i + 1) * width / (2 * n), Y: h / 2, Dir: life.North}
+	}
+	a, err := life.NewAnts(width, h, *antTurns, ants...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	runAutomaton(a)
+}
+
+// loadAutomaton returns an automaton for an RLE pattern given with -rle,
+// -load or -pattern that follows a multi-state rule, named either by -rule
+// or by the header of the pattern, so that the states of its cells are
+// kept. It returns nil for any other pattern.
+func loadAutomaton() *life.Automaton {
+	var g *life.Grid
+	var header, dir string
+	var err error
+	switch {
+	case *rleFile != "":
+		g, header = loadGrid(*rleFile)
+		dir = filepath.Dir(*rleFile)
+	case *cellsFile != "":
+		return nil
+	case *loadFile != "":
+		if life.IsURL(*loadFile) || filepath.Ext(*loadFile) != ".rle" {
+			return nil
+		}
+		g, header = loadGrid(*loadFile)
+		dir = filepath.Dir(*loadFile)
+	case *pattern != "":
+		if g, header, err = patterns.GetGrid(*pattern); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		return nil
+	}
+	if *rule != "" {
+		header = *rule
+	}
+	if _, err := rules.Parse(header); err == nil || header == "" {
+		return nil
+	}
+	m, err := stateRule(header, dir)
+	switch {
+	case err == nil:
+	case dir != "" && !errors.Is(err, fs.ErrNotExist):
+		log.Fatal(err)
+	default:
+		return nil
+	}
+	return life.NewAutomatonFromGrid(centerGrid(g, *boardWidth, *boardHeight), m)
+}
+
+// loadHashLife returns a HashLife universe holding the macrocell file given
+// by -load with -engine hashlife, built from the nodes of the file so that
+// patterns too large to expand into a field can be run, under the rule
+// given by -rule or that of the file. It returns nil for any other pattern,
+// or if patterns are to be pasted onto it.
+func loadHashLife() *life.HashLife {
+	name := *loadFile
+	base := strings.TrimSuffix(name, ".gz")
+	if *engine != "hashlife" || life.IsURL(name) || filepath.Ext(base) != ".mc" || *stdinRLE || *place != "" {
+		return nil
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if base != name {
+		if r, err = gzip.NewReader(file); err != nil {
+			log.Fatalf("%s: %v", name, err)
+		}
+	}
+	h, err := life.LoadMacrocellHashLife(r)
+	if err != nil {
+		log.Fatalf("%s: %v", name, err)
+	}
+	if *rule != "" {
+		r, err := rules.Parse(*rule)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := h.SetRule(r); err != nil {
+			log.Fatal(err)
+		}
+	}
+	return h
+}
+
+// stateRule returns the multi-state rule with the given name: the rule in
+// the named Golly .rule file, the rule returned by rules.ParseStateRule or,
+// failing that and as Golly does for the rules named by patterns, the rule
+// in the file with the given name and extension .rule in dir, if dir is not
+// empty.
+func stateRule(name, dir string) (life.StateRule, error) {
+	if strings.HasSuffix(name, ".rule") {
+		return loadGollyRule(name)
+	}
+	m, err := rules.ParseStateRule(name)
+	if err == nil || dir == "" {
+		return m, err
+	}
+	return loadGollyRule(filepath.Join(dir, name+".rule"))
+}
+
+// loadGollyRule reads the Golly rule stored in the named file.
+func loadGollyRule(name string) (*life.GollyRule, error) {
+	r, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	g, err := life.LoadGollyRule(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return g, nil
+}
+
+// loadGrid reads the multi-state RLE pattern stored in the named file and
+// the rule named in its header, exiting the program if it cannot be read.
+func loadGrid(name string) (*life.Grid, string) {
+	r, err := os.Open(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer r.Close()
+	g, rule, err := life.LoadRLEGrid(r)
+	if err != nil {
+		log.Fatalf("%s: %v", name, err)
+	}
+	return g, rule
+}
+
+// initialField returns the initial field selected by the flags, or nil if
+// the game should start from a random soup.
+func initialField() *life.Field {
+	var f *life.Field
+	var err error
+	switch {
+	case *rleFile != "":
+		f = load(*rleFile, life.LoadRLE)
+	case *cellsFile != "":
+		f = load(*cellsFile, life.LoadCells)
+	case *loadFile != "":
+		if life.IsURL(*loadFile) {
+			f, err = life.LoadURL(context.Background(), *loadFile)
+		} else {
+			f, err = life.Load(*loadFile)
+		}
+	case *pattern != "":
+		f, err = patterns.Get(*pattern)
+	case *imageFile != "":
+		var img image.Image
+		if img, err = readImage(*imageFile); err == nil {
+			f = life.FieldFromImage(img, uint8(min(*threshold, 255)))
+		}
+	default:
+		return nil
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	return center(f, *boardWidth, *boardHeight)
+}
+
+// usage prints the command-line usage message.
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "usage: gol [flags]\n       gol list-patterns\n       gol list-rules\n       gol bench [engine...]\n")
+	flag.PrintDefaults()
+}
+
+// listPatterns prints the names and descriptions of the built-in patterns.
+func listPatterns() {
+	for _, name := range patterns.Names() {
+		title, desc, _ := patterns.Describe(name)
+		fmt.Printf("%-12s %s: %s\n", name, title, desc)
+	}
+}
+
+// listRules prints the names, rulestrings and descriptions of the named
+// rules.
+func listRules() {
+	for _, p := range rules.Presets() {
+		fmt.Printf("%-20s %-14v %s\n", p.Name, p.Rule, p.Description)
+	}
+	for _, p := range rules.GenerationsPresets() {
+		fmt.Printf("%-20s %-14v %s\n", p.Name, p.Rule, p.Description)
+	}
+	fmt.Printf("%-20s %-14v %s\n", "wireworld", life.Wireworld{}, "Electrons flow along wires; builds logic circuits")
+	fmt.Printf("%-20s %-14v %s\n", "immigration", life.Immigration{Rule: life.Conway}, "Two-color Life; newborns take the majority color of their parents")
+	fmt.Printf("%-20s %-14v %s\n", "quadlife", life.QuadLife{Rule: life.Conway}, "Four-color Life; newborns of three colors take the fourth")
+	for _, p := range rules.MargolusPresets() {
+		fmt.Printf("%-20s %v\n%20s %s\n", p.Name, p.Rule, "", p.Description)
+	}
+	for _, p := range rules.LtLPresets() {
+		fmt.Printf("%-20s %v\n%20s %s\n", p.Name, p.Rule, "", p.Description)
+	}
+}
+
+// load reads the pattern stored in the named file using the given decoder,
+// exiting the program if it cannot be read.
+func load(name string, decode func(io.Reader) (*life.Field, error)) *life.Field {
+	r, err := os.Open(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer r.Close()
+	f, err := decode(r)
+	if err != nil {
+		log.Fatalf("%s: %v", name, err)
+	}
+	return f
+}
+
+// placePatterns pastes the patterns of a -place list onto f.
+func placePatterns(f *life.Field, list string, mode life.PlaceMode) {
+	for _, item := range strings.Fields(list) {
+		name, at, ok := strings.Cut(item, "@")
+		if !ok {
+			log.Fatalf("invalid -place item %q, want pattern@x,y", item)
+		}
+		var x, y int
+		if _, err := fmt.Sscanf(at, "%d,%d", &x, &y); err != nil {
+			log.Fatalf("invalid -place position %q, want x,y", at)
+		}
+		load := patterns.Get
+		if filepath.Ext(name) != "" {
+			load = life.Load
+		}
+		p, err := load(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		f.Place(p, x, y, mode)
+	}
+}
+
+// center returns a field of at least the given size with the pattern p
+// copied into its middle. Patterns with a topology other than a torus, read
+// from a bounded grid, are returned as is. The rule and generation of p
+// carry over.
+func center(p *life.Field, width, h int) *life.Field {
+	if p.Topology() != life.Torus {
+		return p
+	}
+	width, h = max(width, p.Width()), max(h, p.Height())
+	f := life.NewField(width, h)
+	dx, dy := (width-p.Width())/2, (h-p.Height())/2
+	for y := 0; y < p.Height(); y++ {
+		for x := 0; x < p.Width(); x++ {
+			f.Set(x+dx, y+dy, p.Alive(x, y))
+		}
+	}
+	f.SetRule(p.Rule())
+	f.SetTopology(p.Topology())
+	f.SetGeneration(p.Generation())
+	return f
+}
+
+// centerGrid is like center for multi-state grids.
+func centerGrid(p *life.Grid, width, h int) *life.Grid {
+	width, h = max(width, p.Width()), max(h, p.Height())
+	g := life.NewGrid(width, h)
+	dx, dy := (width-p.Width())/2, (h-p.Height())/2
+	for y := 0; y < p.Height(); y++ {
+		for x := 0; x < p.Width(); x++ {
+			g.Set(x+dx, y+dy, p.At(x, y))
+		}
+	}
+	return g
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life"
+)
+
+// TestMain runs gol itself rather than the tests when the test binary is
+// run by gol below.
+func TestMain(m *testing.M) {
+	if os.Getenv("GOL_TEST_MAIN") == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// gol runs gol with the given arguments and standard input, returning its
+// combined output.
+func gol(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "GOL_TEST_MAIN=1")
+	cmd.Stdin = strings.NewReader(stdin)
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// frame returns the board recorded in the named PNG frame, drawn with
+// cells of one pixel.
+func frame(t *testing.T, name string) *life.Field {
+	t.Helper()
+	file, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return life.FieldFromImage(img, 128)
+}
+
+// TestWindow checks that the view of an unbounded game follows the pattern
+// wherever it goes.
+func TestWindow(t *testing.T) {
+	for _, tt := range []struct {
+		b    image.Rectangle
+		want image.Rectangle
+	}{
+		{image.Rect(0, 0, 4, 4), image.Rect(-18, -5, 22, 10)},
+		{image.Rect(98, 48, 102, 52), image.Rect(80, 43, 120, 58)},
+		{image.Rect(-210, -90, -190, -70), image.Rect(-220, -87, -180, -72)},
+	} {
+		if got := window(tt.b, 40, 15); got != tt.want {
+			t.Errorf("window(%v, 40, 15) = %v, want %v", tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestCenter checks that a pattern centered on the board keeps its cells,
+// rule and generation.
+func TestCenter(t *testing.T) {
+	p, err := life.LoadRLE(strings.NewReader("#CXRLE Gen=12\nx = 3, y = 3, rule = B36/S23\nbo$2bo$3o!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := center(p, 40, 15)
+	if f.Width() != 40 || f.Height() != 15 || f.Population() != 5 || !f.Alive(19, 8) {
+		t.Errorf("centered glider: %d×%d with %d cells", f.Width(), f.Height(), f.Population())
+	}
+	if f.Generation() != 12 || f.Rule() != p.Rule() {
+		t.Errorf("centered glider at generation %d under %v, want 12 under %v", f.Generation(), f.Rule(), p.Rule())
+	}
+}
+
+// TestStdin checks that a snippet read from standard input is pasted onto
+// the board, centered or at the position given.
+func TestStdin(t *testing.T) {
+	for _, tt := range []struct {
+		at   []string
+		want []image.Point
+	}{
+		{nil, []image.Point{{4, 3}, {5, 4}, {3, 5}, {4, 5}, {5, 5}}},
+		{[]string{"-at", "0,1"}, []image.Point{{1, 1}, {2, 2}, {0, 3}, {1, 3}, {2, 3}}},
+	} {
+		dir := t.TempDir()
+		args := append([]string{"-stdin", "-width", "9", "-height", "9", "-frames", dir, "-generations", "1", "-cell-size", "1"}, tt.at...)
+		if out, err := gol(t, "x = 3, y = 3\nbo$2bo$3o!\n", args...); err != nil {
+			t.Fatalf("gol %v: %v\n%s", args, err, out)
+		}
+		var got []image.Point
+		for x, y := range frame(t, filepath.Join(dir, "frame000000.png")).LiveCells() {
+			got = append(got, image.Pt(x, y))
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("gol %v: cells %v, want %v", args, got, tt.want)
+		}
+	}
+	if out, err := gol(t, "x = 3, y = 3\nbo$2bo$3o!\n", "-stdin", "-at", "1;2", "-frames", t.TempDir(), "-generations", "1"); err == nil {
+		t.Errorf("gol -at 1;2: no error\n%s", out)
+	}
+}
+
+// TestBoardFlags checks that -width, -height and -generations set the size
+// of the board and the number of generations recorded, and that invalid
+// values are rejected.
+func TestBoardFlags(t *testing.T) {
+	dir := t.TempDir()
+	if out, err := gol(t, "", "-width", "7", "-height", "5", "-generations", "4", "-seed", "1", "-frames", dir, "-cell-size", "1"); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Errorf("%d frames written for 4 generations", len(entries))
+	}
+	if f := frame(t, filepath.Join(dir, "frame000000.png")); f.Width() != 7 || f.Height() != 5 {
+		t.Errorf("%d×%d board, want 7×5", f.Width(), f.Height())
+	}
+	for _, args := range [][]string{
+		{"-width", "0"},
+		{"-height", "-3"},
+		{"-generations", "-1"},
+		{"-generations", "0", "-frames", t.TempDir()},
+	} {
+		if out, err := gol(t, "", args...); err == nil {
+			t.Errorf("gol %v: no error\n%s", args, out)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"expvar"
+	"log"
+	"net"
+	"net/http"
+	_ "net/http/pprof"
+	"time"
+
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life"
+)
+
+// Metrics of the stepping of the game, served with -pprof at /debug/vars.
+var (
+	metricGenerations = expvar.NewInt("generations")
+	metricStepTotal   = expvar.NewInt("step_ns_total")
+	metricStepLast    = expvar.NewInt("step_ns_last")
+	metricStepMax     = expvar.NewInt("step_ns_max")
+	metricPopulation  = expvar.NewInt("population")
+)
+
+// servePprof serves the profiles of net/http/pprof at /debug/pprof/ and the
+// metrics of the game at /debug/vars on addr, in the background.
+func servePprof(addr string) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("-pprof: %v", err)
+	}
+	log.Printf("serving profiles at http://%s/debug/pprof/ and metrics at http://%[1]s/debug/vars", ln.Addr())
+	go func() {
+		log.Fatal(http.Serve(ln, nil))
+	}()
+}
+
+// recordStep records in the metrics a step that took d to compute one
+// generation.
+func recordStep(step time.Duration) {
+	d := step.Nanoseconds()
+	metricGenerations.Add(1)
+	metricStepTotal.Add(d)
+	metricStepLast.Set(d)
+	if d > metricStepMax.Value() {
+		metricStepMax.Set(d)
+	}
+}
+
+// observeMetrics makes the game record its population in the metrics after
+// each step.
+func observeMetrics(grid *life.Life) {
+	grid.AddObserver(life.ObserverFunc(func(int64, *life.Field) {
+		metricPopulation.Set(int64(grid.Population()))
+	}))
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life"
+)
+
+// TestMetrics checks the metrics recorded for steps, and the population
+// recorded after each step of an observed game.
+func TestMetrics(t *testing.T) {
+	gens, total, maxStep := metricGenerations.Value(), metricStepTotal.Value(), metricStepMax.Value()
+	long := time.Duration(maxStep) + time.Hour
+	recordStep(long)
+	recordStep(time.Millisecond)
+	if metricGenerations.Value() != gens+2 || metricStepTotal.Value() != total+long.Nanoseconds()+1e6 {
+		t.Errorf("%d generations in %d ns after 2 steps, from %d in %d", metricGenerations.Value(), metricStepTotal.Value(), gens, total)
+	}
+	if metricStepLast.Value() != 1e6 || metricStepMax.Value() != long.Nanoseconds() {
+		t.Errorf("last step %d ns and longest %d, want %d and %d", metricStepLast.Value(), metricStepMax.Value(), int64(1e6), long.Nanoseconds())
+	}
+	f := life.NewField(5, 5)
+	f.FillRect(1, 2, 4, 3, true) // a blinker
+	grid := life.NewLifeFromField(f)
+	grid.SetRule(life.MustParseRule("B3/S012345678"))
+	observeMetrics(grid)
+	grid.Step()
+	if n := metricPopulation.Value(); n != 5 {
+		t.Errorf("population %d recorded after a step, want 5", n)
+	}
+}
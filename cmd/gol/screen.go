@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// A screen redraws successive frames of plain text in an ANSI terminal,
+// moving the cursor to the cells that changed since the previous frame and
+// writing only those, rather than reprinting the whole board.
+type screen struct {
+	prev  []byte   // the frame last drawn
+	lines [][]byte // the lines of prev
+}
+
+// draw appends to out the output turning the terminal from the frame last
+// drawn into frame. Frames whose number of lines changes, or that hold
+// anything but printable ASCII, are drawn whole after clearing the screen.
+func (s *screen) draw(out *bytes.Buffer, frame []byte) {
+	lines := bytes.Split(bytes.TrimSuffix(frame, []byte("\n")), []byte("\n"))
+	if s.prev == nil || len(lines) != len(s.lines) || !printable(frame) {
+		out.WriteString("\x1b[H\x1b[2J")
+		out.Write(frame)
+		s.keep(frame, printable(frame))
+		return
+	}
+	for y, line := range lines {
+		old := s.lines[y]
+		if len(line) != len(old) {
+			fmt.Fprintf(out, "\x1b[%d;1H%s\x1b[K", y+1, line)
+			continue
+		}
+		for x := 0; x < len(line); x++ {
+			if line[x] == old[x] {
+				continue
+			}
+			// Write the run of changed cells from x, carrying on across
+			// gaps too short to be worth moving the cursor over.
+			end := x + 1
+			for i := end; i < len(line) && i < end+4; i++ {
+				if line[i] != old[i] {
+					end = i + 1
+				}
+			}
+			fmt.Fprintf(out, "\x1b[%d;%dH%s", y+1, x+1, line[x:end])
+			x = end
+		}
+	}
+	fmt.Fprintf(out, "\x1b[%d;1H", len(lines)+1)
+	s.keep(frame, true)
+}
+
+// keep records frame as the one last drawn, or forgets the previous frame
+// if the next is to be drawn whole.
+func (s *screen) keep(frame []byte, ok bool) {
+	if !ok {
+		s.prev, s.lines = nil, nil
+		return
+	}
+	s.prev = append(s.prev[:0], frame...)
+	s.lines = bytes.Split(bytes.TrimSuffix(s.prev, []byte("\n")), []byte("\n"))
+}
+
+// printable reports whether b holds only printable ASCII and newlines.
+func printable(b []byte) bool {
+	for _, c := range b {
+		if (c < ' ' || c > '~') && c != '\n' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// term is a terminal understanding the ANSI sequences a screen writes.
+type term struct {
+	lines [][]byte
+	y, x  int
+}
+
+// write applies the output b to the terminal.
+func (t *term) write(b []byte) {
+	for len(b) > 0 {
+		if b[0] == '\x1b' && len(b) > 1 && b[1] == '[' {
+			i := 2
+			for b[i] == ';' || b[i] >= '0' && b[i] <= '9' {
+				i++
+			}
+			params := strings.Split(string(b[2:i]), ";")
+			switch b[i] {
+			case 'H':
+				t.y, t.x = 0, 0
+				if len(params) == 2 {
+					y, _ := strconv.Atoi(params[0])
+					x, _ := strconv.Atoi(params[1])
+					t.y, t.x = y-1, x-1
+				}
+			case 'J':
+				t.lines = nil
+			case 'K':
+				if t.y < len(t.lines) && t.x < len(t.lines[t.y]) {
+					t.lines[t.y] = t.lines[t.y][:t.x]
+				}
+			}
+			b = b[i+1:]
+			continue
+		}
+		r := []rune(string(b))[0]
+		n := len(string(r))
+		if r == '\n' {
+			t.y, t.x = t.y+1, 0
+		} else {
+			for len(t.lines) <= t.y {
+				t.lines = append(t.lines, nil)
+			}
+			line := []rune(string(t.lines[t.y]))
+			for len(line) <= t.x {
+				line = append(line, ' ')
+			}
+			line[t.x] = r
+			t.lines[t.y] = []byte(string(line))
+			t.x++
+		}
+		b = b[n:]
+	}
+}
+
+// TestScreen checks that the output of a screen turns a terminal into each
+// frame in turn, whether it redraws changed cells only or the whole frame.
+func TestScreen(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var scr screen
+	var tm term
+	partial := 0
+	board := func(w, h int, live string) string {
+		var b strings.Builder
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				if rng.Intn(4) == 0 {
+					b.WriteString(live)
+				} else {
+					b.WriteByte(' ')
+				}
+			}
+			b.WriteByte('\n')
+		}
+		return b.String()
+	}
+	for i := 0; i < 40; i++ {
+		h, live := 10, "#"
+		switch {
+		case i%13 == 12:
+			h = 12
+		case i%7 == 6:
+			live = "█"
+		}
+		frame := board(30, h, live) + fmt.Sprintf("generation %d, %d gen/s\n", i, rng.Intn(1<<uint(rng.Intn(20))))
+		var out bytes.Buffer
+		scr.draw(&out, []byte(frame))
+		tm.write(out.Bytes())
+		if !bytes.Contains(out.Bytes(), []byte("\x1b[2J")) {
+			partial++
+		}
+		var got bytes.Buffer
+		for _, line := range tm.lines {
+			got.Write(line)
+			got.WriteByte('\n')
+		}
+		if got.String() != frame {
+			t.Fatalf("frame %d drawn as:\n%s\nwant:\n%s", i, got.String(), frame)
+		}
+	}
+	if partial < 20 {
+		t.Errorf("%d of 40 frames drawn by changed cells only", partial)
+	}
+}
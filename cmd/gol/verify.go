@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"strings"
+
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life"
+)
+
+// A verifyEngine is an engine run by -verify.
+type verifyEngine struct {
+	step   func()
+	bounds func() image.Rectangle // the cells to compare
+
+	// cells returns a function reporting whether the cells within r are
+	// alive.
+	cells func(r image.Rectangle) func(x, y int) bool
+}
+
+// boundedEngines make the engines computing a game on the board itself,
+// from a field they may keep.
+var boundedEngines = map[string]func(f *life.Field) *life.Life{
+	"field": life.NewLifeFromField,
+	"naive": func(f *life.Field) *life.Life {
+		// A transition function is applied cell by cell.
+		g := life.NewLifeFromField(f)
+		g.SetTransition(f.Rule().Next)
+		return g
+	},
+	"single": func(f *life.Field) *life.Life {
+		g := life.NewLifeFromField(f)
+		g.SetWorkers(1)
+		return g
+	},
+	"parallel": func(f *life.Field) *life.Life {
+		g := life.NewLifeFromField(f)
+		g.SetWorkers(4)
+		return g
+	},
+}
+
+// unboundedEngines make the engines computing a game on the infinite plane
+// around a field.
+var unboundedEngines = map[string]func(f *life.Field) (unbounded, error){
+	"infinite": func(f *life.Field) (unbounded, error) { return life.NewInfiniteFromField(f) },
+	"sparse":   func(f *life.Field) (unbounded, error) { return life.NewSparseFieldFromField(f) },
+	"hashlife": func(f *life.Field) (unbounded, error) { return life.NewHashLifeFromField(f) },
+	"quadtree": func(f *life.Field) (unbounded, error) { return life.NewQuadFieldFromField(f) },
+}
+
+// newVerifyEngine returns the engine with the given name seeded with a
+// copy of f, reporting whether it is unbounded.
+func newVerifyEngine(name string, f *life.Field) (verifyEngine, bool) {
+	f = f.Snapshot().Field()
+	if mk, ok := boundedEngines[name]; ok {
+		g := mk(f)
+		return verifyEngine{
+			step:   g.Step,
+			bounds: func() image.Rectangle { return image.Rect(0, 0, f.Width(), f.Height()) },
+			cells: func(image.Rectangle) func(x, y int) bool {
+				return func(x, y int) bool { return g.Alive(x, y) }
+			},
+		}, false
+	}
+	mk, ok := unboundedEngines[name]
+	if !ok {
+		log.Fatalf("unknown -verify engine %q", name)
+	}
+	p, err := mk(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// The cells compared are those of the window of the plane given by its
+	// live cells; those of the other engine are added in by verify.
+	return verifyEngine{
+		step:   p.Step,
+		bounds: p.Bounds,
+		cells: func(r image.Rectangle) func(x, y int) bool {
+			f := p.Field(r)
+			return func(x, y int) bool { return f.Alive(x-r.Min.X, y-r.Min.Y) }
+		},
+	}, true
+}
+
+// runVerify runs the two engines named by -verify, such as field,naive, in
+// lockstep from the field of grid, and panics with the cells that differ at
+// the first generation in which they disagree. Engines computing a game on
+// the board, field, naive, single and parallel, can only be compared with
+// each other, as can those computing it on the infinite plane: infinite,
+// sparse, hashlife and quadtree. The game must follow the B/S rule of its
+// field in the Moore neighborhood, without noise or scheduled rule changes.
+func runVerify(grid *life.Life, spec string) {
+	names := strings.Split(spec, ",")
+	if len(names) != 2 {
+		log.Fatalf("invalid -verify %q, want two engines such as field,naive", spec)
+	}
+	if grid.Stepper() != nil {
+		log.Fatal("-verify is only supported for B/S rules")
+	}
+	// The engines are made from the field alone, so settings of the game
+	// they would not follow are refused rather than ignored.
+	if *nbhd != "moore" {
+		log.Fatal("-verify is not supported with -neighborhood")
+	}
+	if *noise != 0 {
+		log.Fatal("-verify is not supported with -noise")
+	}
+	if *schedule != "" {
+		log.Fatal("-verify is not supported with -rule-schedule")
+	}
+	if *generations == 0 {
+		log.Fatal("-verify needs a number of -generations to compare")
+	}
+	a, ua := newVerifyEngine(names[0], grid.Field())
+	b, ub := newVerifyEngine(names[1], grid.Field())
+	if ua != ub {
+		log.Fatalf("-verify cannot compare %s with %s, as only one of them is unbounded", names[0], names[1])
+	}
+	for gen := 1; gen <= *generations+int(*skip); gen++ {
+		a.step()
+		b.step()
+		if diff := compare(a, b); diff != "" {
+			panic(fmt.Sprintf("%s and %s diverge at generation %d:\n%s", names[0], names[1], gen, diff))
+		}
+	}
+	fmt.Printf("%s and %s agree over %d generations\n", names[0], names[1], *generations+int(*skip))
+}
+
+// compare returns the cells in which the engines differ, showing at most
+// 20 of them, or "" if there are none.
+func compare(a, b verifyEngine) string {
+	var diff strings.Builder
+	n := 0
+	r := a.bounds().Union(b.bounds())
+	ca, cb := a.cells(r), b.cells(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if pa, pb := ca(x, y), cb(x, y); pa != pb {
+				if n < 20 {
+					fmt.Fprintf(&diff, "  cell %d, %d: %v, %v\n", x, y, alive(pa), alive(pb))
+				}
+				n++
+			}
+		}
+	}
+	if n > 20 {
+		fmt.Fprintf(&diff, "  and %d more\n", n-20)
+	}
+	return diff.String()
+}
+
+// alive names the state of a cell.
+func alive(b bool) string {
+	if b {
+		return "alive"
+	}
+	return "dead"
+}
@@ -0,0 +1,3 @@
+module github.com/collinp1221/CS371-Cycle-4-Game-of-Life
+
+go 1.23
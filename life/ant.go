@@ -0,0 +1,140 @@
+package life
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// Direction is the heading of an ant.
+type Direction uint8
+
+// Headings of an ant, in clockwise order.
+const (
+	North Direction = iota
+	East
+	South
+	West
+)
+
+// Ant is an ant of a Langton's Ant simulation.
+type Ant struct {
+	X, Y int
+	Dir  Direction
+}
+
+// antGlyphs are the characters used by String for ants heading north,
+// east, south and west.
+const antGlyphs = "^>v<"
+
+// Ants stores the state of a Langton's Ant simulation: a grid of cells
+// walked by one or more ants. At each step every ant in turn looks at the
+// state s of its cell and turns as given by the letter s of its turn
+// string: L turns left, R right, N not at all and U around. The ant then
+// moves the cell to the next state, wrapping back to state 0 after the
+// last, and moves forward one cell. The grid wraps toroidally.
+type Ants struct {
+	g     *Grid
+	ants  []Ant
+	turns string
+	gen   int64 // number of steps taken
+}
+
+// NewAnts returns a new simulation on an empty grid of the specified
+// width and height in which the given ants follow the turn string turns,
+// such as "RL" for Langton's original ant or "LLRR". The turn string has
+// one letter per cell state and so must be between 2 and 256 letters long.
+func NewAnts(width, h int, turns string, ants ...Ant) (*Ants, error) {
+	turns = strings.ToUpper(turns)
+	if len(turns) < 2 || len(turns) > 256 || strings.Trim(turns, "LRNU") != "" {
+		return nil, fmt.Errorf("life: invalid turn string %q", turns)
+	}
+	a := &Ants{g: NewGrid(width, h), turns: turns}
+	for _, ant := range ants {
+		ant.X, ant.Y, ant.Dir = wrap(ant.X, width), wrap(ant.Y, h), ant.Dir%4
+		a.ants = append(a.ants, ant)
+	}
+	return a, nil
+}
+
+// Grid returns the grid walked by the ants.
+func (a *Ants) Grid() *Grid {
+	return a.g
+}
+
+// Ants returns the current positions and headings of the ants.
+func (a *Ants) Ants() []Ant {
+	return append([]Ant(nil), a.ants...)
+}
+
+// Turns returns the turn string of the simulation.
+func (a *Ants) Turns() string {
+	return a.turns
+}
+
+// Generation returns the number of steps taken.
+func (a *Ants) Generation() int64 {
+	return a.gen
+}
+
+// Step moves every ant once.
+func (a *Ants) Step() {
+	n := len(a.turns)
+	for i := range a.ants {
+		ant := &a.ants[i]
+		s := a.g.At(ant.X, ant.Y)
+		switch a.turns[s] {
+		case 'L':
+			ant.Dir += 3
+		case 'R':
+			ant.Dir++
+		case 'U':
+			ant.Dir += 2
+		}
+		ant.Dir %= 4
+		a.g.Set(ant.X, ant.Y, State((int(s)+1)%n))
+		switch ant.Dir {
+		case North:
+			ant.Y--
+		case East:
+			ant.X++
+		case South:
+			ant.Y++
+		case West:
+			ant.X--
+		}
+		ant.X, ant.Y = wrap(ant.X, a.g.width), wrap(ant.Y, a.g.h)
+	}
+	a.gen++
+}
+
+// glyph returns the character String uses for the cell at x, y in state
+// s: an arrow if an ant is on it, and otherwise the glyph of its state.
+func (a *Ants) glyph(x, y int, s State) byte {
+	for _, ant := range a.ants {
+		if ant.X == x && ant.Y == y {
+			return antGlyphs[ant.Dir]
+		}
+	}
+	return stateGlyph(s, len(a.turns))
+}
+
+// String returns the grid as a string, drawing the cells as
+// Automaton.String does and each ant as an arrow pointing its way.
+func (a *Ants) String() string {
+	var buf bytes.Buffer
+	for y := 0; y < a.g.h; y++ {
+		for x := 0; x < a.g.width; x++ {
+			buf.WriteByte(a.glyph(x, y, a.g.At(x, y)))
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// ColorString is like String but colors each cell using 24-bit ANSI
+// terminal escape sequences.
+func (a *Ants) ColorString() string {
+	return colorString(a.g, fadePalette(len(a.turns), color.White, color.Black), a.glyph)
+}
@@ -0,0 +1,39 @@
+package life
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestAnts checks the first steps of Langton's ant, including its wrap
+// across the edges of the grid and its drawing.
+func TestAnts(t *testing.T) {
+	a, err := NewAnts(5, 5, "rl", Ant{X: 5, Y: 1, Dir: North})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Turns() != "RL" {
+		t.Errorf("Turns() = %q, want RL", a.Turns())
+	}
+	for i, want := range []Ant{
+		{1, 1, East}, {1, 2, South}, {0, 2, West}, {0, 1, North},
+		// Back on a cell it flipped, the ant turns left and flips it back.
+		{4, 1, West},
+	} {
+		a.Step()
+		if got := a.Ants(); !slices.Equal(got, []Ant{want}) {
+			t.Fatalf("step %d: ant %v, want %v", i+1, got, want)
+		}
+	}
+	if got, want := a.String(), "     \n *  <\n**   \n     \n     \n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if a.Generation() != 5 {
+		t.Errorf("generation %d, want 5", a.Generation())
+	}
+	for _, turns := range []string{"", "R", "RX"} {
+		if _, err := NewAnts(5, 5, turns); err == nil {
+			t.Errorf("NewAnts(%q): no error", turns)
+		}
+	}
+}
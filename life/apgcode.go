@@ -0,0 +1,168 @@
+package life
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// wechslerDigits are the digits of the extended Wechsler format. The first
+// 32 encode a column of five cells; all 36 are used after 'y' to count runs
+// of blank columns.
+const wechslerDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// Apgcode returns the apgcode of the live cells of the field, as used by
+// Catagolue, treating them as a still life: "xs" followed by the population
+// and the canonical extended Wechsler encoding. Oscillators and spaceships
+// can be encoded by combining WechslerCode with the matching "xp" or "xq"
+// prefix and period.
+func (f *Field) Apgcode() string {
+	pop := 0
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; x++ {
+			if f.get(x, y) {
+				pop++
+			}
+		}
+	}
+	return fmt.Sprintf("xs%d_%s", pop, f.WechslerCode())
+}
+
+// WechslerCode returns the extended Wechsler encoding of the live cells of
+// the field. The field is tried in all eight orientations and the shortest
+// encoding is returned, ties being broken by lexical order, so that
+// congruent patterns share the same code. An empty field is encoded as
+// "0", as Catagolue does.
+func (f *Field) WechslerCode() string {
+	var cells [][2]int
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; x++ {
+			if f.get(x, y) {
+				cells = append(cells, [2]int{x, y})
+			}
+		}
+	}
+	if len(cells) == 0 {
+		return "0"
+	}
+	best := ""
+	t := make([][2]int, len(cells))
+	for o := 0; o < 8; o++ {
+		for i, c := range cells {
+			x, y := c[0], c[1]
+			if o&1 != 0 {
+				x = -x
+			}
+			if o&2 != 0 {
+				y = -y
+			}
+			if o&4 != 0 {
+				x, y = y, x
+			}
+			t[i] = [2]int{x, y}
+		}
+		// The cells come from a field, so they fit one.
+		g, _ := fieldFromCells(t, math.MaxInt)
+		code := encodeWechsler(g)
+		if o == 0 || len(code) < len(best) || len(code) == len(best) && code < best {
+			best = code
+		}
+	}
+	return best
+}
+
+// encodeWechsler returns the extended Wechsler encoding of the field in its
+// current orientation.
+func encodeWechsler(f *Field) string {
+	var b strings.Builder
+	for y0 := 0; y0 < f.h; y0 += 5 {
+		if y0 > 0 {
+			b.WriteByte('z')
+		}
+		blank := 0
+		for x := 0; x < f.width; x++ {
+			v := 0
+			for i := 0; i < 5 && y0+i < f.h; i++ {
+				if f.get(x, y0+i) {
+					v |= 1 << i
+				}
+			}
+			if v == 0 {
+				blank++
+				continue
+			}
+			writeWechslerBlanks(&b, blank)
+			blank = 0
+			b.WriteByte(wechslerDigits[v])
+		}
+		// Trailing blank columns of a strip are implied.
+	}
+	return b.String()
+}
+
+// writeWechslerBlanks writes a run of n blank columns.
+func writeWechslerBlanks(b *strings.Builder, n int) {
+	for n > 0 {
+		switch {
+		case n >= 4:
+			k := min(n, 39)
+			b.WriteByte('y')
+			b.WriteByte(wechslerDigits[k-4])
+			n -= k
+		case n == 3:
+			b.WriteByte('x')
+			n = 0
+		case n == 2:
+			b.WriteByte('w')
+			n = 0
+		default:
+			b.WriteByte('0')
+			n = 0
+		}
+	}
+}
+
+// DecodeApgcode returns a field holding the pattern described by an
+// apgcode such as "xs4_33" or "xq4_153". The prefix is not checked beyond
+// its form; only the extended Wechsler part after the underscore is decoded.
+func DecodeApgcode(code string) (*Field, error) {
+	prefix, w, ok := strings.Cut(code, "_")
+	if !ok || len(prefix) < 2 || prefix[0] != 'x' {
+		return nil, fmt.Errorf("life: invalid apgcode %q", code)
+	}
+	var cells [][2]int
+	x, y := 0, 0
+	for i := 0; i < len(w); i++ {
+		c := w[i]
+		switch {
+		case c == 'z':
+			x, y = 0, y+5
+		case c == 'w':
+			x += 2
+		case c == 'x':
+			x += 3
+		case c == 'y':
+			i++
+			n := -1
+			if i < len(w) {
+				n = strings.IndexByte(wechslerDigits, w[i])
+			}
+			if n < 0 {
+				return nil, fmt.Errorf("life: invalid apgcode %q", code)
+			}
+			x += n + 4
+		default:
+			v := strings.IndexByte(wechslerDigits[:32], c)
+			if v < 0 {
+				return nil, fmt.Errorf("life: invalid character %q in apgcode %q", c, code)
+			}
+			for j := 0; j < 5; j++ {
+				if v&(1<<j) != 0 {
+					cells = append(cells, [2]int{x, y + j})
+				}
+			}
+			x++
+		}
+	}
+	return fieldFromCells(cells, MaxPatternCells)
+}
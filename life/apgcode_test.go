@@ -0,0 +1,55 @@
+package life
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestApgcode checks the apgcodes of a few still lifes as Catagolue lists
+// them, in every orientation, and that decoding them gives the same cells.
+func TestApgcode(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		rows []string
+		want string
+	}{
+		{"block", []string{"OO", "OO"}, "xs4_33"},
+		{"boat", []string{"OO.", "O.O", ".O."}, "xs5_253"},
+		{"beehive", []string{".OO.", "O..O", ".OO."}, "xs6_696"},
+		{"loaf", []string{".OO.", "O..O", ".O.O", "..O."}, "xs7_2596"},
+		{"empty", []string{"..", ".."}, "xs0_0"},
+	} {
+		f := fieldOf(t, Plane, tt.rows...)
+		for i, g := range []*Field{f, f.Rotate90(), f.FlipH(), f.Transpose()} {
+			if got := g.Apgcode(); got != tt.want {
+				t.Errorf("%s, orientation %d: Apgcode() = %q, want %q", tt.name, i, got, tt.want)
+			}
+		}
+		d, err := DecodeApgcode(tt.want)
+		if err != nil {
+			t.Errorf("DecodeApgcode(%q): %v", tt.want, err)
+			continue
+		}
+		if got := d.Apgcode(); got != tt.want {
+			t.Errorf("DecodeApgcode(%q) has apgcode %q", tt.want, got)
+		}
+	}
+}
+
+// TestDecodeApgcode checks the blank runs of the extended Wechsler format
+// and the rejection of malformed codes.
+func TestDecodeApgcode(t *testing.T) {
+	// Two cells 5 blank columns apart, written "y1", and one on the next strip.
+	f, err := DecodeApgcode("xp2_1y11z1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := liveCells(f), pts(0, 0, 6, 0, 0, 5); !slices.Equal(got, want) {
+		t.Errorf("cells %v, want %v", got, want)
+	}
+	for _, code := range []string{"", "xs4", "s4_33", "xs4_3!", "xs4_3y"} {
+		if _, err := DecodeApgcode(code); err == nil {
+			t.Errorf("DecodeApgcode(%q): no error", code)
+		}
+	}
+}
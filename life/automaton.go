@@ -0,0 +1,193 @@
+package life
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"math/rand"
+)
+
+// A StateRule is the rule of a multi-state cellular automaton.
+type StateRule interface {
+	// States returns the number of cell states, including state 0.
+	States() int
+
+	// Next returns the state of the specified cell of g at the next time
+	// step.
+	Next(g *Grid, x, y int) State
+}
+
+// A Colorer is a StateRule with its own colors for rendering. Palette
+// returns one color per state.
+type Colorer interface {
+	Palette() color.Palette
+}
+
+// Automaton stores the state of a multi-state cellular automaton. It is the
+// multi-state counterpart of Life.
+type Automaton struct {
+	a, b     *Grid
+	rule     StateRule
+	width, h int
+	gen      int64 // number of steps taken
+}
+
+// NewAutomaton returns a new automaton following rule with a random initial
+// state in which a quarter of the cells are in state 1.
+func NewAutomaton(width, h int, rule StateRule) *Automaton {
+	a := NewGrid(width, h)
+	for i := 0; i < (width * h / 4); i++ {
+		a.Set(rand.Intn(width), rand.Intn(h), 1)
+	}
+	return NewAutomatonFromGrid(a, rule)
+}
+
+// NewAutomatonFromGrid returns a new automaton following rule whose initial
+// state is the given grid. The grid becomes owned by the automaton and must
+// not be modified by the caller afterwards.
+func NewAutomatonFromGrid(a *Grid, rule StateRule) *Automaton {
+	return &Automaton{
+		a: a, b: NewGrid(a.width, a.h), rule: rule,
+		width: a.width, h: a.h,
+	}
+}
+
+// Grid returns the grid holding the current generation.
+func (m *Automaton) Grid() *Grid {
+	return m.a
+}
+
+// Rule returns the rule the automaton follows.
+func (m *Automaton) Rule() StateRule {
+	return m.rule
+}
+
+// Generation returns the number of steps taken.
+func (m *Automaton) Generation() int64 {
+	return m.gen
+}
+
+// Step advances the automaton by one instant, recomputing and updating all
+// cells.
+func (m *Automaton) Step() {
+	for y := 0; y < m.h; y++ {
+		for x := 0; x < m.width; x++ {
+			m.b.Set(x, y, m.rule.Next(m.a, x, y))
+		}
+	}
+	m.a, m.b = m.b, m.a
+	m.gen++
+}
+
+// stateGlyphs are the characters used by String for states 0, 1 and the
+// further states, which are spread over the remaining glyphs.
+const stateGlyphs = " *o+:."
+
+// glyph returns the character String uses for cells in state s.
+func (m *Automaton) glyph(s State) byte {
+	return stateGlyph(s, m.rule.States())
+}
+
+// stateGlyph returns the character used to draw cells in state s of an
+// automaton with n states.
+func stateGlyph(s State, n int) byte {
+	if s < 2 || n <= 2 {
+		return stateGlyphs[min(int(s), 1)]
+	}
+	extra := len(stateGlyphs) - 2
+	return stateGlyphs[2+(int(s)-2)*extra/(n-2)]
+}
+
+// String returns the grid as a string, drawing empty cells as spaces,
+// cells in state 1 as '*' and higher states with lighter glyphs.
+func (m *Automaton) String() string {
+	var buf bytes.Buffer
+	for y := 0; y < m.h; y++ {
+		for x := 0; x < m.width; x++ {
+			buf.WriteByte(m.glyph(m.a.At(x, y)))
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// ColorString is like String but colors each cell using 24-bit ANSI
+// terminal escape sequences, with empty cells left blank.
+func (m *Automaton) ColorString() string {
+	return colorString(m.a, m.palette(color.White, color.Black), func(x, y int, s State) byte {
+		return m.glyph(s)
+	})
+}
+
+// colorString draws g with each cell colored by its state in p using 24-bit
+// ANSI terminal escape sequences, and with the characters returned by
+// glyph.
+func colorString(g *Grid, p color.Palette, glyph func(x, y int, s State) byte) string {
+	var buf bytes.Buffer
+	for y := 0; y < g.h; y++ {
+		last := State(0)
+		for x := 0; x < g.width; x++ {
+			s := g.At(x, y)
+			if s != last && s != 0 {
+				c := color.RGBAModel.Convert(p[s]).(color.RGBA)
+				fmt.Fprintf(&buf, "\x1b[38;2;%d;%d;%dm", c.R, c.G, c.B)
+				last = s
+			}
+			buf.WriteByte(glyph(x, y, s))
+		}
+		buf.WriteString("\x1b[0m\n")
+	}
+	return buf.String()
+}
+
+// palette returns the color of each state: that of the rule if it is a
+// Colorer, and otherwise alive for state 1 fading towards empty for the
+// further states.
+func (m *Automaton) palette(alive, empty color.Color) color.Palette {
+	n := m.rule.States()
+	if c, ok := m.rule.(Colorer); ok {
+		if p := c.Palette(); len(p) >= n {
+			return p
+		}
+	}
+	return fadePalette(n, alive, empty)
+}
+
+// fadePalette returns a palette of n colors with empty for state 0, alive
+// for state 1 and the further states fading towards empty.
+func fadePalette(n int, alive, empty color.Color) color.Palette {
+	p := make(color.Palette, n)
+	p[0] = empty
+	a := color.RGBAModel.Convert(alive).(color.RGBA)
+	e := color.RGBAModel.Convert(empty).(color.RGBA)
+	for s := 1; s < n; s++ {
+		mix := func(u, v uint8) uint8 {
+			// States fade linearly, never quite reaching the empty color.
+			return uint8((int(u)*(n-s) + int(v)*(s-1)) / (n - 1))
+		}
+		p[s] = color.RGBA{mix(a.R, e.R), mix(a.G, e.G), mix(a.B, e.B), 0xff}
+	}
+	return p
+}
+
+// Image renders the grid as a paletted image with the cell size of opt.
+// Unless the rule has its own palette, state 1 is drawn in the live color
+// of opt and further states fade towards its dead color.
+func (m *Automaton) Image(opt *ImageOptions) *image.Paletted {
+	n := opt.cellSize()
+	base := opt.palette()
+	img := image.NewPaletted(image.Rect(0, 0, m.width*n, m.h*n), m.palette(base[1], base[0]))
+	for y := 0; y < m.h; y++ {
+		for x := 0; x < m.width; x++ {
+			s := uint8(m.a.At(x, y))
+			for j := 0; j < n; j++ {
+				row := img.Pix[img.PixOffset(x*n, y*n+j):]
+				for i := 0; i < n; i++ {
+					row[i] = s
+				}
+			}
+		}
+	}
+	return img
+}
@@ -0,0 +1,209 @@
+package life
+
+// wordParallel reports whether fields with topology t can be stepped by
+// stepWords, which handles every edge behavior except twisted and shifted
+// ones.
+func wordParallel(t Topology) bool {
+	return t.X != Flip && t.Y != Flip && t.ShiftX == 0 && t.ShiftY == 0
+}
+
+// activity records which words of the field of a game changed into the
+// current generation, so that stepWords can skip the regions of the board
+// that have settled. A word can only change if one in the 3×3 block of
+// words around it changed in the previous generation, and one that is
+// skipped needs no writing either, as the other field of the game already
+// holds the same word.
+type activity struct {
+	valid          bool   // whether word and row describe the current generation
+	word, nextWord []bool // whether each word changed, by index in bits
+	row, nextRow   []bool // whether any word of each row changed
+	live           []bool // whether each tile of the current generation has live cells, when not valid
+
+	// The conditions under which the record was made, which must still
+	// hold for it to be used.
+	rule  Rule
+	top   Topology
+	edits int64
+}
+
+// settled reports whether the word i of row y, and every word around it,
+// did not change into the current generation. The first and last rows and
+// words of a row count as adjacent whatever the topology.
+func (a *activity) settled(f *Field, y, i int) bool {
+	h, stride := f.h, f.stride
+	for _, yy := range [3]int{(y + h - 1) % h, y, (y + 1) % h} {
+		if !a.row[yy] {
+			continue
+		}
+		for _, ii := range [3]int{(i + stride - 1) % stride, i, (i + 1) % stride} {
+			if a.word[f.index(ii, yy)] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// markLive records in live which tiles of f have live cells.
+func (a *activity) markLive(f *Field) {
+	for t := range a.live {
+		a.live[t] = false
+		for _, w := range f.bits[t*tileSize : (t+1)*tileSize] {
+			if w != 0 {
+				a.live[t] = true
+				break
+			}
+		}
+	}
+}
+
+// quiet reports whether tile tx, ty and every tile around it are dead, so
+// that under a rule without B0 it stays dead. As in settled, the first and
+// last tiles of each axis count as adjacent.
+func (a *activity) quiet(tx, ty, stride, th int) bool {
+	for _, y := range [3]int{(ty + th - 1) % th, ty, (ty + 1) % th} {
+		for _, x := range [3]int{(tx + stride - 1) % stride, tx, (tx + 1) % stride} {
+			if a.live[y*stride+x] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// stepWords writes rows y0 to y1-1 of the generation following src under
+// the rule r into dst, which has the same dimensions, computing 64 cells at
+// a time, tile by tile. If act is valid, words whose surroundings have
+// settled are skipped, and act records the changes into the next
+// generation; otherwise, quiet tiles are cleared without being computed.
+// The live neighbors of each cell are summed in parallel into a 4-bit count
+// held in four words, one per bit, by chaining half adders, and the rule is
+// then applied to the count with boolean operations.
+func stepWords(dst, src *Field, r Rule, y0, y1 int, act *activity) {
+	if src.stride == 0 {
+		return
+	}
+	for y := y0; y < y1; y++ {
+		act.nextRow[y] = false
+	}
+	last, tail := src.stride-1, src.tailMask()
+	endBit := uint(src.width-1) & 63
+	th := (src.h + tileSize - 1) / tileSize
+	skipQuiet := !act.valid && !r.B0()
+	for ty := y0 / tileSize; ty*tileSize < y1; ty++ {
+		ya, yb := max(ty*tileSize, y0), min((ty+1)*tileSize, y1)
+		// Find the rows from ya-1 to yb, which may lie beyond the top or
+		// bottom edge, as the index of their first word, or -1 beyond a dead
+		// edge, along with the cells just beyond their left and right ends.
+		var base [tileSize + 2]int
+		var wests, easts [tileSize + 2]uint64
+		for j := range yb - ya + 2 {
+			y, ok, _ := edge(ya+j-1, src.h, src.top.Y)
+			if !ok {
+				base[j] = -1
+				continue
+			}
+			base[j] = src.index(0, y)
+			if src.Alive(-1, y) {
+				wests[j] = 1
+			}
+			if src.Alive(src.width, y) {
+				easts[j] = 1
+			}
+		}
+		// shifted returns word i of row j of those found shifted a cell east,
+		// with bits carried across words and the cells beyond the ends of
+		// the row brought in, as is, and shifted a cell west.
+		shifted := func(j, i int) [3]uint64 {
+			k := base[j]
+			if k < 0 {
+				return [3]uint64{}
+			}
+			k += i * tileSize
+			c := src.bits[k]
+			w, e := c<<1, c>>1
+			if i > 0 {
+				w |= src.bits[k-tileSize] >> 63
+			} else {
+				w |= wests[j]
+			}
+			if i < last {
+				e |= src.bits[k+tileSize] << 63
+			} else {
+				e |= easts[j] << endBit
+			}
+			return [3]uint64{w, c, e}
+		}
+		for i := 0; i <= last; i++ {
+			if skipQuiet && act.quiet(i, ty, src.stride, th) {
+				for y := ya; y < yb; y++ {
+					dst.bits[dst.index(i, y)] = 0
+					act.nextWord[src.index(i, y)] = false
+				}
+				continue
+			}
+			// Slide down the column of words, keeping the rows above and at
+			// the current one from the previous rows.
+			above, at := shifted(0, i), shifted(1, i)
+			for y := ya; y < yb; y++ {
+				north, here, south := above, at, shifted(y-ya+2, i)
+				above, at = here, south
+				if act.valid && !act.row[(y+src.h-1)%src.h] && !act.row[y] && !act.row[(y+1)%src.h] {
+					continue
+				}
+				k := base[y-ya+1] + i*tileSize
+				act.nextWord[k] = false
+				if act.valid && act.settled(src, y, i) {
+					continue
+				}
+				var c0, c1, c2, c3 uint64
+				add := func(n uint64) {
+					t0 := c0 & n
+					c0 ^= n
+					t1 := c1 & t0
+					c1 ^= t0
+					c3 |= c2 & t1
+					c2 ^= t1
+				}
+				for _, n := range north {
+					add(n)
+				}
+				add(here[0])
+				add(here[2])
+				for _, n := range south {
+					add(n)
+				}
+				alive := here[1]
+				var next uint64
+				for n := 0; n <= 8; n++ {
+					var from uint64
+					if r.birth&(1<<n) != 0 {
+						from |= ^alive
+					}
+					if r.survival&(1<<n) != 0 {
+						from |= alive
+					}
+					if from == 0 {
+						continue
+					}
+					eq := from
+					for b, c := range [4]uint64{c0, c1, c2, c3} {
+						if n&(1<<b) != 0 {
+							eq &= c
+						} else {
+							eq &^= c
+						}
+					}
+					next |= eq
+				}
+				if i == last {
+					next &= tail
+				}
+				if next != alive {
+					act.nextWord[k], act.nextRow[y] = true, true
+				}
+				dst.bits[k] = next
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package life
+
+import "image/color"
+
+// States of the Brian's Brain automaton other than Empty.
+const (
+	Firing     State = 1 + iota // neuron firing
+	Refractory                  // neuron resting after firing
+)
+
+// BriansBrain is Brian Silverman's three-state Brian's Brain rule, the
+// Generations rule B2/S/C3. An empty cell fires if exactly two of its
+// neighbors are firing; a firing cell becomes refractory, and a refractory
+// cell becomes empty. Almost any seed fills the field with gliders.
+type BriansBrain struct{}
+
+// States returns the number of states of the rule.
+func (BriansBrain) States() int { return 3 }
+
+// String returns the rule in B/S/C notation.
+func (BriansBrain) String() string { return "B2/S/C3" }
+
+// Next implements StateRule.
+func (BriansBrain) Next(g *Grid, x, y int) State {
+	switch g.At(x, y) {
+	case Empty:
+		if g.Count(x, y, Firing) == 2 {
+			return Firing
+		}
+	case Firing:
+		return Refractory
+	}
+	return Empty
+}
+
+// Palette implements Colorer, drawing firing cells white and refractory
+// cells blue on black.
+func (BriansBrain) Palette() color.Palette {
+	return color.Palette{
+		color.Black,
+		color.White,
+		color.RGBA{0x30, 0x60, 0xff, 0xff},
+	}
+}
@@ -0,0 +1,37 @@
+package life
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestBriansBrain checks that Brian's Brain steps as the Generations rule
+// B2/S/C3 it equals.
+func TestBriansBrain(t *testing.T) {
+	g, err := ParseGenerations(BriansBrain{}.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	soup := func() *Grid {
+		rng := rand.New(rand.NewSource(3))
+		m := NewGrid(40, 30)
+		for y := 0; y < m.Height(); y++ {
+			for x := 0; x < m.Width(); x++ {
+				m.Set(x, y, State(rng.Intn(3)))
+			}
+		}
+		return m
+	}
+	want := NewAutomatonFromGrid(soup(), g)
+	m := NewAutomatonFromGrid(soup(), BriansBrain{})
+	for gen := 1; gen <= 20; gen++ {
+		want.Step()
+		m.Step()
+		if m.String() != want.String() {
+			t.Fatalf("generation %d:\n%s\nwant\n%s", gen, m, want)
+		}
+	}
+	if p := (BriansBrain{}).Palette(); len(p) != (BriansBrain{}).States() {
+		t.Errorf("%d colors for %d states", len(p), (BriansBrain{}).States())
+	}
+}
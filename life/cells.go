@@ -0,0 +1,102 @@
+package life
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LoadCells reads a pattern in the plaintext .cells format, in which each
+// line is a row of cells drawn with '.' for dead cells and 'O' for live ones,
+// and lines starting with '!' are comments, one of which may record the
+// generation as "!Generation n". The field is as wide as the longest row;
+// shorter rows are padded with dead cells. Patterns larger than
+// MaxPatternCells are rejected.
+func LoadCells(r io.Reader) (*Field, error) {
+	return loadCells(r, MaxPatternCells)
+}
+
+// loadCells is LoadCells for patterns of at most maxCells cells.
+func loadCells(r io.Reader, maxCells int) (*Field, error) {
+	sc := bufio.NewScanner(r)
+	var rows []string
+	width := 0
+	var gen int64
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), " \t\r")
+		if c, ok := strings.CutPrefix(line, "!"); ok {
+			if n, ok := parseGenerationComment(c); ok {
+				gen = n
+			}
+			continue
+		}
+		rows = append(rows, line)
+		width = max(width, len(line))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if err := checkArea(width, len(rows), maxCells); err != nil {
+		return nil, err
+	}
+	f := NewField(width, len(rows))
+	f.gen = gen
+	for y, row := range rows {
+		for x := 0; x < len(row); x++ {
+			switch row[x] {
+			case '.':
+			case 'O', '*':
+				f.Set(x, y, true)
+			default:
+				return nil, fmt.Errorf("life: unexpected character %q in cells row %d", row[x], y+1)
+			}
+		}
+	}
+	return f, nil
+}
+
+// WriteCells writes the field to w in the plaintext .cells format, with a
+// comment recording its generation if it is not 0.
+func (f *Field) WriteCells(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if c := generationComment(f.gen); c != "" {
+		fmt.Fprintf(bw, "!%s\n", c)
+	}
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; x++ {
+			b := byte('.')
+			if f.get(x, y) {
+				b = 'O'
+			}
+			bw.WriteByte(b)
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the field in the
+// plaintext .cells format as WriteCells does.
+func (f *Field) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := f.WriteCells(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, replacing the field
+// with one read from the plaintext .cells format as LoadCells does.
+func (f *Field) UnmarshalText(text []byte) error {
+	g, err := LoadCells(bytes.NewReader(text))
+	if err != nil {
+		return err
+	}
+	// Count the replacement as an edit, so that a game stepping f does not
+	// take the cells it last saw changing for those of the new pattern.
+	g.edits = f.edits + 1
+	*f = *g
+	return nil
+}
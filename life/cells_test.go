@@ -0,0 +1,49 @@
+package life
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestUnmarshalText checks that text marshalled from a field reads back
+// unchanged, and that a game goes on from a pattern unmarshalled into its
+// field rather than from the one it replaced.
+func TestUnmarshalText(t *testing.T) {
+	f := NewLife(37, 11, WithRandom(0.3, nil)).Field()
+	text, err := f.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g Field
+	if err := g.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(liveCells(&g), liveCells(f)) {
+		t.Error("field changed by a text round trip")
+	}
+
+	// A block drawn with four edits, then stepped back into the same field,
+	// is replaced by one unmarshalled with as many.
+	block := func(x, y int) []byte {
+		rows := make([]string, 40)
+		for i := range rows {
+			rows[i] = strings.Repeat(".", 40)
+		}
+		rows[y] = rows[y][:x] + "OO" + rows[y][x+2:]
+		rows[y+1] = rows[y]
+		return []byte(strings.Join(rows, "\n"))
+	}
+	grid := NewLifeFromField(NewField(40, 40))
+	for _, p := range pts(0, 0, 1, 0, 0, 1, 1, 1) {
+		grid.Field().Set(p.X, p.Y, true)
+	}
+	grid.StepN(2)
+	if err := grid.Field().UnmarshalText(block(30, 30)); err != nil {
+		t.Fatal(err)
+	}
+	grid.Step()
+	if got, want := liveCells(grid.Field()), pts(30, 30, 31, 30, 30, 31, 31, 31); !slices.Equal(got, want) {
+		t.Errorf("after unmarshalling a block at 30, 30 and a step, cells %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,42 @@
+package life
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes the coordinates of the live cells of the field to w as
+// CSV, with an "x,y" header row followed by one row per cell.
+func (f *Field) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"x", "y"})
+	f.writeCSV(cw, nil)
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteCSV writes the coordinates of the live cells of the current
+// generation to w as CSV rows of the form "generation,x,y". If header is
+// true the rows are preceded by a header row, so that successive
+// generations can be appended to the same output.
+func (grid *Life) WriteCSV(w io.Writer, header bool) error {
+	cw := csv.NewWriter(w)
+	if header {
+		cw.Write([]string{"generation", "x", "y"})
+	}
+	grid.State().writeCSV(cw, []string{strconv.FormatInt(grid.gen, 10)})
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeCSV writes one row per live cell, each starting with the given
+// leading columns.
+func (f *Field) writeCSV(cw *csv.Writer, lead []string) {
+	row := append(lead, "", "")
+	n := len(lead)
+	for x, y := range f.LiveCells() {
+		row[n], row[n+1] = strconv.Itoa(x), strconv.Itoa(y)
+		cw.Write(row)
+	}
+}
@@ -0,0 +1,30 @@
+package life
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestWriteCSV checks the rows written for a field and for successive
+// generations of a game.
+func TestWriteCSV(t *testing.T) {
+	f := fieldOf(t, Torus, ".O..", "..O.", "OOO.")
+	var b strings.Builder
+	if err := f.WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	if want := "x,y\n1,0\n2,1\n0,2\n1,2\n2,2\n"; b.String() != want {
+		t.Errorf("Field.WriteCSV wrote %q, want %q", b.String(), want)
+	}
+	grid := NewLifeFromField(fieldOf(t, Torus, ".....", "..O..", "..O..", "..O..", "....."))
+	b.Reset()
+	for gen := 0; gen < 2; gen++ {
+		if err := grid.WriteCSV(&b, gen == 0); err != nil {
+			t.Fatal(err)
+		}
+		grid.Step()
+	}
+	if want := "generation,x,y\n0,2,1\n0,2,2\n0,2,3\n1,1,2\n1,2,2\n1,3,2\n"; b.String() != want {
+		t.Errorf("Life.WriteCSV wrote %q, want %q", b.String(), want)
+	}
+}
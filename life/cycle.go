@@ -0,0 +1,101 @@
+package life
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnstable is returned by StepUntilStable when the game neither dies out
+// nor settles into a cycle within the limit.
+var ErrUnstable = errors.New("life: game did not stabilize within the limit")
+
+// cycleWindow is the number of recent generations whose hashes are kept by
+// DetectCycle, which bounds the periods it can find.
+const cycleWindow = 256
+
+// cycles records the hashes of the recent generations of a game for
+// DetectCycle.
+type cycles struct {
+	hashes []uint64 // by generation modulo cycleWindow, nil until DetectCycle is called
+	start  int64    // first generation recorded since the game last changed
+
+	// The conditions under which the last hash was recorded, which must
+	// still hold for the earlier ones to be compared with the next.
+	rule  Rule
+	top   Topology
+	edits int64
+}
+
+// DetectCycle reports whether the current generation repeats one of the
+// recent generations, with the smallest period at which it does. The
+// generations are compared by their hashes, which the game records from
+// the first call on; editing the board or changing its rule or topology
+// starts the record again.
+func (grid *Life) DetectCycle() (period int, ok bool) {
+	c := &grid.cycles
+	if c.hashes == nil {
+		c.hashes = make([]uint64, cycleWindow)
+		c.record(grid, false)
+	} else if !c.current(grid) {
+		c.record(grid, false)
+	}
+	h := c.hashes[slot(grid.gen)]
+	for p := int64(1); p < cycleWindow && grid.gen-p >= c.start; p++ {
+		if c.hashes[slot(grid.gen-p)] == h {
+			return int(p), true
+		}
+	}
+	return 0, false
+}
+
+// StepUntilStable steps the game until every cell is dead, the board
+// repeats a recent generation as found by DetectCycle or maxGen steps have
+// been taken, returning the generation reached and the period of the cycle,
+// which is 0 if the game died out. If the limit is reached, the error is
+// ErrUnstable. A negative limit is an error, as there is always one.
+func (grid *Life) StepUntilStable(maxGen int) (gen int, period int, err error) {
+	if maxGen < 0 {
+		return int(grid.gen), 0, fmt.Errorf("life: invalid generation limit %d", maxGen)
+	}
+	for i := 0; ; i++ {
+		if grid.Population() == 0 {
+			return int(grid.gen), 0, nil
+		}
+		if p, ok := grid.DetectCycle(); ok {
+			return int(grid.gen), p, nil
+		}
+		if i == maxGen {
+			return int(grid.gen), 0, ErrUnstable
+		}
+		grid.Step()
+	}
+}
+
+// current reports whether the hashes recorded still apply to the game, its
+// board not having been edited nor its rule or topology changed since the
+// last was.
+func (c *cycles) current(grid *Life) bool {
+	return c.edits == grid.a.edits && c.rule == grid.a.rule && c.top == grid.a.top
+}
+
+// record records the hash of the current generation of the game, starting
+// the record again from it unless keep is set.
+func (c *cycles) record(grid *Life, keep bool) {
+	if !keep {
+		c.start = grid.gen
+	}
+	c.hashes[slot(grid.gen)] = grid.hash()
+	c.rule, c.top, c.edits = grid.a.rule, grid.a.top, grid.a.edits
+}
+
+// hash returns the hash of the true state of the current generation of the
+// game, without inverting its field, which readers may share.
+func (grid *Life) hash() uint64 {
+	return grid.a.hash(grid.inverted)
+}
+
+// slot returns the index in the hashes of generation gen, which may be
+// negative, as a pattern file can say.
+func slot(gen int64) int {
+	return int((gen%cycleWindow + cycleWindow) % cycleWindow)
+}
@@ -0,0 +1,57 @@
+package life
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// TestHashInverted checks that hashing the complement of a field gives the
+// hash of the inverted field, whatever its size.
+func TestHashInverted(t *testing.T) {
+	for _, size := range [][2]int{{1, 1}, {63, 5}, {64, 64}, {65, 70}, {130, 129}} {
+		f := NewLife(size[0], size[1], WithRandom(0.4, rand.NewSource(4))).Field()
+		c := f.Clone()
+		c.invert()
+		if f.hash(true) != c.Hash() {
+			t.Errorf("%d×%d: hash of the complement differs from that of the inverted field", size[0], size[1])
+		}
+	}
+}
+
+// TestDetectCycle checks the periods found for a few oscillators, including
+// under a B0 rule and from a negative generation.
+func TestDetectCycle(t *testing.T) {
+	for _, tt := range []struct {
+		rle    string
+		period int
+	}{
+		{"x = 3, y = 3\nbo$bo$bo!", 2},
+		{"x = 4, y = 4, rule = B3/S23\n2o$2o$2b2o$2b2o!", 2},
+		{"x = 2, y = 2\n2o$2o!", 1},
+		{"#CXRLE Gen=-5\nx = 3, y = 1\n3o!", 2},
+		{"x = 3, y = 1, rule = B0/S\n3o!", 2},
+	} {
+		p, err := LoadRLE(strings.NewReader(tt.rle))
+		if err != nil {
+			t.Fatal(err)
+		}
+		grid := NewLife(16, 16, WithSeed(p), WithRule(p.Rule()))
+		_, period, err := grid.StepUntilStable(100)
+		if err != nil || period != tt.period {
+			t.Errorf("%q: StepUntilStable gives period %d, %v, want %d", tt.rle, period, err, tt.period)
+		}
+	}
+}
+
+// TestStepUntilStableLimit checks that StepUntilStable stops at its limit
+// and rejects a negative one.
+func TestStepUntilStableLimit(t *testing.T) {
+	grid := NewLife(200, 200, WithRandom(0.3, rand.NewSource(6)))
+	if gen, _, err := grid.StepUntilStable(10); err != ErrUnstable || gen != 10 {
+		t.Errorf("StepUntilStable(10) = %d, %v, want 10, ErrUnstable", gen, err)
+	}
+	if _, _, err := grid.StepUntilStable(-1); err == nil || err == ErrUnstable {
+		t.Errorf("StepUntilStable(-1) gives %v, want an invalid limit", err)
+	}
+}
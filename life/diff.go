@@ -0,0 +1,36 @@
+package life
+
+import "math/bits"
+
+// A CellChange is a cell that changed state between two fields, coming
+// alive if Alive is set and dying otherwise.
+type CellChange struct {
+	X, Y  int
+	Alive bool
+}
+
+// Diff returns the cells whose state differs between f and other, taking f
+// as the earlier state, in order of rows and then of columns, comparing the
+// fields 64 cells at a time. The fields may differ in size, cells beyond
+// the smaller counting as dead.
+func (f *Field) Diff(other *Field) []CellChange {
+	var changes []CellChange
+	for y := 0; y < max(f.h, other.h); y++ {
+		for i := 0; i < max(f.stride, other.stride); i++ {
+			was, is := f.wordAt(i, y), other.wordAt(i, y)
+			for d := was ^ is; d != 0; d &= d - 1 {
+				b := bits.TrailingZeros64(d)
+				changes = append(changes, CellChange{i*64 + b, y, is&(1<<b) != 0})
+			}
+		}
+	}
+	return changes
+}
+
+// wordAt returns word i of row y, or 0 if it is outside the field.
+func (f *Field) wordAt(i, y int) uint64 {
+	if i >= f.stride || y >= f.h {
+		return 0
+	}
+	return f.bits[f.index(i, y)]
+}
@@ -0,0 +1,32 @@
+package life
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestDiff checks the births and deaths between two generations, in order,
+// and between fields of different sizes.
+func TestDiff(t *testing.T) {
+	grid := NewLifeFromField(fieldOf(t, Torus, ".....", ".....", ".OOO.", ".....", "....."))
+	before := grid.State()
+	grid.Step()
+	want := []CellChange{{2, 1, true}, {1, 2, false}, {3, 2, false}, {2, 3, true}}
+	if got := before.Diff(grid.Field()); !slices.Equal(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+	if got := before.Diff(before); got != nil {
+		t.Errorf("Diff of a field with itself = %v", got)
+	}
+
+	// Cells beyond the smaller field count as dead, including past the
+	// first word of a row.
+	wide := NewField(130, 2)
+	wide.Set(129, 1, true)
+	wide.Set(0, 0, true)
+	small := fieldOf(t, Torus, "O.", ".O")
+	want = []CellChange{{1, 1, true}, {129, 1, false}}
+	if got := wide.Diff(small); !slices.Equal(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+}
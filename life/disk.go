@@ -0,0 +1,265 @@
+package life
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"math/bits"
+	"os"
+)
+
+// DiskField is a field of cells evolving under a B/S rule that is kept in
+// files on disk rather than in memory, so that it can be far larger than
+// RAM. Each generation is stored in a file of its own as rows of
+// little-endian words, 64 cells to a word as in a Field, and is stepped a
+// band of rows at a time into the file of the next one, only the band and
+// the rows around it being held in memory.
+type DiskField struct {
+	files    [2]*os.File // the current generation, then the next
+	width, h int
+	stride   int // number of words per row
+	rule     Rule
+	top      Topology
+	band     int   // number of rows stepped at a time
+	gen      int64 // number of steps taken
+
+	src, dst *Field   // the band being stepped, with a row above and below
+	act      activity // for stepWords, never valid
+	buf      []byte   // rows read or written
+}
+
+// NewDiskField returns an empty field of the specified width and height
+// that follows the Conway rule, on a torus, stored in two temporary files
+// created in dir, or the default directory for temporary files if dir is
+// empty. The files are removed by Close.
+func NewDiskField(dir string, width, h int) (*DiskField, error) {
+	if width <= 0 || h <= 0 {
+		return nil, fmt.Errorf("life: invalid field size %d×%d", width, h)
+	}
+	d := &DiskField{width: width, h: h, stride: (width + 63) / 64, rule: Conway, top: Torus}
+	for i := range d.files {
+		f, err := os.CreateTemp(dir, "life-*.bin")
+		if err != nil {
+			d.Close()
+			return nil, err
+		}
+		d.files[i] = f
+		// The file holds zeros up to its size, on most file systems without
+		// taking space until written.
+		if err := f.Truncate(d.offset(h)); err != nil {
+			d.Close()
+			return nil, err
+		}
+	}
+	d.SetBand(256)
+	return d, nil
+}
+
+// Close removes the files of the field.
+func (d *DiskField) Close() error {
+	var errs []error
+	for _, f := range d.files {
+		if f != nil {
+			errs = append(errs, f.Close(), os.Remove(f.Name()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Width returns the width of the field.
+func (d *DiskField) Width() int { return d.width }
+
+// Height returns the height of the field.
+func (d *DiskField) Height() int { return d.h }
+
+// Rule returns the rule the field follows.
+func (d *DiskField) Rule() Rule { return d.rule }
+
+// SetRule sets the rule the field follows.
+func (d *DiskField) SetRule(r Rule) { d.rule = r }
+
+// Topology returns the topology of the field.
+func (d *DiskField) Topology() Topology { return d.top }
+
+// SetTopology sets the topology of the field. Twisted and shifted edges are
+// not supported.
+func (d *DiskField) SetTopology(top Topology) error {
+	if !wordParallel(top) {
+		return fmt.Errorf("life: topology %v is not supported on a disk field", top)
+	}
+	d.top = top
+	return nil
+}
+
+// Generation returns the number of steps taken.
+func (d *DiskField) Generation() int64 { return d.gen }
+
+// SetBand sets the number of rows stepped at a time, 256 by default. The
+// field takes the memory of about three times that many rows.
+func (d *DiskField) SetBand(rows int) {
+	d.band = max(rows, 1)
+	d.src = NewFieldWithTopology(d.width, d.band+2, Topology{X: d.top.X, Y: DeadEdge})
+	d.dst = NewField(d.width, d.band+2)
+	d.act = activity{
+		nextWord: make([]bool, len(d.src.bits)),
+		nextRow:  make([]bool, d.src.h),
+		live:     make([]bool, len(d.src.bits)/tileSize),
+	}
+	d.buf = make([]byte, (d.band+2)*d.stride*8)
+}
+
+// offset returns the offset in the files of row y.
+func (d *DiskField) offset(y int) int64 {
+	return int64(y) * int64(d.stride) * 8
+}
+
+// word returns the offset in the files of the word holding the cell at x,
+// y, which must be in the field.
+func (d *DiskField) word(x, y int) (int64, error) {
+	if x < 0 || x >= d.width || y < 0 || y >= d.h {
+		return 0, fmt.Errorf("life: cell %d, %d outside the %d×%d disk field", x, y, d.width, d.h)
+	}
+	return d.offset(y) + int64(x/64)*8, nil
+}
+
+// Set sets the state of the specified cell, which must be in the field, to
+// the given value.
+func (d *DiskField) Set(x, y int, b bool) error {
+	off, err := d.word(x, y)
+	if err != nil {
+		return err
+	}
+	var w [8]byte
+	if _, err := d.files[0].ReadAt(w[:], off); err != nil {
+		return err
+	}
+	v := binary.LittleEndian.Uint64(w[:])
+	if b {
+		v |= 1 << (x & 63)
+	} else {
+		v &^= 1 << (x & 63)
+	}
+	binary.LittleEndian.PutUint64(w[:], v)
+	_, err = d.files[0].WriteAt(w[:], off)
+	return err
+}
+
+// Alive reports whether the specified cell, which must be in the field, is
+// alive.
+func (d *DiskField) Alive(x, y int) (bool, error) {
+	off, err := d.word(x, y)
+	if err != nil {
+		return false, err
+	}
+	var w [8]byte
+	if _, err := d.files[0].ReadAt(w[:], off); err != nil {
+		return false, err
+	}
+	return binary.LittleEndian.Uint64(w[:])&(1<<(x&63)) != 0, nil
+}
+
+// readRows reads n rows of the current generation from row y into rows dy
+// onwards of f, mapping rows beyond the top and bottom edges as the
+// topology says.
+func (d *DiskField) readRows(f *Field, y, n, dy int) error {
+	for n > 0 {
+		yy, ok, _ := edge(y, d.h, d.top.Y)
+		if !ok || yy != y {
+			// Read rows beyond an edge one at a time.
+			if err := d.readRun(f, yy, ok, 1, dy); err != nil {
+				return err
+			}
+			y, n, dy = y+1, n-1, dy+1
+			continue
+		}
+		k := min(n, d.h-y)
+		if err := d.readRun(f, y, true, k, dy); err != nil {
+			return err
+		}
+		y, n, dy = y+k, n-k, dy+k
+	}
+	return nil
+}
+
+// readRun reads k consecutive rows of the current generation from row y
+// into rows dy onwards of f, or clears them if ok is false.
+func (d *DiskField) readRun(f *Field, y int, ok bool, k, dy int) error {
+	buf := d.buf[:k*d.stride*8]
+	if !ok {
+		clear(buf)
+	} else if _, err := d.files[0].ReadAt(buf, d.offset(y)); err != nil {
+		return err
+	}
+	for j := 0; j < k; j++ {
+		for i := 0; i < d.stride; i++ {
+			f.bits[f.index(i, dy+j)] = binary.LittleEndian.Uint64(buf[(j*d.stride+i)*8:])
+		}
+	}
+	return nil
+}
+
+// Step advances the field by one generation, reading the current one band
+// by band and writing the next to the other file.
+func (d *DiskField) Step() error {
+	d.src.top.X = d.top.X
+	for y0 := 0; y0 < d.h; y0 += d.band {
+		n := min(d.band, d.h-y0)
+		if err := d.readRows(d.src, y0-1, n+2, 0); err != nil {
+			return err
+		}
+		d.act.markLive(d.src)
+		stepWords(d.dst, d.src, d.rule, 1, n+1, &d.act)
+		buf := d.buf[:n*d.stride*8]
+		for j := 0; j < n; j++ {
+			for i := 0; i < d.stride; i++ {
+				binary.LittleEndian.PutUint64(buf[(j*d.stride+i)*8:], d.dst.bits[d.dst.index(i, j+1)])
+			}
+		}
+		if _, err := d.files[1].WriteAt(buf, d.offset(y0)); err != nil {
+			return err
+		}
+	}
+	d.files[0], d.files[1] = d.files[1], d.files[0]
+	d.gen++
+	return nil
+}
+
+// Population returns the number of live cells.
+func (d *DiskField) Population() (int, error) {
+	n := 0
+	for y := 0; y < d.h; y += d.band {
+		buf := d.buf[:min(d.band, d.h-y)*d.stride*8]
+		if _, err := d.files[0].ReadAt(buf, d.offset(y)); err != nil {
+			return 0, err
+		}
+		for i := 0; i < len(buf); i += 8 {
+			n += bits.OnesCount64(binary.LittleEndian.Uint64(buf[i:]))
+		}
+	}
+	return n, nil
+}
+
+// Field returns a copy of the cells of the field within r, which is
+// clipped to the field, as a field with dead edges that follows the rule of
+// the disk field, the cell at r.Min being at 0, 0.
+func (d *DiskField) Field(r image.Rectangle) (*Field, error) {
+	r = r.Intersect(image.Rect(0, 0, d.width, d.h))
+	f := NewFieldWithTopology(r.Dx(), r.Dy(), Plane)
+	f.rule = d.rule
+	if r.Empty() {
+		return f, nil
+	}
+	i0, i1 := r.Min.X/64, (r.Max.X-1)/64+1
+	row := make([]byte, (i1-i0)*8)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		if _, err := d.files[0].ReadAt(row, d.offset(y)+int64(i0)*8); err != nil {
+			return nil, err
+		}
+		for x := r.Min.X; x < r.Max.X; x++ {
+			w := binary.LittleEndian.Uint64(row[(x/64-i0)*8:])
+			f.set(x-r.Min.X, y-r.Min.Y, w&(1<<(x&63)) != 0)
+		}
+	}
+	return f, nil
+}
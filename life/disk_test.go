@@ -0,0 +1,65 @@
+package life
+
+import (
+	"image"
+	"os"
+	"testing"
+)
+
+// TestDiskField checks that a field stepped band by band on disk agrees
+// with the same field stepped in memory, on the torus and the plane, and
+// that Close removes its files.
+func TestDiskField(t *testing.T) {
+	dir := t.TempDir()
+	for _, top := range []Topology{Torus, Plane} {
+		seed := soup("B36/S23")
+		seed.SetTopology(top)
+		d, err := NewDiskField(dir, seed.Width(), seed.Height())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := d.SetTopology(top); err != nil {
+			t.Fatal(err)
+		}
+		d.SetRule(seed.Rule())
+		// A band that does not divide the height leaves a short last band.
+		d.SetBand(7)
+		for x, y := range seed.LiveCells() {
+			if err := d.Set(x, y, true); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want := NewLifeFromField(seed)
+		for gen := 1; gen <= 30; gen++ {
+			want.Step()
+			if err := d.Step(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, err := d.Field(image.Rect(0, 0, d.Width(), d.Height()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Topology() != Plane {
+			t.Errorf("%v: Field has topology %v, want the plane", top, got.Topology())
+		}
+		if !got.Equal(want.Field()) {
+			t.Errorf("%v: disk field differs after %d generations", top, d.Generation())
+		}
+		if n, err := d.Population(); err != nil || n != want.Population() {
+			t.Errorf("%v: Population() = %d, %v, want %d", top, n, err, want.Population())
+		}
+		if alive, err := d.Alive(5, 5); err != nil || alive != want.Alive(5, 5) {
+			t.Errorf("%v: Alive(5, 5) = %v, %v, want %v", top, alive, err, want.Alive(5, 5))
+		}
+		if err := d.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("Close left %d files", len(files))
+	}
+	if _, err := NewDiskField(dir, 0, 5); err == nil {
+		t.Error("NewDiskField(0, 5): no error")
+	}
+}
@@ -0,0 +1,75 @@
+package life
+
+import "bytes"
+
+// Elementary stores the state of a one-dimensional elementary cellular
+// automaton: a row of two-state cells, each of which is updated from its
+// own state and those of its two neighbors. The row wraps around at its
+// ends.
+type Elementary struct {
+	a, b []bool
+	rule uint8 // Wolfram code
+	gen  int64 // number of steps taken
+}
+
+// NewElementary returns an elementary automaton of the given width
+// following the rule with the given Wolfram code, such as 30 or 110, with
+// a single live cell in the middle of the row.
+func NewElementary(width int, rule uint8) *Elementary {
+	e := &Elementary{a: make([]bool, width), b: make([]bool, width), rule: rule}
+	e.a[width/2] = true
+	return e
+}
+
+// Width returns the number of cells of the row.
+func (e *Elementary) Width() int { return len(e.a) }
+
+// Rule returns the Wolfram code of the rule the automaton follows.
+func (e *Elementary) Rule() uint8 { return e.rule }
+
+// Generation returns the number of steps taken.
+func (e *Elementary) Generation() int64 { return e.gen }
+
+// Set sets the state of cell i to the given value.
+func (e *Elementary) Set(i int, b bool) {
+	e.a[i] = b
+}
+
+// Alive reports whether cell i is alive, wrapping i around the ends of the
+// row.
+func (e *Elementary) Alive(i int) bool {
+	return e.a[wrap(i, len(e.a))]
+}
+
+// Step advances the automaton by one instant. Bit n of the rule gives the
+// next state of a cell whose left neighbor, itself and right neighbor,
+// read as a binary number, make n.
+func (e *Elementary) Step() {
+	for i := range e.a {
+		n := 0
+		for _, b := range [3]bool{e.Alive(i - 1), e.a[i], e.Alive(i + 1)} {
+			n <<= 1
+			if b {
+				n |= 1
+			}
+		}
+		e.b[i] = e.rule>>n&1 == 1
+	}
+	e.a, e.b = e.b, e.a
+	e.gen++
+}
+
+// String returns the row as a single line, drawing live cells as '*' and
+// dead ones as spaces.
+func (e *Elementary) String() string {
+	var buf bytes.Buffer
+	for _, b := range e.a {
+		if b {
+			buf.WriteByte('*')
+		} else {
+			buf.WriteByte(' ')
+		}
+	}
+	buf.WriteByte('\n')
+	return buf.String()
+}
@@ -0,0 +1,30 @@
+package life
+
+import "testing"
+
+// TestElementary checks that rule 90 draws the Sierpinski triangle and
+// rule 30 its well-known chaotic rows, across the ends of the row.
+func TestElementary(t *testing.T) {
+	for _, tt := range []struct {
+		rule uint8
+		rows []string
+	}{
+		{90, []string{"    *    \n", "   * *   \n", "  *   *  \n", " * * * * \n", "*       *\n"}},
+		{30, []string{"    *    \n", "   ***   \n", "  **  *  \n", " ** **** \n", "**  *   *\n"}},
+	} {
+		e := NewElementary(9, tt.rule)
+		for gen, want := range tt.rows {
+			if got := e.String(); got != want {
+				t.Errorf("rule %d, generation %d: %q, want %q", tt.rule, gen, got, want)
+			}
+			e.Step()
+		}
+	}
+	e := NewElementary(3, 4)
+	e.Set(1, false)
+	e.Set(2, true)
+	e.Step()
+	if !e.Alive(-1) || e.Alive(0) || e.Generation() != 1 {
+		t.Errorf("rule 4: cells %q at generation %d, want only cell 2", e, e.Generation())
+	}
+}
@@ -0,0 +1,95 @@
+package life
+
+import (
+	"image"
+	"math/rand"
+	"testing"
+)
+
+// An unboundedEngine computes a game on the infinite plane.
+type unboundedEngine interface {
+	Step()
+	Bounds() image.Rectangle
+	Field(r image.Rectangle) *Field
+}
+
+// soup returns a random 96×64 torus following the named rule.
+func soup(rule string) *Field {
+	return NewLife(96, 64, WithRandom(0.3, rand.NewSource(8)), WithRule(MustParseRule(rule))).State()
+}
+
+// TestUnboundedEnginesAgree runs every engine on the infinite plane from the
+// same patterns, as gol -verify does, and checks that they agree with the
+// infinite field.
+func TestUnboundedEnginesAgree(t *testing.T) {
+	unbounded := map[string]func(f *Field) (unboundedEngine, error){
+		"sparse":   func(f *Field) (unboundedEngine, error) { return NewSparseFieldFromField(f) },
+		"hashlife": func(f *Field) (unboundedEngine, error) { return NewHashLifeFromField(f) },
+		"quadtree": func(f *Field) (unboundedEngine, error) { return NewQuadFieldFromField(f) },
+	}
+	for _, rule := range []string{"B3/S23", "B36/S23"} {
+		seed := soup(rule).Crop(image.Rect(0, 0, 24, 24))
+		ref, err := NewInfiniteFromField(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		engines := make(map[string]unboundedEngine)
+		for name, mk := range unbounded {
+			if engines[name], err = mk(seed); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+		}
+		for gen := 1; gen <= 300; gen++ {
+			ref.Step()
+			r := ref.Bounds()
+			want := ref.Field(r)
+			for name, e := range engines {
+				e.Step()
+				if b := e.Bounds(); b != r {
+					t.Fatalf("%s: %s has bounds %v at generation %d, want %v", rule, name, b, gen, r)
+				}
+				if !e.Field(r).Equal(want) {
+					t.Fatalf("%s: %s differs from infinite at generation %d", rule, name, gen)
+				}
+			}
+		}
+	}
+}
+
+// TestBoundedEnginesAgree runs every engine on a torus from the same
+// patterns, as gol -verify does, and checks that they agree with the field.
+func TestBoundedEnginesAgree(t *testing.T) {
+	bounded := map[string]func(f *Field) *Life{
+		"naive": func(f *Field) *Life {
+			g := NewLifeFromField(f)
+			g.SetTransition(f.Rule().Next)
+			return g
+		},
+		"single": func(f *Field) *Life {
+			g := NewLifeFromField(f)
+			g.SetWorkers(1)
+			return g
+		},
+		"parallel": func(f *Field) *Life {
+			g := NewLifeFromField(f)
+			g.SetWorkers(4)
+			return g
+		},
+	}
+	for _, rule := range []string{"B3/S23", "B36/S23", "B2/S"} {
+		ref := NewLifeFromField(soup(rule))
+		games := make(map[string]*Life)
+		for name, mk := range bounded {
+			games[name] = mk(soup(rule))
+		}
+		for gen := 1; gen <= 100; gen++ {
+			ref.Step()
+			for name, g := range games {
+				g.Step()
+				if x, y, ok := firstDiff(trueCells(ref), trueCells(g)); ok {
+					t.Fatalf("%s: %s differs from field at generation %d, cell %d, %d", rule, name, gen, x, y)
+				}
+			}
+		}
+	}
+}
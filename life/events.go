@@ -0,0 +1,40 @@
+package life
+
+import "math/bits"
+
+// OnCellChange makes the game call fn at the end of each step for every
+// cell that changed state, with born set if it came alive, in order of
+// rows and then of columns. The game is then at the new generation. Finding
+// the changes takes a pass over the board per step, 64 cells at a time. A
+// nil function stops the calls.
+func (grid *Life) OnCellChange(fn func(x, y int, born bool)) {
+	grid.onChange = fn
+}
+
+// reportChanges calls the function set by OnCellChange for the cells whose
+// state differs between prev, inverted if wasInverted is set, and the
+// current generation.
+func (grid *Life) reportChanges(prev *Field, wasInverted bool) {
+	a := grid.a
+	var flip uint64 // the words in which every cell changed with the inversion
+	if wasInverted != grid.inverted {
+		flip = ^uint64(0)
+	}
+	for y := 0; y < a.h; y++ {
+		for i := 0; i < a.stride; i++ {
+			k := a.index(i, y)
+			d := prev.bits[k] ^ a.bits[k] ^ flip
+			if i == a.stride-1 {
+				d &= a.tailMask()
+			}
+			is := a.bits[k]
+			if grid.inverted {
+				is = ^is
+			}
+			for ; d != 0; d &= d - 1 {
+				b := bits.TrailingZeros64(d)
+				grid.onChange(i*64+b, y, is&(1<<b) != 0)
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package life
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestOnCellChange checks that the changes reported at each step are those
+// between the true states of the generations, including under a B0 rule
+// whose generations are stored inverted every other step.
+func TestOnCellChange(t *testing.T) {
+	for _, rule := range []string{"B36/S23", "B03/S23"} {
+		grid := NewLifeFromField(soup("B3/S23"))
+		grid.SetRule(MustParseRule(rule))
+		var got []CellChange
+		grid.OnCellChange(func(x, y int, born bool) {
+			got = append(got, CellChange{x, y, born})
+		})
+		inverted := false
+		for gen := 1; gen <= 6; gen++ {
+			inverted = inverted || grid.Inverted()
+			prev := grid.State()
+			got = got[:0]
+			grid.Step()
+			if want := prev.Diff(grid.State()); !slices.Equal(got, want) {
+				t.Fatalf("%s: generation %d: %d changes reported, want %d", rule, gen, len(got), len(want))
+			}
+		}
+		if inverted != MustParseRule(rule).B0() {
+			t.Errorf("%s: inverted %v", rule, inverted)
+		}
+		grid.OnCellChange(nil)
+		got = got[:0]
+		grid.Step()
+		if len(got) != 0 {
+			t.Errorf("%s: %d changes reported after OnCellChange(nil)", rule, len(got))
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package life
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// fetchTimeout bounds the time LoadURL spends on a download when the
+	// context has no earlier deadline.
+	fetchTimeout = 30 * time.Second
+
+	// maxFetchSize is the largest pattern file LoadURL will download.
+	maxFetchSize = 8 << 20
+
+	// maxFetchCells is the largest area of a pattern LoadURL will decode,
+	// so that a few bytes cannot ask for more memory than the largest
+	// download takes.
+	maxFetchCells = 8 * maxFetchSize
+)
+
+// IsURL reports whether name is an http or https URL rather than a file
+// name.
+func IsURL(name string) bool {
+	return strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://")
+}
+
+// LoadURL downloads the pattern file at the given http or https URL, such
+// as a LifeWiki pattern, and decodes it. The format is chosen from the
+// extension of the URL path, or guessed from the contents if the extension
+// is not recognized. Downloads are limited to 8 MiB, before and after
+// decompression, and to 30 seconds, and the patterns they hold to 2^26
+// cells.
+func LoadURL(ctx context.Context, rawURL string) (*Field, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("life: unsupported URL scheme %q", u.Scheme)
+	}
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("life: fetching %s: %s", rawURL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFetchSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFetchSize {
+		return nil, fmt.Errorf("life: %s exceeds %d bytes", rawURL, maxFetchSize)
+	}
+	r, err := decompress(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", rawURL, err)
+	}
+	if data, err = io.ReadAll(io.LimitReader(r, maxFetchSize+1)); err != nil {
+		return nil, fmt.Errorf("%s: %w", rawURL, err)
+	}
+	if len(data) > maxFetchSize {
+		return nil, fmt.Errorf("life: %s exceeds %d bytes once decompressed", rawURL, maxFetchSize)
+	}
+	fm, err := formatForFile(path.Base(u.Path))
+	if err != nil {
+		fm = sniffFormat(data)
+	}
+	f, err := fm.decode(bytes.NewReader(data), maxFetchCells)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", rawURL, err)
+	}
+	return f, nil
+}
+
+// sniffFormat guesses the format of a pattern file from its contents,
+// falling back to RLE.
+func sniffFormat(data []byte) Format {
+	name := "rle"
+	switch {
+	case bytes.HasPrefix(data, []byte(mcHeader)):
+		name = "mc"
+	case bytes.HasPrefix(data, []byte("#Life 1.0")):
+		name = "life106"
+	case bytes.HasPrefix(data, []byte("P1")), bytes.HasPrefix(data, []byte("P4")):
+		name = "pbm"
+	case bytes.HasPrefix(data, []byte("P2")), bytes.HasPrefix(data, []byte("P5")):
+		name = "pgm"
+	case bytes.HasPrefix(data, []byte("!")), bytes.HasPrefix(data, []byte(".")), bytes.HasPrefix(data, []byte("O")):
+		name = "cells"
+	}
+	fm, _ := LookupFormat(name)
+	return fm
+}
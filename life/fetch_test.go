@@ -0,0 +1,79 @@
+package life
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestLoadURL checks that LoadURL decodes plain and gzipped patterns,
+// guessing the format of those without a known extension, and rejects those that only exceed the size cap once decompressed, and tiny
+// files whose headers ask for more cells than the cap.
+func TestLoadURL(t *testing.T) {
+	gz := func(s string) []byte {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		w.Write([]byte(s))
+		w.Close()
+		return buf.Bytes()
+	}
+	glider := "x = 3, y = 3\nbo$2bo$3o!\n"
+	// Trailing text pads the RLE beyond the cap, while compressing to
+	// little; cut at the cap, it would still decode.
+	huge := glider + strings.Repeat("x", maxFetchSize) + "\n"
+	files := map[string][]byte{
+		"/glider.rle":    []byte(glider),
+		"/glider.rle.gz": gz(glider),
+		"/glider":        []byte(glider),
+		"/glider.txt":    []byte(".O.\n..O\nOOO\n"),
+		"/glider.View":   []byte("#Life 1.06\n1 0\n2 1\n0 2\n1 2\n2 2\n"),
+		"/huge.rle.gz":   gz(huge),
+		"/tall.rle":      []byte("x = 100000, y = 100000\no!\n"),
+		"/wide.rle":      []byte("x = 10000, y = 10000\no!\n"),
+		"/wide.lif":      []byte("#Life 1.06\n0 0\n9999 9999\n"),
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	defer srv.Close()
+	for _, name := range []string{"/glider.rle", "/glider.rle.gz", "/glider", "/glider.txt", "/glider.View"} {
+		f, err := LoadURL(context.Background(), srv.URL+name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		} else if f.Population() != 5 {
+			t.Errorf("%s: population %d, want 5", name, f.Population())
+		}
+	}
+	for _, name := range []string{"/huge.rle.gz", "/missing.rle", "/tall.rle", "/wide.rle", "/wide.lif"} {
+		if _, err := LoadURL(context.Background(), srv.URL+name); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+// TestIsURL checks that only http and https URLs are taken for URLs.
+func TestIsURL(t *testing.T) {
+	for name, want := range map[string]bool{
+		"https://conwaylife.com/patterns/glider.rle": true,
+		"http://example.com/glider.rle":              true,
+		"ftp://example.com/glider.rle":               false,
+		"glider.rle":                                 false,
+		"http.rle":                                   false,
+	} {
+		if got := IsURL(name); got != want {
+			t.Errorf("IsURL(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if _, err := LoadURL(context.Background(), "ftp://example.com/glider.rle"); err == nil {
+		t.Error("LoadURL of an ftp URL: no error")
+	}
+}
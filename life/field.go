@@ -0,0 +1,386 @@
+package life
+
+import (
+	"fmt"
+	"image"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// tileSize is the side of the square tiles of cells a Field is stored in.
+const tileSize = 64
+
+// Field represents a two-dimensional field of cells evolving under a rule.
+// Cells are packed 64 to a word and stored in tiles of 64×64 cells, each
+// held in 64 consecutive words, one per row, so that neighboring rows are
+// close in memory; bits beyond the width or height of the field are always
+// zero.
+type Field struct {
+	bits     []uint64 // the tiles in row-major order; see index
+	stride   int      // number of words per row, and of tiles per row of tiles
+	width, h int
+	rule     Rule
+	mask     *MaskRule // rule with a neighbor mask saved in place of rule, or nil
+	top      Topology
+	gen      int64 // generation held by the field, as recorded in pattern files
+	edits    int64 // number of changes made through the exported methods
+	shared   bool  // whether bits is shared with a snapshot, and must be copied before any change
+}
+
+// NewField returns an empty field of the specified width and height that
+// follows the Conway rule.
+func NewField(width, h int) *Field {
+	f := &Field{rule: Conway}
+	f.resize(width, h)
+	return f
+}
+
+// resize makes f an empty field of the given width and height, reusing
+// its words if there are enough of them.
+func (f *Field) resize(width, h int) {
+	f.stride = (width + tileSize - 1) / tileSize
+	n := f.stride * ((h + tileSize - 1) / tileSize) * tileSize
+	if cap(f.bits) >= n && !f.shared {
+		f.bits = f.bits[:n]
+		clear(f.bits)
+	} else {
+		f.bits, f.shared = make([]uint64, n), false
+	}
+	f.width, f.h = width, h
+}
+
+// Width returns the width of the field.
+func (f *Field) Width() int { return f.width }
+
+// Height returns the height of the field.
+func (f *Field) Height() int { return f.h }
+
+// Rule returns the rule the field follows.
+func (f *Field) Rule() Rule { return f.rule }
+
+// SetRule sets the rule the field follows.
+func (f *Field) SetRule(r Rule) { f.rule = r }
+
+// Clear kills every cell of the field.
+func (f *Field) Clear() {
+	f.own()
+	clear(f.bits)
+	f.edits++
+}
+
+// Fill sets every cell of the field to the given value.
+func (f *Field) Fill(b bool) {
+	f.FillRect(0, 0, f.width, f.h, b)
+}
+
+// FillRect sets the cells at x0 ≤ x < x1 and y0 ≤ y < y1 to the given value,
+// 64 at a time. The rectangle is clipped to the field.
+func (f *Field) FillRect(x0, y0, x1, y1 int, b bool) {
+	x0, y0 = max(x0, 0), max(y0, 0)
+	x1, y1 = min(x1, f.width), min(y1, f.h)
+	if x0 >= x1 || y0 >= y1 {
+		return
+	}
+	f.own()
+	for y := y0; y < y1; y++ {
+		for i := x0 / 64; i <= (x1-1)/64; i++ {
+			// The mask of the cells of word i within the rectangle.
+			m := ^uint64(0)
+			if i == x0/64 {
+				m &= ^uint64(0) << (x0 % 64)
+			}
+			if i == (x1-1)/64 && x1%64 != 0 {
+				m &= 1<<(x1%64) - 1
+			}
+			k := f.index(i, y)
+			if b {
+				f.bits[k] |= m
+			} else {
+				f.bits[k] &^= m
+			}
+		}
+	}
+	f.edits++
+}
+
+// Clone returns a copy of the field, sharing nothing with it.
+func (f *Field) Clone() *Field {
+	c := *f
+	c.bits, c.shared = slices.Clone(f.bits), false
+	return &c
+}
+
+// Crop returns a copy of the cells of the field within r, which is clipped
+// to the field, as a field with dead edges that follows the same rule, the
+// cell at r.Min being at 0, 0.
+func (f *Field) Crop(r image.Rectangle) *Field {
+	r = r.Intersect(image.Rect(0, 0, f.width, f.h))
+	c := NewFieldWithTopology(r.Dx(), r.Dy(), Plane)
+	c.rule, c.mask, c.gen = f.rule, f.mask, f.gen
+	for y := 0; y < c.h; y++ {
+		for i := 0; i < c.stride; i++ {
+			c.bits[c.index(i, y)] = f.cellsAt(r.Min.X+i*64, r.Min.Y+y)
+		}
+		if c.stride > 0 {
+			c.bits[c.index(c.stride-1, y)] &= c.tailMask()
+		}
+	}
+	return c
+}
+
+// Trim returns a copy of the smallest rectangle of the field containing
+// every live cell, as Crop does.
+func (f *Field) Trim() *Field {
+	return f.Crop(f.liveBounds())
+}
+
+// cellsAt returns the 64 cells of row y from x on, which must be in the
+// field, bit 0 being the cell at x. Cells beyond the right end of the row
+// are dead.
+func (f *Field) cellsAt(x, y int) uint64 {
+	i, s := x/64, uint(x%64)
+	w := f.bits[f.index(i, y)] >> s
+	if s != 0 && i+1 < f.stride {
+		w |= f.bits[f.index(i+1, y)] << (64 - s)
+	}
+	return w
+}
+
+// Generation returns the generation the field holds, as set by the game
+// holding it or read from a pattern file, or 0 if it is unknown.
+func (f *Field) Generation() int64 { return f.gen }
+
+// SetGeneration sets the generation the field holds.
+func (f *Field) SetGeneration(gen int64) { f.gen = gen }
+
+// Set sets the state of the specified cell to the given value.
+func (f *Field) Set(x, y int, b bool) {
+	f.set(x, y, b)
+	f.edits++
+}
+
+// Toggle flips the state of the specified cell, which must be in the field.
+func (f *Field) Toggle(x, y int) {
+	f.Set(x, y, !f.get(x, y))
+}
+
+// Get reports whether the specified cell is alive, returning an error if it
+// is outside the field rather than mapping it as Alive does.
+func (f *Field) Get(x, y int) (bool, error) {
+	if x < 0 || x >= f.width || y < 0 || y >= f.h {
+		return false, fmt.Errorf("life: cell %d, %d outside the %d×%d field", x, y, f.width, f.h)
+	}
+	return f.get(x, y), nil
+}
+
+// index returns the index in bits of word i of row y, which holds the cells
+// from 64*i to 64*i+63 of the row. Word i of row y+1 follows it in the same
+// tile, and word i+1 is tileSize words further on.
+func (f *Field) index(i, y int) int {
+	return (y/tileSize*f.stride+i)*tileSize + y%tileSize
+}
+
+// get returns the state of the specified cell, which must be in the field.
+func (f *Field) get(x, y int) bool {
+	return f.bits[f.index(x>>6, y)]&(1<<(x&63)) != 0
+}
+
+// set sets the state of the specified cell, which must be in the field.
+func (f *Field) set(x, y int, b bool) {
+	f.own()
+	if b {
+		f.bits[f.index(x>>6, y)] |= 1 << (x & 63)
+	} else {
+		f.bits[f.index(x>>6, y)] &^= 1 << (x & 63)
+	}
+}
+
+// tailMask returns the mask of the bits of the last word of each row that
+// hold cells of the field.
+func (f *Field) tailMask() uint64 {
+	if n := f.width & 63; n != 0 {
+		return 1<<n - 1
+	}
+	return ^uint64(0)
+}
+
+// own gives the field words of its own, copying those it shares with a
+// snapshot, so that they can be changed.
+func (f *Field) own() {
+	if f.shared {
+		f.bits, f.shared = slices.Clone(f.bits), false
+	}
+}
+
+// invert replaces every cell of the field with its complement.
+func (f *Field) invert() {
+	f.own()
+	for y := 0; y < f.h; y++ {
+		for i := 0; i < f.stride; i++ {
+			k := f.index(i, y)
+			f.bits[k] = ^f.bits[k]
+			if i == f.stride-1 {
+				f.bits[k] &= f.tailMask()
+			}
+		}
+	}
+}
+
+// Population returns the number of live cells of the field, counting them
+// 64 at a time.
+func (f *Field) Population() int {
+	n := 0
+	for _, w := range f.bits {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Equal reports whether f and g have the same size and the same live
+// cells, whatever their rules and topologies.
+func (f *Field) Equal(g *Field) bool {
+	return f.width == g.width && f.h == g.h && slices.Equal(f.bits, g.bits)
+}
+
+// Hash returns a 64-bit FNV-1a hash of the size and cells of the field,
+// taking the words of the field as little-endian bytes, so that fields that
+// are Equal have the same hash. It is the same from run to run.
+func (f *Field) Hash() uint64 {
+	return f.hash(false)
+}
+
+// hash returns the hash of the field as Hash does, or that of its
+// complement if invert is set, without changing the field.
+func (f *Field) hash(invert bool) uint64 {
+	const offset, prime = 14695981039346656037, 1099511628211
+	h := uint64(offset)
+	mix := func(w uint64) {
+		for range 8 {
+			h = (h ^ w&0xff) * prime
+			w >>= 8
+		}
+	}
+	mix(uint64(f.width))
+	mix(uint64(f.h))
+	tail := f.tailMask()
+	for k, w := range f.bits {
+		if invert {
+			// Word k is word t%stride of row y, which may be beyond the last.
+			t := k / tileSize
+			switch y := t/f.stride*tileSize + k%tileSize; {
+			case y >= f.h:
+			case t%f.stride == f.stride-1:
+				w ^= tail
+			default:
+				w = ^w
+			}
+		}
+		mix(w)
+	}
+	return h
+}
+
+// LiveCells returns an iterator over the coordinates x, y of the live cells
+// of the field, row by row from the top and from the left within a row. It
+// skips dead cells 64 at a time. The field must not be changed while the
+// iteration is in progress.
+func (f *Field) LiveCells() iter.Seq2[int, int] {
+	return func(yield func(x, y int) bool) {
+		for y := 0; y < f.h; y++ {
+			for i := 0; i < f.stride; i++ {
+				for w := f.bits[f.index(i, y)]; w != 0; w &= w - 1 {
+					if !yield(i*64+bits.TrailingZeros64(w), y) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// Alive reports whether the specified cell is alive.
+// If the x or y coordinates are outside the field boundaries they are mapped
+// as by Wrap, by default wrapping them toroidally. For instance, an x value
+// of -1 is then treated as width-1. Cells beyond dead edges are dead.
+func (f *Field) Alive(x, y int) bool {
+	if x >= 0 && x < f.width && y >= 0 && y < f.h {
+		return f.get(x, y)
+	}
+	x, y, ok := f.Wrap(x, y)
+	return ok && f.get(x, y)
+}
+
+// Wrap maps the coordinates of a cell, which may lie anywhere, to those of
+// the cell of the field they stand for according to its topology. It
+// reports false if the cell lies beyond a dead edge.
+func (f *Field) Wrap(x, y int) (int, int, bool) {
+	// Each crossing of a shifted edge moves the other coordinate.
+	if f.top.ShiftX != 0 && f.top.Y == Wrap {
+		x += floorDiv(y, f.h) * f.top.ShiftX
+	}
+	if f.top.ShiftY != 0 && f.top.X == Wrap {
+		y += floorDiv(x, f.width) * f.top.ShiftY
+	}
+	// Crossing an edge joined with a twist reverses the other coordinate
+	// before it is mapped in turn.
+	x, okx, flip := edge(x, f.width, f.top.X)
+	if flip {
+		y = f.h - 1 - y
+	}
+	y, oky, flip := edge(y, f.h, f.top.Y)
+	if flip {
+		x = f.width - 1 - x
+	}
+	return x, y, okx && oky
+}
+
+// Next returns the state of the specified cell at the next time step.
+func (f *Field) Next(x, y int) bool {
+	// Count the adjacent cells that are alive.
+	alive := Moore(f, x, y)
+	// Return next state according to the rule of the field; under the
+	// Conway rule:
+	//   exactly 3 neighbors: on,
+	//   exactly 2 neighbors: maintain current state,
+	//   otherwise: off.
+	return f.rule.Next(f.Alive(x, y), alive)
+}
+
+// liveBounds returns the smallest rectangle containing every live cell of
+// the field, or an empty rectangle if there are none.
+func (f *Field) liveBounds() image.Rectangle {
+	return f.bounds(false)
+}
+
+// bounds returns the smallest rectangle containing every live cell of the
+// field, or every dead one if dead is true, scanning 64 cells at a time.
+func (f *Field) bounds(dead bool) image.Rectangle {
+	var r image.Rectangle
+	tail := f.tailMask()
+	for y := 0; y < f.h; y++ {
+		first, last := -1, -1
+		var wf, wl uint64
+		for i := 0; i < f.stride; i++ {
+			w := f.bits[f.index(i, y)]
+			if dead {
+				w = ^w
+				if i == f.stride-1 {
+					w &= tail
+				}
+			}
+			if w != 0 {
+				if first < 0 {
+					first, wf = i, w
+				}
+				last, wl = i, w
+			}
+		}
+		if first >= 0 {
+			x0 := first*64 + bits.TrailingZeros64(wf)
+			x1 := last*64 + 64 - bits.LeadingZeros64(wl)
+			r = r.Union(image.Rect(x0, y, x1, y+1))
+		}
+	}
+	return r
+}
@@ -0,0 +1,259 @@
+package life
+
+import (
+	"image"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fieldOf returns a field of the given size and topology holding the cells
+// drawn in rows, with live cells as 'O' and dead ones as '.'.
+func fieldOf(t *testing.T, top Topology, rows ...string) *Field {
+	t.Helper()
+	f := NewFieldWithTopology(len(rows[0]), len(rows), top)
+	for y, row := range rows {
+		for x := range row {
+			f.Set(x, y, row[x] == 'O')
+		}
+	}
+	return f
+}
+
+// liveCells returns the live cells of f.
+func liveCells(f *Field) []image.Point {
+	var cells []image.Point
+	for x, y := range f.LiveCells() {
+		cells = append(cells, image.Pt(x, y))
+	}
+	return cells
+}
+
+// TestStep checks a few oscillators and spaceships on the torus and the
+// plane, against and across their edges.
+func TestStep(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		top   Topology
+		steps int
+		from  []string
+		want  []string
+	}{
+		{"blinker", Torus, 1,
+			[]string{".....", ".....", ".OOO.", ".....", "....."},
+			[]string{".....", "..O..", "..O..", "..O..", "....."}},
+		{"blinker", Plane, 2,
+			[]string{".....", ".....", ".OOO.", ".....", "....."},
+			[]string{".....", ".....", ".OOO.", ".....", "....."}},
+		// Along a dead edge the blinker loses the cell beyond it.
+		{"edge blinker", Plane, 1,
+			[]string{".OOO.", ".....", "....."},
+			[]string{"..O..", "..O..", "....."}},
+		// On a torus of height 3, the cell beyond the edge is on the last row.
+		{"edge blinker", Torus, 1,
+			[]string{".OOO.", ".....", "....."},
+			[]string{"..O..", "..O..", "..O.."}},
+		{"glider", Plane, 4,
+			[]string{".O....", "..O...", "OOO...", "......", "......", "......"},
+			[]string{"......", "..O...", "...O..", ".OOO..", "......", "......"}},
+		// A glider crossing the edges of a torus comes back to where it began.
+		{"glider", Torus, 4 * 6,
+			[]string{".O....", "..O...", "OOO...", "......", "......", "......"},
+			[]string{".O....", "..O...", "OOO...", "......", "......", "......"}},
+		// Against the corner of a plane, a glider becomes a block.
+		{"glider", Plane, 20,
+			[]string{".O...", "..O..", "OOO..", ".....", "....."},
+			[]string{".....", ".....", ".....", "...OO", "...OO"}},
+	} {
+		grid := NewLifeFromField(fieldOf(t, tt.top, tt.from...))
+		grid.StepN(tt.steps)
+		if got, want := liveCells(grid.Field()), liveCells(fieldOf(t, tt.top, tt.want...)); !slices.Equal(got, want) {
+			t.Errorf("%s on %v after %d steps:\n%s", tt.name, tt.top, tt.steps, strings.TrimSuffix(grid.String(), "\n"))
+		}
+	}
+}
+
+// TestFieldNeighbors checks that neighbors are counted across the edges of
+// a torus only.
+func TestFieldNeighbors(t *testing.T) {
+	f := fieldOf(t, Torus, "O...O", ".....", "O...O")
+	if n := f.Neighbors(0, 0); n != 3 {
+		t.Errorf("Neighbors(0, 0) on a torus = %d, want 3", n)
+	}
+	f.SetTopology(Plane)
+	if n := f.Neighbors(0, 0); n != 0 {
+		t.Errorf("Neighbors(0, 0) on a plane = %d, want 0", n)
+	}
+}
+
+// randomField returns a field of the given size in which each cell is alive
+// with probability 1/2, drawing from a source seeded with seed.
+func randomField(width, h int, seed int64) *Field {
+	rng := rand.New(rand.NewSource(seed))
+	f := NewField(width, h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < width; x++ {
+			f.Set(x, y, rng.Intn(2) == 0)
+		}
+	}
+	return f
+}
+
+// TestPopulation checks the population of fields spanning several tiles,
+// and of rows that end within a word.
+func TestPopulation(t *testing.T) {
+	for _, size := range [][2]int{{0, 0}, {1, 1}, {63, 5}, {150, 70}} {
+		f := randomField(size[0], size[1], 1)
+		want := 0
+		for y := 0; y < f.Height(); y++ {
+			for x := 0; x < f.Width(); x++ {
+				want += bool2int(f.Alive(x, y))
+			}
+		}
+		if got := f.Population(); got != want {
+			t.Errorf("%d×%d field: population %d, want %d", size[0], size[1], got, want)
+		}
+	}
+}
+
+// TestLiveCells checks that LiveCells yields the live cells of a field
+// spanning several tiles in order, and stops when asked to.
+func TestLiveCells(t *testing.T) {
+	f := randomField(150, 70, 2)
+	var want []image.Point
+	for y := 0; y < f.Height(); y++ {
+		for x := 0; x < f.Width(); x++ {
+			if f.Alive(x, y) {
+				want = append(want, image.Pt(x, y))
+			}
+		}
+	}
+	if got := liveCells(f); !slices.Equal(got, want) {
+		t.Errorf("LiveCells yielded %d cells, want %d in order", len(got), len(want))
+	}
+	n := 0
+	for range f.LiveCells() {
+		if n++; n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("iteration stopped after %d cells, want 3", n)
+	}
+}
+
+// TestFillRect checks FillRect against setting the cells one by one, for
+// rectangles within a word, across words and beyond the field, as well as
+// Fill and Clear.
+func TestFillRect(t *testing.T) {
+	for _, r := range []image.Rectangle{
+		image.Rect(3, 2, 9, 5),
+		image.Rect(60, 0, 130, 70),
+		image.Rect(64, 10, 128, 11),
+		image.Rect(-5, -5, 200, 3),
+		image.Rect(100, 20, 90, 30),
+	} {
+		for _, b := range []bool{true, false} {
+			f, want := randomField(150, 70, 3), randomField(150, 70, 3)
+			f.FillRect(r.Min.X, r.Min.Y, r.Max.X, r.Max.Y, b)
+			for y := max(r.Min.Y, 0); y < min(r.Max.Y, 70); y++ {
+				for x := max(r.Min.X, 0); x < min(r.Max.X, 150); x++ {
+					want.Set(x, y, b)
+				}
+			}
+			if !f.Equal(want) {
+				t.Errorf("FillRect(%v, %v) differs from setting its cells", r, b)
+			}
+		}
+	}
+	f := randomField(150, 70, 4)
+	if f.Fill(true); f.Population() != 150*70 {
+		t.Errorf("Fill(true): population %d, want %d", f.Population(), 150*70)
+	}
+	if f.Clear(); f.Population() != 0 {
+		t.Errorf("Clear: population %d, want 0", f.Population())
+	}
+}
+
+// TestCrop checks that Crop and Trim copy the cells of a rectangle crossing
+// words and tiles to a field with dead edges of its own.
+func TestCrop(t *testing.T) {
+	f := NewField(150, 70)
+	f.SetRule(MustParseRule("B36/S23"))
+	for _, p := range glider {
+		f.Set(62+p.X, 66+p.Y, true)
+	}
+	trim := f.Trim()
+	if got := liveCells(trim); trim.Width() != 3 || trim.Height() != 3 || !slices.Equal(got, glider) {
+		t.Errorf("Trim: %d×%d field with cells %v", trim.Width(), trim.Height(), got)
+	}
+	if trim.Rule() != f.Rule() || trim.Topology() != Plane {
+		t.Errorf("Trim: rule %v and topology %v, want %v and %v", trim.Rule(), trim.Topology(), f.Rule(), Plane)
+	}
+	c := f.Crop(image.Rect(63, 67, 200, 100))
+	if got := liveCells(c); c.Width() != 87 || c.Height() != 3 || !slices.Equal(got, pts(1, 0, 0, 1, 1, 1)) {
+		t.Errorf("Crop beyond the field: %d×%d field with cells %v", c.Width(), c.Height(), got)
+	}
+	if c := NewField(5, 5).Trim(); c.Width() != 0 || c.Height() != 0 {
+		t.Errorf("Trim of an empty field: %d×%d field", c.Width(), c.Height())
+	}
+}
+
+// TestEqualHash checks that fields are Equal, and hash alike, when their
+// sizes and cells agree, whatever their rules and topologies.
+func TestEqualHash(t *testing.T) {
+	f := fieldOf(t, Torus, ".O..", "..O.", "OOO.")
+	g := fieldOf(t, Plane, ".O..", "..O.", "OOO.")
+	g.SetRule(MustParseRule("B36/S23"))
+	if !f.Equal(g) || f.Hash() != g.Hash() {
+		t.Errorf("fields differing in rule and topology: Equal %v, hashes %#x and %#x", f.Equal(g), f.Hash(), g.Hash())
+	}
+	g.Set(3, 0, true)
+	if f.Equal(g) || f.Hash() == g.Hash() {
+		t.Errorf("fields differing in a cell: Equal %v, hashes %#x and %#x", f.Equal(g), f.Hash(), g.Hash())
+	}
+	if a, b := NewField(3, 4), NewField(4, 3); a.Equal(b) || a.Hash() == b.Hash() {
+		t.Errorf("empty 3×4 and 4×3 fields: Equal %v, hashes %#x and %#x", a.Equal(b), a.Hash(), b.Hash())
+	}
+}
+
+// TestToggleGet checks that Toggle flips cells and that Get rejects cells
+// outside the field, which Alive would map into it.
+func TestToggleGet(t *testing.T) {
+	f := NewField(70, 3)
+	f.Toggle(65, 2)
+	if b, err := f.Get(65, 2); !b || err != nil {
+		t.Errorf("Get(65, 2) after Toggle = %v, %v; want true, nil", b, err)
+	}
+	f.Toggle(65, 2)
+	if b, err := f.Get(65, 2); b || err != nil {
+		t.Errorf("Get(65, 2) after two Toggles = %v, %v; want false, nil", b, err)
+	}
+	f.Set(0, 0, true)
+	for _, p := range pts(-1, 0, 70, 0, 0, 3, 0, -3) {
+		if b, err := f.Get(p.X, p.Y); err == nil {
+			t.Errorf("Get(%d, %d) = %v with no error", p.X, p.Y, b)
+		}
+	}
+}
+
+// TestSetAlive checks that setting and clearing each cell of fields whose
+// rows fill, or end within, their last word changes that cell alone.
+func TestSetAlive(t *testing.T) {
+	for _, width := range []int{1, 63, 64, 65, 130} {
+		f := NewField(width, 3)
+		for y := 0; y < 3; y++ {
+			for x := 0; x < width; x++ {
+				f.Set(x, y, true)
+				if got := liveCells(f); len(got) != 1 || got[0] != image.Pt(x, y) {
+					t.Fatalf("width %d: cells %v after setting %d, %d", width, got, x, y)
+				}
+				f.Set(x, y, false)
+				if f.Alive(x, y) || f.Population() != 0 {
+					t.Fatalf("width %d: cell %d, %d still alive after clearing it", width, x, y)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package life
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// MaxPatternCells is the largest area, in cells, of a pattern that the
+// loaders of this package expand into a field or grid. Larger patterns, and
+// run counts or coordinates reaching past it, are rejected with an error
+// before any cells are allocated.
+const MaxPatternCells = 1 << 28
+
+// checkArea returns an error if a pattern of width by h cells exceeds
+// maxCells cells.
+func checkArea(width, h, maxCells int) error {
+	if width > maxCells || h > maxCells || h > 0 && width > maxCells/h {
+		return fmt.Errorf("life: pattern of %d×%d cells exceeds the limit of %d cells", width, h, maxCells)
+	}
+	return nil
+}
+
+// A Format describes a pattern file format that fields can be loaded from
+// and saved to.
+type Format struct {
+	Name       string   // short name, such as "rle"
+	Extensions []string // file name extensions, such as ".rle"
+	Decode     func(io.Reader) (*Field, error)
+	Encode     func(io.Writer, *Field) error
+
+	decode func(r io.Reader, maxCells int) (*Field, error) // Decode for at most maxCells cells
+}
+
+// formats lists the supported pattern file formats.
+var formats = []Format{
+	{
+		Name:       "rle",
+		Extensions: []string{".rle"},
+		Decode:     LoadRLE,
+		decode:     loadRLE,
+		Encode: func(w io.Writer, f *Field) error {
+			return f.WriteRLE(w)
+		},
+	},
+	{
+		Name:       "cells",
+		Extensions: []string{".cells"},
+		Decode:     LoadCells,
+		decode:     loadCells,
+		Encode: func(w io.Writer, f *Field) error {
+			return f.WriteCells(w)
+		},
+	},
+	{
+		Name:       "life106",
+		Extensions: []string{".lif", ".life"},
+		Decode: func(r io.Reader) (*Field, error) {
+			return loadLife(r, MaxPatternCells)
+		},
+		decode: loadLife,
+		Encode: SaveLife106,
+	},
+	{
+		Name:       "life105",
+		Extensions: []string{".lif", ".life"},
+		Decode: func(r io.Reader) (*Field, error) {
+			return loadLife(r, MaxPatternCells)
+		},
+		decode: loadLife,
+		Encode: func(w io.Writer, f *Field) error {
+			return f.WriteLife105(w)
+		},
+	},
+	{
+		Name:       "mc",
+		Extensions: []string{".mc"},
+		Decode:     LoadMacrocell,
+		decode:     loadMacrocell,
+		Encode: func(w io.Writer, f *Field) error {
+			return f.WriteMacrocell(w)
+		},
+	},
+	{
+		Name:       "pbm",
+		Extensions: []string{".pbm"},
+		Decode:     LoadPBM,
+		decode:     loadPBM,
+		Encode: func(w io.Writer, f *Field) error {
+			return f.WritePBM(w, false)
+		},
+	},
+	{
+		Name:       "pgm",
+		Extensions: []string{".pgm"},
+		Decode:     LoadPBM,
+		decode:     loadPBM,
+		Encode: func(w io.Writer, f *Field) error {
+			return f.WritePGM(w)
+		},
+	},
+}
+
+// Formats returns the supported pattern file formats.
+func Formats() []Format {
+	return append([]Format(nil), formats...)
+}
+
+// LookupFormat returns the format with the given name, or the first format
+// using the given file name extension.
+func LookupFormat(name string) (Format, bool) {
+	for _, fm := range formats {
+		if fm.Name == name {
+			return fm, true
+		}
+	}
+	for _, fm := range formats {
+		for _, ext := range fm.Extensions {
+			if strings.EqualFold(ext, name) {
+				return fm, true
+			}
+		}
+	}
+	return Format{}, false
+}
+
+// formatForFile returns the format matching the extension of the named file,
+// ignoring any ".gz" suffix.
+func formatForFile(name string) (Format, error) {
+	ext := filepath.Ext(strings.TrimSuffix(name, ".gz"))
+	fm, ok := LookupFormat(ext)
+	if !ok || ext == "" {
+		return Format{}, fmt.Errorf("life: unknown pattern format for %s", name)
+	}
+	return fm, nil
+}
+
+// Load reads the pattern stored in the named file, choosing the format from
+// the file name extension. Gzip-compressed files are decompressed
+// transparently; their name may carry an additional ".gz" suffix.
+func Load(name string) (*Field, error) {
+	fm, err := formatForFile(name)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	r, err := decompress(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	f, err := fm.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return f, nil
+}
+
+// Save writes the field to the named file, choosing the format from the
+// file name extension. If the name ends in ".gz" the file is compressed
+// with gzip and the format is chosen from the preceding extension.
+func Save(name string, f *Field) error {
+	fm, err := formatForFile(name)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = file
+	var zw *gzip.Writer
+	if strings.HasSuffix(name, ".gz") {
+		zw = gzip.NewWriter(file)
+		w = zw
+	}
+	err = fm.Encode(w, f)
+	if zw != nil && err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// generationComment returns the comment recording the generation gen in
+// the pattern formats that have no notation of their own for it, after the
+// comment marker of the format, or "" if gen is 0.
+func generationComment(gen int64) string {
+	if gen == 0 {
+		return ""
+	}
+	return "Generation " + strconv.FormatInt(gen, 10)
+}
+
+// parseGenerationComment parses a comment written by generationComment,
+// reporting false if s is any other comment.
+func parseGenerationComment(s string) (int64, bool) {
+	s, ok := strings.CutPrefix(strings.TrimSpace(s), "Generation ")
+	if !ok {
+		return 0, false
+	}
+	gen, err := strconv.ParseInt(s, 10, 64)
+	return gen, err == nil
+}
+
+// gzipMagic is the header that starts every gzip stream.
+const gzipMagic = "\x1f\x8b"
+
+// decompress returns a reader for the contents of r, decompressing them if
+// they start with the gzip header.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(len(gzipMagic)); string(magic) != gzipMagic {
+		return br, nil
+	}
+	return gzip.NewReader(br)
+}
+
+// loadLife reads a .lif file in either of the Life 1.05 and Life 1.06
+// formats, telling them apart by their header line, for patterns of at most
+// maxCells cells.
+func loadLife(r io.Reader, maxCells int) (*Field, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(len(life106Header))
+	if string(head) == life106Header {
+		return loadLife106(br, maxCells)
+	}
+	return loadLife105(br, maxCells)
+}
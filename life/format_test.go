@@ -0,0 +1,252 @@
+package life
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// pts returns the given coordinates as points.
+func pts(xy ...int) []image.Point {
+	var p []image.Point
+	for i := 0; i < len(xy); i += 2 {
+		p = append(p, image.Pt(xy[i], xy[i+1]))
+	}
+	return p
+}
+
+// glider is the cells of a glider heading southeast, row by row.
+var glider = pts(1, 0, 2, 1, 0, 2, 1, 2, 2, 2)
+
+// TestReaders checks the RLE, plaintext, Life 1.06, Life 1.05 and
+// macrocell readers on small files, valid and not.
+func TestReaders(t *testing.T) {
+	for _, tt := range []struct {
+		name, in string
+		w, h     int
+		rule     string
+		gen      int64
+		cells    []image.Point // nil for an invalid file
+	}{
+		{"rle", "x = 3, y = 3\nbo$2bo$3o!\n", 3, 3, "B3/S23", 0, glider},
+		{"rle", "#N Glider\n#CXRLE Pos=0,0 Gen=12\nx = 3, y = 3, rule = B36/S23\nb\no$2bo$3o!", 3, 3, "B36/S23", 12, glider},
+		{"rle", "x = 4, y = 3, rule = 23/3\n4o2$o2bo!", 4, 3, "B3/S23", 0, pts(0, 0, 1, 0, 2, 0, 3, 0, 0, 2, 3, 2)},
+		{"rle", "x = 3, y = 3\nbo$2bo$3o%!", 0, 0, "", 0, nil},
+		{"rle", "x = three, y = 3\nbo!", 0, 0, "", 0, nil},
+		{"rle", "#CXRLE Gen=x\nx = 3, y = 3\nbo!", 0, 0, "", 0, nil},
+		{"rle", "x = 3, y = 3, rule = B9/S\nbo!", 0, 0, "", 0, nil},
+		{"cells", "!Name: Glider\n.O\n..O\nOOO\n", 3, 3, "B3/S23", 0, glider},
+		{"cells", "!Generation 5\r\n*..\r\n\r\n.*\r\n", 3, 3, "B3/S23", 5, pts(0, 0, 1, 2)},
+		{"cells", ".O\n.X\n", 0, 0, "", 0, nil},
+		{"life106", "#Life 1.06\n0 -1\n1 0\n-1 1\n0 1\n1 1\n", 3, 3, "B3/S23", 0, glider},
+		{"life106", "#Life 1.06\n#D Generation 7\n5 5\n", 1, 1, "B3/S23", 7, pts(0, 0)},
+		{"life106", "#Life 1.06\n1 x\n", 0, 0, "", 0, nil},
+		{"life106", "#Life 1.06\n0 0\n2000000000 2000000000\n", 0, 0, "", 0, nil},
+		{"life106", "#Life 1.06\n-9223372036854775808 0\n9223372036854775807 0\n", 0, 0, "", 0, nil},
+		{"life105", "#Life 1.05\n#R 23/36\n#D Generation 3\n#P -1 -1\n.*\n..*\n***\n", 3, 3, "B36/S23", 3, glider},
+		{"life105", "#Life 1.05\n#N\n#P 0 0\n*\n#P 4 2\n**\n", 6, 3, "B3/S23", 0, pts(0, 0, 4, 2, 5, 2)},
+		{"life105", "#Life 1.05\n#P 0 x\n*\n", 0, 0, "", 0, nil},
+		{"life105", "#Life 1.05\n#R 23\n*\n", 0, 0, "", 0, nil},
+		{"life105", "#Life 1.05\n*o\n", 0, 0, "", 0, nil},
+		{"mc", "[M2] (golly 4.0)\n#R B3/S23\n#G 40\n$.*$..*$***$\n", 3, 3, "B3/S23", 40, glider},
+		{"mc", "[M2] (golly 4.0)\n$.*$..*$***$\n4 1 0 0 0\n", 3, 3, "B3/S23", 0, glider},
+		{"mc", "[M2] (golly 4.0)\n4 1 2 0 0\n", 0, 0, "", 0, nil},
+	} {
+		var f *Field
+		var err error
+		r := strings.NewReader(tt.in)
+		switch tt.name {
+		case "rle":
+			f, err = LoadRLE(r)
+		case "cells":
+			f, err = LoadCells(r)
+		case "life106":
+			f, err = LoadLife106(r)
+		case "life105":
+			f, err = LoadLife105(r)
+		case "mc":
+			f, err = LoadMacrocell(r)
+		}
+		if tt.cells == nil {
+			if err == nil {
+				t.Errorf("%s %q: no error", tt.name, tt.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %q: %v", tt.name, tt.in, err)
+			continue
+		}
+		if f.Width() != tt.w || f.Height() != tt.h || f.Rule().String() != tt.rule || f.Generation() != tt.gen {
+			t.Errorf("%s %q: %d×%d %v at generation %d, want %d×%d %v at %d", tt.name, tt.in,
+				f.Width(), f.Height(), f.Rule(), f.Generation(), tt.w, tt.h, tt.rule, tt.gen)
+		}
+		if got := liveCells(f); !slices.Equal(got, tt.cells) {
+			t.Errorf("%s %q: cells %v, want %v", tt.name, tt.in, got, tt.cells)
+		}
+	}
+}
+
+// TestLoadRLELimits checks that patterns whose header, runs or extent reach
+// past MaxPatternCells are rejected before they are expanded.
+func TestLoadRLELimits(t *testing.T) {
+	for _, in := range []string{
+		"x = 1000000000, y = 1000000000\no!",
+		"x = 3, y = 3\n1000000000000G!",
+		"x = 3, y = 3\n99999999999999999999999999o!",
+		"x = 3, y = 3\n" + strings.Repeat("268435456b", 2) + "o!",
+		"x = 3, y = 3\n" + strings.Repeat("16384o$", 16385) + "!",
+	} {
+		if _, err := LoadRLE(strings.NewReader(in)); err == nil {
+			t.Errorf("LoadRLE(%.40q): no error", in)
+		}
+		if _, _, err := LoadRLEGrid(strings.NewReader(in)); err == nil {
+			t.Errorf("LoadRLEGrid(%.40q): no error", in)
+		}
+	}
+	if _, err := LoadRLE(strings.NewReader("x = 1, y = 1, rule = B3/S23:P1000000000,1000000000\no!")); err == nil {
+		t.Error("LoadRLE of a huge bounded grid: no error")
+	}
+}
+
+// TestWritersRoundTrip checks that the patterns written in each format are
+// read back unchanged.
+func TestWritersRoundTrip(t *testing.T) {
+	f := NewLife(150, 70, WithRandom(0.2, nil)).Field().Trim()
+	f.SetRule(MustParseRule("B36/S23"))
+	f.SetGeneration(99)
+	for _, fm := range []struct {
+		name  string
+		write func(f *Field, buf *bytes.Buffer) error
+		load  func(buf *bytes.Buffer) (*Field, error)
+	}{
+		{"rle", func(f *Field, buf *bytes.Buffer) error { return f.WriteRLE(buf) }, func(buf *bytes.Buffer) (*Field, error) { return LoadRLE(buf) }},
+		{"life105", func(f *Field, buf *bytes.Buffer) error { return f.WriteLife105(buf) }, func(buf *bytes.Buffer) (*Field, error) { return LoadLife105(buf) }},
+		{"mc", func(f *Field, buf *bytes.Buffer) error { return f.WriteMacrocell(buf) }, func(buf *bytes.Buffer) (*Field, error) { return LoadMacrocell(buf) }},
+	} {
+		var buf bytes.Buffer
+		if err := fm.write(f, &buf); err != nil {
+			t.Fatal(err)
+		}
+		g, err := fm.load(&buf)
+		if err != nil {
+			t.Fatalf("%s: %v", fm.name, err)
+		}
+		if !slices.Equal(liveCells(g.Trim()), liveCells(f)) || g.Rule() != f.Rule() || g.Generation() != f.Generation() {
+			t.Errorf("%s: pattern changed by a round trip", fm.name)
+		}
+	}
+}
+
+// TestLoadMacrocellHashLife checks that a macrocell pattern spanning far
+// more cells than could be expanded loads as a HashLife universe centered
+// on the origin.
+func TestLoadMacrocellHashLife(t *testing.T) {
+	// Two blocks at opposite corners of a root of level 40.
+	var mc strings.Builder
+	mc.WriteString("[M2] (gol)\n#R B3/S23\n#G 3\n$$$$$$......**$......**$\n")
+	mc.WriteString("**$**$\n")
+	// Each level puts the node holding the northwest block in its northwest
+	// quadrant, and that holding the southeast block in its southeast one.
+	nw, se := 2, 1
+	for id, level := 2, 4; level < 40; id, level = id+2, level+1 {
+		fmt.Fprintf(&mc, "%d %d 0 0 0\n%d 0 0 0 %d\n", level, nw, level, se)
+		nw, se = id+1, id+2
+	}
+	fmt.Fprintf(&mc, "40 %d 0 0 %d\n", nw, se)
+	if _, err := LoadMacrocell(strings.NewReader(mc.String())); err == nil {
+		t.Error("LoadMacrocell expanded a pattern of 2^80 cells")
+	}
+	h, err := LoadMacrocellHashLife(strings.NewReader(mc.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const half = 1 << 39
+	if h.Population() != 8 || h.Generation() != 3 {
+		t.Errorf("population %d at generation %d, want 8 at 3", h.Population(), h.Generation())
+	}
+	if b, want := h.Bounds(), image.Rect(-half, -half, half, half); b != want {
+		t.Errorf("bounds %v, want %v", b, want)
+	}
+	h.Step()
+	if h.Population() != 8 {
+		t.Errorf("population %d after a step, want 8", h.Population())
+	}
+	if f := h.Field(image.Rect(half-4, half-4, half, half)); f.Population() != 4 {
+		t.Errorf("southeast corner holds %d cells, want 4", f.Population())
+	}
+	var buf bytes.Buffer
+	if err := h.WriteMacrocell(&buf); err != nil {
+		t.Fatal(err)
+	}
+	g, err := LoadMacrocellHashLife(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Population() != h.Population() || g.Generation() != h.Generation() || g.Bounds() != h.Bounds() {
+		t.Error("universe changed by a round trip")
+	}
+}
+
+// TestSaveLoad checks that patterns saved in each format, plain or
+// gzip-compressed as their names ask, are loaded back unchanged.
+func TestSaveLoad(t *testing.T) {
+	f := fieldOf(t, Torus, ".O..", "..O.", "OOO.")
+	dir := t.TempDir()
+	for _, fm := range Formats() {
+		for _, name := range []string{"glider" + fm.Extensions[0], "glider" + fm.Extensions[0] + ".gz"} {
+			name = filepath.Join(dir, name)
+			if err := Save(name, f); err != nil {
+				t.Fatalf("Save(%s): %v", name, err)
+			}
+			data, err := os.ReadFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gz := strings.HasSuffix(name, ".gz"); bytes.HasPrefix(data, []byte(gzipMagic)) != gz {
+				t.Errorf("%s: gzip-compressed %v, want %v", name, !gz, gz)
+			}
+			g, err := Load(name)
+			if err != nil {
+				t.Errorf("Load(%s): %v", name, err)
+			} else if !slices.Equal(liveCells(g.Trim()), glider) {
+				t.Errorf("%s: loaded cells %v, want %v", name, liveCells(g.Trim()), glider)
+			}
+		}
+	}
+	for _, name := range []string{"glider", "glider.txt.gz", "glider.gz"} {
+		if err := Save(filepath.Join(dir, name), f); err == nil {
+			t.Errorf("Save(%s): no error", name)
+		}
+	}
+}
+
+// TestFormatsGeneration checks that every format records the generation a
+// field holds, and records none for generation 0.
+func TestFormatsGeneration(t *testing.T) {
+	for _, gen := range []int64{0, 1234} {
+		f := fieldOf(t, Torus, ".O..", "..O.", "OOO.")
+		f.SetGeneration(gen)
+		for _, fm := range Formats() {
+			var buf bytes.Buffer
+			if err := fm.Encode(&buf, f); err != nil {
+				t.Fatalf("%s: %v", fm.Name, err)
+			}
+			out := buf.String()
+			if gen == 0 && strings.Contains(out, "Gen") {
+				t.Errorf("%s: generation 0 recorded in %q", fm.Name, out)
+			}
+			g, err := fm.Decode(&buf)
+			if err != nil {
+				t.Errorf("%s: %v", fm.Name, err)
+			} else if g.Generation() != gen {
+				t.Errorf("%s: generation %d read back from %q, want %d", fm.Name, g.Generation(), out, gen)
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package life
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Generations is a multi-state rule of the Generations family. State 1 is
+// the live state and cells are born and survive as under a Rule, counting
+// only live neighbors; but instead of dying, a live cell moves to state 2
+// and then decays through successive states until it becomes empty again.
+// Decaying cells cannot give birth and are not themselves reborn.
+type Generations struct {
+	Rule   Rule // birth and survival conditions
+	states int
+}
+
+// NewGenerations returns a Generations rule with the given birth and
+// survival conditions and number of states, which must be at least 2.
+// With two states it is equivalent to the two-state rule.
+func NewGenerations(r Rule, states int) (Generations, error) {
+	if states < 2 || states > 256 {
+		return Generations{}, fmt.Errorf("life: invalid number of states %d", states)
+	}
+	return Generations{Rule: r, states: states}, nil
+}
+
+// ParseGenerations parses a Generations rule written either as
+// "B2/S/C3", in which the C part gives the number of states, or in the
+// older "survival/birth/states" notation, such as "/2/3" for Brian's Brain
+// or "345/2/4" for Star Wars.
+func ParseGenerations(s string) (Generations, error) {
+	t := strings.ToUpper(strings.TrimSpace(s))
+	i := strings.LastIndexByte(t, '/')
+	if i < 0 {
+		return Generations{}, fmt.Errorf("life: invalid Generations rule %q", s)
+	}
+	head, n := t[:i], strings.TrimPrefix(strings.TrimPrefix(t[i+1:], "C"), "G")
+	states, err := strconv.Atoi(n)
+	if err != nil {
+		return Generations{}, fmt.Errorf("life: invalid Generations rule %q", s)
+	}
+	r, err := ParseRule(head)
+	if err != nil {
+		return Generations{}, fmt.Errorf("life: invalid Generations rule %q", s)
+	}
+	return NewGenerations(r, states)
+}
+
+// States returns the number of states of the rule.
+func (g Generations) States() int {
+	return g.states
+}
+
+// String returns the rule in B/S/C notation.
+func (g Generations) String() string {
+	return fmt.Sprintf("%v/C%d", g.Rule, g.states)
+}
+
+// Next implements StateRule.
+func (g Generations) Next(m *Grid, x, y int) State {
+	switch s := m.At(x, y); s {
+	case 0:
+		if g.Rule.Next(false, m.Count(x, y, 1)) {
+			return 1
+		}
+		return 0
+	case 1:
+		if g.Rule.Next(true, m.Count(x, y, 1)) {
+			return 1
+		}
+		fallthrough
+	default:
+		if int(s)+1 >= g.states {
+			return 0
+		}
+		return s + 1
+	}
+}
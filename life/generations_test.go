@@ -0,0 +1,50 @@
+package life
+
+import "testing"
+
+// TestParseGenerations checks both notations of Generations rules.
+func TestParseGenerations(t *testing.T) {
+	for _, tt := range []struct{ in, want string }{
+		{"B2/S/C3", "B2/S/C3"},
+		{"/2/3", "B2/S/C3"},
+		{"345/2/4", "B2/S345/C4"},
+		{"b36/s23/g2", "B36/S23/C2"},
+	} {
+		g, err := ParseGenerations(tt.in)
+		if err != nil {
+			t.Errorf("ParseGenerations(%q): %v", tt.in, err)
+		} else if g.String() != tt.want {
+			t.Errorf("ParseGenerations(%q) = %v, want %s", tt.in, g, tt.want)
+		}
+	}
+	for _, in := range []string{"B2", "B2/S/C1", "B2/S/C257", "B9/S/C3", "B2/S/Cx"} {
+		if _, err := ParseGenerations(in); err == nil {
+			t.Errorf("ParseGenerations(%q): no error", in)
+		}
+	}
+}
+
+// TestGenerationsStep checks that dying cells decay through the further
+// states without giving birth, under Brian's Brain.
+func TestGenerationsStep(t *testing.T) {
+	g, err := ParseGenerations("/2/3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid := NewGrid(6, 5)
+	grid.Set(2, 2, 1)
+	grid.Set(3, 2, 1)
+	m := NewAutomatonFromGrid(grid, g)
+	for i, want := range []string{
+		"      \n  **  \n  oo  \n  **  \n      \n",
+		"  **  \n  oo  \n *  * \n  oo  \n  **  \n",
+	} {
+		m.Step()
+		if got := m.String(); got != want {
+			t.Errorf("generation %d:\n%q\nwant\n%q", i+1, got, want)
+		}
+	}
+	if m.Generation() != 2 {
+		t.Errorf("generation %d, want 2", m.Generation())
+	}
+}
@@ -0,0 +1,26 @@
+package life
+
+import (
+	"image"
+	"image/gif"
+	"io"
+)
+
+// WriteGIF records n generations of the game, starting with the current
+// one, and writes them to w as an animated GIF. The delay between frames is
+// given in hundredths of a second. The game is left at the last recorded
+// generation.
+func (grid *Life) WriteGIF(w io.Writer, n, delay int, opt *ImageOptions) error {
+	anim := &gif.GIF{
+		Image: make([]*image.Paletted, 0, n),
+		Delay: make([]int, 0, n),
+	}
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			grid.Step()
+		}
+		anim.Image = append(anim.Image, grid.State().Image(opt))
+		anim.Delay = append(anim.Delay, delay)
+	}
+	return gif.EncodeAll(w, anim)
+}
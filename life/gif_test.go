@@ -0,0 +1,39 @@
+package life
+
+import (
+	"bytes"
+	"image/gif"
+	"slices"
+	"testing"
+)
+
+// TestWriteGIF checks that the frames of a recording are the generations
+// of the game in turn, and that the game is left at the last one.
+func TestWriteGIF(t *testing.T) {
+	grid := NewLifeFromField(fieldOf(t, Torus, "......", ".O....", "..O...", "OOO...", "......"))
+	want := grid.Clone()
+	opt := &ImageOptions{CellSize: 2}
+	var buf bytes.Buffer
+	if err := grid.WriteGIF(&buf, 3, 7, opt); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 3 || !slices.Equal(anim.Delay, []int{7, 7, 7}) {
+		t.Fatalf("%d frames with delays %v, want 3 of 7", len(anim.Image), anim.Delay)
+	}
+	for i, img := range anim.Image {
+		if i > 0 {
+			want.Step()
+		}
+		got, w := liveCells(FieldFromImage(img, 128)), liveCells(FieldFromImage(want.State().Image(opt), 128))
+		if !slices.Equal(got, w) {
+			t.Errorf("frame %d: live pixels %v, want %v", i, got, w)
+		}
+	}
+	if grid.Generation() != 2 {
+		t.Errorf("game left at generation %d, want 2", grid.Generation())
+	}
+}
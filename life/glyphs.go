@@ -0,0 +1,41 @@
+package life
+
+// glyphs are the characters with which a game is rendered as text.
+type glyphs struct {
+	alive, dead rune
+	rowEnd      string // written after each row
+}
+
+// defaultGlyphs are the glyphs of a game unless set otherwise.
+var defaultGlyphs = glyphs{alive: '*', dead: ' ', rowEnd: "\n"}
+
+// SetGlyphs sets the characters with which String and Render show live and
+// dead cells, '*' and a space by default. Block elements such as '█' and
+// '·' make the board easier to read on many terminals.
+func (grid *Life) SetGlyphs(alive, dead rune) {
+	g := grid.ownGlyphs()
+	g.alive, g.dead = alive, dead
+}
+
+// SetRowEnd sets the text with which String and Render end each row, "\n"
+// by default, such as "|\n" to mark the right edge of the board or "\r\n"
+// for a raw terminal.
+func (grid *Life) SetRowEnd(s string) {
+	grid.ownGlyphs().rowEnd = s
+}
+
+// WithGlyphs makes the game show its cells with the given characters, as
+// SetGlyphs does.
+func WithGlyphs(alive, dead rune) Option {
+	return setting(func(grid *Life) { grid.SetGlyphs(alive, dead) })
+}
+
+// ownGlyphs returns the glyphs of the game, giving it a copy of the default
+// ones if it has none of its own.
+func (grid *Life) ownGlyphs() *glyphs {
+	if grid.glyphs == nil {
+		g := defaultGlyphs
+		grid.glyphs = &g
+	}
+	return grid.glyphs
+}
@@ -0,0 +1,28 @@
+package life
+
+import "testing"
+
+// TestGlyphs checks that games are drawn with the glyphs and row ends set
+// on them, and that clones do not share changes to them.
+func TestGlyphs(t *testing.T) {
+	grid := NewLifeFromField(fieldOf(t, Torus, ".O.", "O.."))
+	if got, want := grid.String(), " * \n*  \n"; got != want {
+		t.Errorf("default glyphs: %q, want %q", got, want)
+	}
+	grid.SetGlyphs('█', '·')
+	grid.SetRowEnd("|\r\n")
+	c := grid.Clone()
+	if got, want := grid.String(), "·█·|\r\n█··|\r\n"; got != want {
+		t.Errorf("SetGlyphs: %q, want %q", got, want)
+	}
+	c.SetGlyphs('#', '.')
+	if got, want := c.String(), ".#.|\r\n#..|\r\n"; got != want {
+		t.Errorf("clone: %q, want %q", got, want)
+	}
+	if got, want := grid.String(), "·█·|\r\n█··|\r\n"; got != want {
+		t.Errorf("after changing the clone: %q, want %q", got, want)
+	}
+	if got, want := NewLife(3, 1, WithGlyphs('o', '.'), WithRandom(0, nil)).String(), "...\n"; got != want {
+		t.Errorf("WithGlyphs: %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,506 @@
+package life
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// GollyRule is a multi-state rule loaded from a Golly .rule file, given
+// either as a transition table (@TABLE) or as a rule tree (@TREE). Both
+// the Moore and the von Neumann neighborhoods are supported. Since the next
+// state of each neighborhood is looked up once and then cached, a GollyRule
+// must not be used by several automata at the same time.
+type GollyRule struct {
+	name       string
+	states     int
+	vonNeumann bool
+	permute    bool         // whether the table has permute symmetry
+	table      []transition // in order of precedence
+	tree       [][]int      // nodes, each with one child per state
+	colors     color.Palette
+	cache      map[[9]State]State
+}
+
+// A transition is a line of a rule table: the conditions on the cell and
+// each of its neighbors, in Golly's order, followed by the new state.
+type transition []tableCell
+
+// A tableCell is an entry of a transition: a set of states, which is bound
+// to the same value wherever its variable appears in the transition if it
+// is a variable.
+type tableCell struct {
+	states []State
+	bind   int // variable number, or -1 for a state or inline set
+}
+
+// Golly lists the neighbors of a cell clockwise from the north in rule
+// tables, and in the order NW, NE, SW, SE, N, W, E, S in rule trees.
+var (
+	mooreTable      = [8][2]int{{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}}
+	vonNeumannTable = [4][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+	mooreTree       = [8][2]int{{-1, -1}, {1, -1}, {-1, 1}, {1, 1}, {0, -1}, {-1, 0}, {1, 0}, {0, 1}}
+	vonNeumannTree  = [4][2]int{{0, -1}, {-1, 0}, {1, 0}, {0, 1}}
+)
+
+// LoadGollyRule reads a rule in Golly's .rule format. The rule is taken
+// from the @TABLE section of the file, or else from its @TREE section, and
+// its colors from the @COLORS section, if any. Other sections are ignored.
+func LoadGollyRule(r io.Reader) (*GollyRule, error) {
+	sections := map[string][]string{}
+	var name, section string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+		if line[0] == '@' {
+			section, _, _ = strings.Cut(line, " ")
+			if section == "@RULE" {
+				name = strings.TrimSpace(line[len(section):])
+			}
+			continue
+		}
+		sections[section] = append(sections[section], line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	g := &GollyRule{name: name, cache: map[[9]State]State{}}
+	var err error
+	switch {
+	case sections["@TABLE"] != nil:
+		err = g.parseTable(sections["@TABLE"])
+	case sections["@TREE"] != nil:
+		err = g.parseTree(sections["@TREE"])
+	default:
+		err = fmt.Errorf("no @TABLE or @TREE section")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("life: invalid rule %s: %v", name, err)
+	}
+	g.parseColors(sections["@COLORS"])
+	return g, nil
+}
+
+// parseTable parses the lines of an @TABLE section.
+func (g *GollyRule) parseTable(lines []string) error {
+	vars := map[string]int{}
+	var values [][]State
+	symmetries := "none"
+	for _, line := range lines {
+		if k, v, ok := strings.Cut(line, ":"); ok {
+			k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+			switch k {
+			case "n_states":
+				n, err := strconv.Atoi(v)
+				if err != nil || n < 2 || n > 256 {
+					return fmt.Errorf("invalid n_states %q", v)
+				}
+				g.states = n
+			case "neighborhood":
+				switch v {
+				case "Moore":
+				case "vonNeumann":
+					g.vonNeumann = true
+				default:
+					return fmt.Errorf("unsupported neighborhood %q", v)
+				}
+			case "symmetries":
+				symmetries = v
+			}
+			continue
+		}
+		if g.states == 0 {
+			return fmt.Errorf("missing n_states")
+		}
+		if rest, ok := strings.CutPrefix(line, "var "); ok {
+			k, v, ok := strings.Cut(rest, "=")
+			if !ok {
+				return fmt.Errorf("invalid variable %q", line)
+			}
+			c, err := g.parseCell(strings.TrimSpace(v), vars, values)
+			if err != nil {
+				return err
+			}
+			vars[strings.TrimSpace(k)] = len(values)
+			values = append(values, c.states)
+			continue
+		}
+		t, err := g.parseTransition(line, vars, values)
+		if err != nil {
+			return err
+		}
+		ts, err := g.symmetric(t, symmetries)
+		if err != nil {
+			return err
+		}
+		g.table = append(g.table, ts...)
+	}
+	if g.states == 0 {
+		return fmt.Errorf("missing n_states")
+	}
+	return nil
+}
+
+// parseTransition parses a transition, written either as comma-separated
+// entries or, if every entry is a single digit, as a string of digits.
+func (g *GollyRule) parseTransition(line string, vars map[string]int, values [][]State) (transition, error) {
+	var fields []string
+	if strings.ContainsAny(line, ",{") {
+		depth, start := 0, 0
+		for i := 0; i <= len(line); i++ {
+			switch {
+			case i == len(line) || line[i] == ',' && depth == 0:
+				if f := strings.TrimSpace(line[start:i]); f != "" {
+					fields = append(fields, f)
+				}
+				start = i + 1
+			case line[i] == '{':
+				depth++
+			case line[i] == '}':
+				depth--
+			}
+		}
+	} else {
+		fields = strings.Split(strings.ReplaceAll(line, " ", ""), "")
+	}
+	n := 10
+	if g.vonNeumann {
+		n = 6
+	}
+	if len(fields) != n {
+		return nil, fmt.Errorf("transition %q has %d entries, want %d", line, len(fields), n)
+	}
+	t := make(transition, n)
+	for i, f := range fields {
+		c, err := g.parseCell(f, vars, values)
+		if err != nil {
+			return nil, err
+		}
+		t[i] = c
+	}
+	if out := t[n-1]; out.bind < 0 && len(out.states) != 1 {
+		return nil, fmt.Errorf("transition %q has no single new state", line)
+	}
+	return t, nil
+}
+
+// parseCell parses a table entry: a state, a variable or a set of states
+// and variables in braces.
+func (g *GollyRule) parseCell(s string, vars map[string]int, values [][]State) (tableCell, error) {
+	if v, ok := vars[s]; ok {
+		return tableCell{values[v], v}, nil
+	}
+	if inner, ok := strings.CutPrefix(s, "{"); ok && strings.HasSuffix(inner, "}") {
+		var c tableCell
+		c.bind = -1
+		for _, f := range strings.Split(strings.TrimSuffix(inner, "}"), ",") {
+			e, err := g.parseCell(strings.TrimSpace(f), vars, values)
+			if err != nil {
+				return tableCell{}, err
+			}
+			c.states = append(c.states, e.states...)
+		}
+		return c, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n >= g.states {
+		return tableCell{}, fmt.Errorf("invalid state %q", s)
+	}
+	return tableCell{[]State{State(n)}, -1}, nil
+}
+
+// symmetric returns the distinct transitions obtained from t under the
+// named symmetries, t first.
+func (g *GollyRule) symmetric(t transition, symmetries string) ([]transition, error) {
+	k := len(t) - 2 // number of neighbors
+	rotate := func(r int) []int {
+		p := make([]int, k)
+		for i := range p {
+			p[i] = (i + r) % k
+		}
+		return p
+	}
+	reflect := func(p []int) []int {
+		q := make([]int, k)
+		for i := range q {
+			q[i] = p[(k-i)%k]
+		}
+		return q
+	}
+	var perms [][]int
+	step := k / 4 // rotation by a quarter turn
+	switch symmetries {
+	case "none":
+		perms = [][]int{rotate(0)}
+	case "rotate4":
+		for r := 0; r < k; r += step {
+			perms = append(perms, rotate(r))
+		}
+	case "rotate8":
+		if g.vonNeumann {
+			return nil, fmt.Errorf("rotate8 symmetry needs the Moore neighborhood")
+		}
+		for r := 0; r < k; r++ {
+			perms = append(perms, rotate(r))
+		}
+	case "reflect_horizontal":
+		perms = [][]int{rotate(0), reflect(rotate(0))}
+	case "rotate4reflect":
+		for r := 0; r < k; r += step {
+			perms = append(perms, rotate(r), reflect(rotate(r)))
+		}
+	case "rotate8reflect":
+		if g.vonNeumann {
+			return nil, fmt.Errorf("rotate8reflect symmetry needs the Moore neighborhood")
+		}
+		for r := 0; r < k; r++ {
+			perms = append(perms, rotate(r), reflect(rotate(r)))
+		}
+	case "permute":
+		// Permuted transitions are matched as they are; see accept.
+		g.permute = true
+		return []transition{t}, nil
+	default:
+		return nil, fmt.Errorf("unsupported symmetries %q", symmetries)
+	}
+	var ts []transition
+	seen := map[string]bool{}
+	for _, p := range perms {
+		u := make(transition, len(t))
+		u[0], u[k+1] = t[0], t[k+1]
+		for i, j := range p {
+			u[1+i] = t[1+j]
+		}
+		if key := fmt.Sprint(u); !seen[key] {
+			seen[key] = true
+			ts = append(ts, u)
+		}
+	}
+	return ts, nil
+}
+
+// parseTree parses the lines of an @TREE section. Nodes of level 1 hold
+// new states, and those of each higher level nodes of the level below, up
+// to the root, the last node, whose level is one more than the number of
+// neighbors.
+func (g *GollyRule) parseTree(lines []string) error {
+	neighbors, nodes := 0, 0
+	var levels []int
+	for _, line := range lines {
+		if k, v, ok := strings.Cut(line, "="); ok {
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("invalid %s", line)
+			}
+			switch strings.TrimSpace(k) {
+			case "num_states":
+				g.states = n
+			case "num_neighbors":
+				neighbors = n
+			case "num_nodes":
+				nodes = n
+			}
+			continue
+		}
+		f := strings.Fields(line)
+		if len(f) != g.states+1 {
+			return fmt.Errorf("node %q has %d children, want %d", line, len(f)-1, g.states)
+		}
+		level, err := strconv.Atoi(f[0])
+		if err != nil || level < 1 {
+			return fmt.Errorf("invalid node %q", line)
+		}
+		node := make([]int, g.states)
+		for i, s := range f[1:] {
+			c, err := strconv.Atoi(s)
+			if err != nil || c < 0 || level == 1 && c >= g.states || level > 1 && (c >= len(g.tree) || levels[c] != level-1) {
+				return fmt.Errorf("invalid node %q", line)
+			}
+			node[i] = c
+		}
+		g.tree = append(g.tree, node)
+		levels = append(levels, level)
+	}
+	switch {
+	case g.states < 2 || g.states > 256:
+		return fmt.Errorf("invalid num_states %d", g.states)
+	case neighbors != 4 && neighbors != 8:
+		return fmt.Errorf("unsupported num_neighbors %d", neighbors)
+	case len(g.tree) == 0 || len(g.tree) != nodes:
+		return fmt.Errorf("have %d nodes, want %d", len(g.tree), nodes)
+	case levels[len(levels)-1] != neighbors+1:
+		return fmt.Errorf("root node has level %d, want %d", levels[len(levels)-1], neighbors+1)
+	}
+	g.vonNeumann = neighbors == 4
+	return nil
+}
+
+// parseColors parses the lines of a @COLORS section, each giving a state
+// and its red, green and blue components. Lines in other forms, such as
+// gradients, are ignored.
+func (g *GollyRule) parseColors(lines []string) {
+	if lines == nil {
+		return
+	}
+	p := fadePalette(g.states, color.White, color.Black)
+	for _, line := range lines {
+		f := strings.Fields(line)
+		if len(f) != 4 {
+			continue
+		}
+		var v [4]int
+		ok := true
+		for i := range v {
+			n, err := strconv.Atoi(f[i])
+			ok = ok && err == nil && n >= 0 && n <= 255
+			v[i] = n
+		}
+		if ok && v[0] < g.states {
+			p[v[0]] = color.RGBA{uint8(v[1]), uint8(v[2]), uint8(v[3]), 0xff}
+		}
+	}
+	g.colors = p
+}
+
+// Name returns the name given by the @RULE line of the rule file.
+func (g *GollyRule) Name() string { return g.name }
+
+// String returns the name of the rule.
+func (g *GollyRule) String() string { return g.name }
+
+// States returns the number of states of the rule.
+func (g *GollyRule) States() int { return g.states }
+
+// Palette implements Colorer. It returns nil if the rule file has no
+// colors, leaving the choice of colors to the renderer.
+func (g *GollyRule) Palette() color.Palette { return g.colors }
+
+// Next implements StateRule.
+func (g *GollyRule) Next(m *Grid, x, y int) State {
+	var key [9]State
+	key[0] = m.At(x, y)
+	var offsets [][2]int
+	switch {
+	case g.tree != nil && g.vonNeumann:
+		offsets = vonNeumannTree[:]
+	case g.tree != nil:
+		offsets = mooreTree[:]
+	case g.vonNeumann:
+		offsets = vonNeumannTable[:]
+	default:
+		offsets = mooreTable[:]
+	}
+	for i, d := range offsets {
+		key[1+i] = m.At(x+d[0], y+d[1])
+	}
+	if s, ok := g.cache[key]; ok {
+		return s
+	}
+	s := g.next(key[:1+len(offsets)])
+	g.cache[key] = s
+	return s
+}
+
+// next returns the new state of a cell given its state followed by those
+// of its neighbors, in the order of the table or tree.
+func (g *GollyRule) next(in []State) State {
+	if g.tree != nil {
+		// The tree takes the neighbors first and the cell last.
+		node := len(g.tree) - 1
+		for _, s := range in[1:] {
+			node = g.tree[node][s]
+		}
+		return State(g.tree[node][in[0]])
+	}
+	bound := make([]int, 0, 20)
+	for _, t := range g.table {
+		if s, ok := t.match(in, bound, g.permute); ok {
+			return s
+		}
+	}
+	return in[0]
+}
+
+// match reports whether the transition applies to the given cell and
+// neighbor states, in any order of the neighbors if permute is set, and if
+// so returns the new state. Bound variables are recorded in bound as pairs
+// of variable number and value.
+func (t transition) match(in []State, bound []int, permute bool) (State, bool) {
+	bound, ok := t[0].accept(in[0], bound[:0])
+	if !ok {
+		return 0, false
+	}
+	if permute {
+		bound, ok = t.accept(in[1:], bound, 0)
+	} else {
+		for i := 1; ok && i < len(in); i++ {
+			bound, ok = t[i].accept(in[i], bound)
+		}
+	}
+	if !ok {
+		return 0, false
+	}
+	out := t[len(t)-1]
+	if out.bind < 0 {
+		return out.states[0], true
+	}
+	s, ok := boundValue(bound, out.bind)
+	return s, ok
+}
+
+// accept reports whether the neighbor states in can be matched in some
+// order to the neighbor entries of the transition not in the used mask,
+// and returns the bound variables extended accordingly.
+func (t transition) accept(in []State, bound []int, used uint) ([]int, bool) {
+	if len(in) == 0 {
+		return bound, true
+	}
+	for j := 1; j < len(t)-1; j++ {
+		if bit := uint(1) << j; used&bit == 0 {
+			if b, ok := t[j].accept(in[0], bound); ok {
+				if b, ok = t.accept(in[1:], b, used|bit); ok {
+					return b, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// accept reports whether the entry accepts state s given the bound
+// variables, and returns them extended with the variable of the entry.
+func (c tableCell) accept(s State, bound []int) ([]int, bool) {
+	if c.bind >= 0 {
+		if b, ok := boundValue(bound, c.bind); ok {
+			return bound, b == s
+		}
+	}
+	for _, v := range c.states {
+		if v == s {
+			if c.bind >= 0 {
+				bound = append(bound, c.bind, int(s))
+			}
+			return bound, true
+		}
+	}
+	return bound, false
+}
+
+// boundValue returns the value bound to variable v.
+func boundValue(bound []int, v int) (State, bool) {
+	for i := 0; i < len(bound); i += 2 {
+		if bound[i] == v {
+			return State(bound[i+1]), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,230 @@
+package life
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// Rule tables for Conway's Life and Wireworld, as Golly ships them.
+const (
+	conwayTable = `@RULE ConwayTable
+@TABLE
+n_states:2
+neighborhood:Moore
+symmetries:permute
+var a={0,1}
+var b=a
+var c=a
+var d=a
+var e=a
+var f=a
+var g=a
+var h=a
+0,1,1,1,0,0,0,0,0,1
+1,1,1,0,0,0,0,0,0,1
+1,1,1,1,0,0,0,0,0,1
+1,a,b,c,d,e,f,g,h,0
+@COLORS
+1 255 0 0
+`
+	wireworldTable = `@RULE WireWorldTable
+@TABLE
+n_states:4
+neighborhood:Moore
+symmetries:permute
+var a={0,1,2,3}
+var b=a
+var c=a
+var d=a
+var e=a
+var f=a
+var g=a
+var h=a
+var i={0,2,3}
+var j=i
+var k=i
+var l=i
+var m=i
+var n=i
+var o=i
+1,a,b,c,d,e,f,g,h,2
+2,a,b,c,d,e,f,g,h,3
+3,1,i,j,k,l,m,n,o,1
+3,1,1,i,j,k,l,m,n,1
+`
+)
+
+// totalisticTree returns the @TREE rule file for the two-state totalistic
+// rule r in the Moore neighborhood. The nodes of level l, from 1 to 9,
+// stand for the number n of live neighbors among the 9-l already seen, and
+// are numbered in order of level, then of n.
+func totalisticTree(r Rule) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "@RULE %v\n@TREE\nnum_states=2\nnum_neighbors=8\nnum_nodes=45\n", r)
+	id := func(level, n int) int { return (level-1)*(20-level)/2 + n }
+	for level := 1; level <= 9; level++ {
+		for n := 0; n <= 9-level; n++ {
+			if level == 1 {
+				fmt.Fprintf(&b, "1 %d %d\n", bool2int(r.Next(false, n)), bool2int(r.Next(true, n)))
+			} else {
+				fmt.Fprintf(&b, "%d %d %d\n", level, id(level-1, n), id(level-1, n+1))
+			}
+		}
+	}
+	return b.String()
+}
+
+// bool2int returns 1 for true and 0 for false.
+func bool2int(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// TestGollyRule checks that rules loaded from tables and trees step grids
+// as the rules they describe.
+func TestGollyRule(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		file string
+		rule StateRule // nil for a two-state Life-like rule, checked against life
+		life Rule
+	}{
+		{"Conway table", conwayTable, nil, Conway},
+		{"Conway tree", totalisticTree(Conway), nil, Conway},
+		{"HighLife tree", totalisticTree(MustParseRule("B36/S23")), nil, MustParseRule("B36/S23")},
+		{"Wireworld table", wireworldTable, Wireworld{}, Rule{}},
+	} {
+		g, err := LoadGollyRule(strings.NewReader(tt.file))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		soup := func() *Grid {
+			rng := rand.New(rand.NewSource(4))
+			m := NewGrid(48, 32)
+			for y := 0; y < m.Height(); y++ {
+				for x := 0; x < m.Width(); x++ {
+					m.Set(x, y, State(rng.Intn(g.States())))
+				}
+			}
+			return m
+		}
+		// want returns the state of a cell under the rule described.
+		var want func(x, y int) State
+		var step func()
+		if tt.rule == nil {
+			seed := soup()
+			f := NewField(seed.Width(), seed.Height())
+			for y := 0; y < seed.Height(); y++ {
+				for x := 0; x < seed.Width(); x++ {
+					f.Set(x, y, seed.At(x, y) != 0)
+				}
+			}
+			f.SetRule(tt.life)
+			grid := NewLifeFromField(f)
+			want = func(x, y int) State { return State(bool2int(grid.Alive(x, y))) }
+			step = grid.Step
+		} else {
+			m := NewAutomatonFromGrid(soup(), tt.rule)
+			want = func(x, y int) State { return m.Grid().At(x, y) }
+			step = m.Step
+		}
+		m := NewAutomatonFromGrid(soup(), g)
+	gens:
+		for gen := 1; gen <= 30; gen++ {
+			step()
+			m.Step()
+			for y := 0; y < m.Grid().Height(); y++ {
+				for x := 0; x < m.Grid().Width(); x++ {
+					if s := m.Grid().At(x, y); s != want(x, y) {
+						t.Errorf("%s: generation %d: cell %d, %d in state %d, want %d", tt.name, gen, x, y, s, want(x, y))
+						break gens
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestLoadGollyRuleErrors checks that malformed tables and trees are
+// rejected when they are loaded rather than when they are used.
+func TestLoadGollyRuleErrors(t *testing.T) {
+	tree := func(nodes ...string) string {
+		return fmt.Sprintf("@RULE bad\n@TREE\nnum_states=2\nnum_neighbors=4\nnum_nodes=%d\n%s\n", len(nodes), strings.Join(nodes, "\n"))
+	}
+	table := func(lines ...string) string {
+		return "@RULE bad\n@TABLE\n" + strings.Join(lines, "\n") + "\n"
+	}
+	if _, err := LoadGollyRule(strings.NewReader(tree("1 0 1", "2 0 0", "3 1 1", "4 2 2", "5 3 3"))); err != nil {
+		t.Errorf("valid tree: %v", err)
+	}
+	for _, in := range []string{
+		"@RULE empty\n",
+		tree("1 0 1"),
+		tree("1 0 1", "2 0 0", "3 0 0", "4 2 2", "5 3 3"),
+		tree("1 0 1", "2 0 0", "3 1 1", "4 2 2", "5 3 5"),
+		tree("1 0 2", "2 0 0", "3 1 1", "4 2 2", "5 3 3"),
+		tree("1 0 1", "2 0 0", "3 1 1", "4 2 2", "5 3 3", "6 4 4"),
+		tree("1 0 1 0"),
+		strings.Replace(tree("1 0 1", "2 0 0", "3 1 1", "4 2 2", "5 3 3"), "num_nodes=5", "num_nodes=6", 1),
+		table("neighborhood:Moore", "0,0,0,0,0,0,0,0,0,1"),
+		table("n_states:2", "neighborhood:hex"),
+		table("n_states:2", "0,0,0,0,0,0,0,0,0,2"),
+		table("n_states:2", "0,0,0,0,0,0,0,0,1"),
+		table("n_states:2", "neighborhood:vonNeumann", "symmetries:rotate8", "0,1,0,0,0,1"),
+		table("n_states:2", "symmetries:mirror", "0,0,0,0,0,0,0,0,0,1"),
+		table("n_states:2", "0,{0,1},0,0,0,0,0,0,0,{0,1}"),
+	} {
+		if _, err := LoadGollyRule(strings.NewReader(in)); err == nil {
+			t.Errorf("LoadGollyRule(%q): no error", in)
+		}
+	}
+}
+
+// TestGollyRuleSymmetries checks the number of transitions each symmetry
+// makes of a transition with a single live neighbor.
+func TestGollyRuleSymmetries(t *testing.T) {
+	for _, tt := range []struct {
+		symmetries string
+		vonNeumann bool
+		neighbor   int // index of the live neighbor, from the north clockwise
+		want       int
+	}{
+		{"none", false, 1, 1},
+		{"rotate4", false, 0, 4},
+		{"rotate4", false, 1, 4},
+		{"rotate8", false, 1, 8},
+		{"reflect_horizontal", false, 0, 1},
+		{"reflect_horizontal", false, 1, 2},
+		{"rotate4reflect", false, 1, 4},
+		{"rotate8reflect", false, 1, 8},
+		{"permute", false, 1, 1},
+		{"rotate4", true, 0, 4},
+		{"reflect_horizontal", true, 1, 2},
+	} {
+		g := &GollyRule{states: 2, vonNeumann: tt.vonNeumann}
+		n := 8
+		if tt.vonNeumann {
+			n = 4
+		}
+		cells := make([]string, n+2)
+		for i := range cells {
+			cells[i] = "0"
+		}
+		cells[1+tt.neighbor], cells[n+1] = "1", "1"
+		tr, err := g.parseTransition(strings.Join(cells, ","), nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts, err := g.symmetric(tr, tt.symmetries)
+		if err != nil {
+			t.Errorf("%s: %v", tt.symmetries, err)
+		} else if len(ts) != tt.want {
+			t.Errorf("%s of neighbor %d of %d: %d transitions, want %d", tt.symmetries, tt.neighbor, n, len(ts), tt.want)
+		}
+	}
+}
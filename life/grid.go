@@ -0,0 +1,82 @@
+package life
+
+// State is the state of a cell of a multi-state automaton. State 0 is the
+// empty (dead) state.
+type State uint8
+
+// Grid represents a two-dimensional grid of multi-state cells. It is the
+// multi-state counterpart of Field.
+type Grid struct {
+	s        []State // row-major
+	width, h int
+}
+
+// NewGrid returns a grid of the specified width and height with every cell
+// in state 0.
+func NewGrid(width, h int) *Grid {
+	return &Grid{s: make([]State, width*h), width: width, h: h}
+}
+
+// GridFromField returns a grid of the same size as f with its live cells
+// in state s and all other cells in state 0.
+func GridFromField(f *Field, s State) *Grid {
+	g := NewGrid(f.width, f.h)
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; x++ {
+			if f.get(x, y) {
+				g.s[y*g.width+x] = s
+			}
+		}
+	}
+	return g
+}
+
+// Width returns the width of the grid.
+func (g *Grid) Width() int { return g.width }
+
+// Height returns the height of the grid.
+func (g *Grid) Height() int { return g.h }
+
+// Set sets the state of the specified cell.
+func (g *Grid) Set(x, y int, s State) {
+	g.s[y*g.width+x] = s
+}
+
+// At returns the state of the specified cell. Coordinates outside the grid
+// boundaries are wrapped toroidally, as in Field.Alive.
+func (g *Grid) At(x, y int) State {
+	x, y = g.Wrap(x, y)
+	return g.s[y*g.width+x]
+}
+
+// Wrap maps the coordinates of a cell, which may lie anywhere, into the
+// grid by wrapping them toroidally.
+func (g *Grid) Wrap(x, y int) (int, int) {
+	return wrap(x, g.width), wrap(y, g.h)
+}
+
+// Count returns the number of cells in the Moore neighborhood of the
+// specified cell, not counting the cell itself, that are in state s.
+func (g *Grid) Count(x, y int, s State) int {
+	n := 0
+	for i := -1; i <= 1; i++ {
+		for j := -1; j <= 1; j++ {
+			if (j != 0 || i != 0) && g.At(x+i, y+j) == s {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Census returns the number of cells of the grid in each of the states 0
+// to n-1.
+func (g *Grid) Census(n int) []int {
+	c := make([]int, n)
+	for _, s := range g.s {
+		if int(s) < n {
+			c[s]++
+		}
+	}
+	return c
+}
@@ -0,0 +1,460 @@
+package life
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+)
+
+// A node is a square of 2^level cells on a side in the quadtree of a
+// HashLife universe. Nodes are canonical: two nodes holding the same cells
+// are the same node, so that the results of stepping them can be shared.
+type node struct {
+	nw, ne, sw, se *node // quadrants, nil for the cells of level 0
+	level          uint
+	pop            int64 // number of live cells
+}
+
+// nodeKey identifies a node of level 1 or more by its quadrants.
+type nodeKey [4]*node
+
+// stepKey identifies the result of stepping a node by 2^j generations.
+type stepKey struct {
+	n *node
+	j uint
+}
+
+// maxHashNodes is the number of nodes beyond which a HashLife universe
+// forgets the results it has memoized, to bound its memory use.
+const maxHashNodes = 1 << 22
+
+// HashLife is an unbounded plane of cells evolving under a B/S rule that
+// runs Gosper's HashLife algorithm: the plane is a quadtree of canonical
+// nodes, and the futures of nodes are memoized, so that patterns with much
+// regularity in space and time, such as breeders, can be advanced by huge
+// numbers of generations at once.
+type HashLife struct {
+	root   *node
+	rule   Rule
+	gen    int64 // number of steps taken
+	cells  [2]*node
+	nodes  map[nodeKey]*node
+	empty  []*node // empty node of each level
+	result map[stepKey]*node
+}
+
+// NewHashLife returns an empty HashLife universe that follows the Conway
+// rule.
+func NewHashLife() *HashLife {
+	h := &HashLife{rule: Conway}
+	h.cells = [2]*node{{}, {pop: 1}}
+	h.reset()
+	h.root = h.emptyNode(3)
+	return h
+}
+
+// NewHashLifeFromField returns a HashLife universe whose cells at
+// 0 ≤ x < width and 0 ≤ y < height are those of f and which follows the rule
+// of f.
+func NewHashLifeFromField(f *Field) (*HashLife, error) {
+	h := NewHashLife()
+	if err := h.SetRule(f.rule); err != nil {
+		return nil, err
+	}
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; x++ {
+			if f.get(x, y) {
+				h.Set(x, y, true)
+			}
+		}
+	}
+	return h, nil
+}
+
+// reset forgets every node but the cells and every memoized result.
+func (h *HashLife) reset() {
+	h.nodes = make(map[nodeKey]*node)
+	h.result = make(map[stepKey]*node)
+	h.empty = []*node{h.cells[0]}
+}
+
+// Rule returns the rule the universe follows.
+func (h *HashLife) Rule() Rule { return h.rule }
+
+// SetRule changes the rule the universe follows. Rules with B0 are
+// rejected, as they would bring the whole empty plane to life.
+func (h *HashLife) SetRule(r Rule) error {
+	if r.B0() {
+		return fmt.Errorf("life: rule %v with B0 is not supported by HashLife", r)
+	}
+	if r != h.rule {
+		h.rule = r
+		h.result = make(map[stepKey]*node)
+	}
+	return nil
+}
+
+// Generation returns the number of generations the universe has been
+// advanced by.
+func (h *HashLife) Generation() int64 { return h.gen }
+
+// Population returns the number of live cells.
+func (h *HashLife) Population() int { return int(h.root.pop) }
+
+// join returns the canonical node with the given quadrants.
+func (h *HashLife) join(nw, ne, sw, se *node) *node {
+	k := nodeKey{nw, ne, sw, se}
+	if n, ok := h.nodes[k]; ok {
+		return n
+	}
+	n := &node{nw: nw, ne: ne, sw: sw, se: se, level: nw.level + 1,
+		pop: nw.pop + ne.pop + sw.pop + se.pop}
+	h.nodes[k] = n
+	return n
+}
+
+// emptyNode returns the empty node of the given level.
+func (h *HashLife) emptyNode(level uint) *node {
+	for uint(len(h.empty)) <= level {
+		e := h.empty[len(h.empty)-1]
+		h.empty = append(h.empty, h.join(e, e, e, e))
+	}
+	return h.empty[level]
+}
+
+// half returns half the side of the root, which covers the cells with
+// coordinates from -half to half-1.
+func (h *HashLife) half() int {
+	return 1 << (h.root.level - 1)
+}
+
+// expand doubles the side of the root, keeping it centered on the origin.
+func (h *HashLife) expand() {
+	r := h.root
+	e := h.emptyNode(r.level - 1)
+	h.root = h.join(
+		h.join(e, e, e, r.nw), h.join(e, e, r.ne, e),
+		h.join(e, r.sw, e, e), h.join(r.se, e, e, e))
+}
+
+// Set sets the state of the specified cell to the given value.
+func (h *HashLife) Set(x, y int, b bool) {
+	for x < -h.half() || x >= h.half() || y < -h.half() || y >= h.half() {
+		h.expand()
+	}
+	var set func(n *node, x, y int) *node
+	set = func(n *node, x, y int) *node {
+		if n.level == 0 {
+			if b {
+				return h.cells[1]
+			}
+			return h.cells[0]
+		}
+		// x and y are relative to the top-left corner of n.
+		q := 1 << (n.level - 1)
+		nw, ne, sw, se := n.nw, n.ne, n.sw, n.se
+		switch {
+		case x < q && y < q:
+			nw = set(nw, x, y)
+		case y < q:
+			ne = set(ne, x-q, y)
+		case x < q:
+			sw = set(sw, x, y-q)
+		default:
+			se = set(se, x-q, y-q)
+		}
+		return h.join(nw, ne, sw, se)
+	}
+	h.root = set(h.root, x+h.half(), y+h.half())
+}
+
+// Alive reports whether the specified cell is alive.
+func (h *HashLife) Alive(x, y int) bool {
+	if x < -h.half() || x >= h.half() || y < -h.half() || y >= h.half() {
+		return false
+	}
+	return nodeAlive(h.root, x+h.half(), y+h.half())
+}
+
+// nodeAlive reports whether the cell at x, y from the top-left corner of n,
+// which must lie within it, is alive.
+func nodeAlive(n *node, x, y int) bool {
+	for n.level > 0 && n.pop > 0 {
+		q := 1 << (n.level - 1)
+		switch {
+		case x < q && y < q:
+			n = n.nw
+		case y < q:
+			n, x = n.ne, x-q
+		case x < q:
+			n, y = n.sw, y-q
+		default:
+			n, x, y = n.se, x-q, y-q
+		}
+	}
+	return n.pop > 0
+}
+
+// Bounds returns the smallest rectangle containing every live cell, or an
+// empty rectangle if there are none.
+func (h *HashLife) Bounds() image.Rectangle {
+	if h.root.pop == 0 {
+		return image.Rectangle{}
+	}
+	// Each extreme is found by descending first into the quadrants nearest
+	// to it, memoizing per node as large patterns share many of them.
+	type side int
+	const (
+		left side = iota
+		top
+		right
+		bottom
+	)
+	type key struct {
+		n *node
+		s side
+	}
+	memo := make(map[key]int)
+	var extreme func(n *node, s side) int
+	extreme = func(n *node, s side) int {
+		if n.level == 0 {
+			return 0
+		}
+		k := key{n, s}
+		if v, ok := memo[k]; ok {
+			return v
+		}
+		q := 1 << (n.level - 1)
+		// near holds the quadrants nearest to the side and far the others,
+		// at offsets offNear and offFar along the axis.
+		var near, far [2]*node
+		switch s {
+		case left:
+			near, far = [2]*node{n.nw, n.sw}, [2]*node{n.ne, n.se}
+		case right:
+			near, far = [2]*node{n.ne, n.se}, [2]*node{n.nw, n.sw}
+		case top:
+			near, far = [2]*node{n.nw, n.ne}, [2]*node{n.sw, n.se}
+		case bottom:
+			near, far = [2]*node{n.sw, n.se}, [2]*node{n.nw, n.ne}
+		}
+		farSide, offNear, offFar := s == right || s == bottom, 0, q
+		if farSide {
+			offNear, offFar = q, 0
+		}
+		var v int
+		found := false
+		for _, pass := range []struct {
+			quads [2]*node
+			off   int
+		}{{near, offNear}, {far, offFar}} {
+			for _, c := range pass.quads {
+				if c.pop == 0 {
+					continue
+				}
+				e := extreme(c, s) + pass.off
+				if !found || farSide && e > v || !farSide && e < v {
+					v, found = e, true
+				}
+			}
+			if found {
+				break
+			}
+		}
+		memo[k] = v
+		return v
+	}
+	o := -h.half()
+	return image.Rect(o+extreme(h.root, left), o+extreme(h.root, top),
+		o+extreme(h.root, right)+1, o+extreme(h.root, bottom)+1)
+}
+
+// Field returns a copy of the cells of the universe within r as a field
+// with dead edges that follows the rule of the universe, the cell at r.Min
+// being at 0, 0.
+func (h *HashLife) Field(r image.Rectangle) *Field {
+	f := NewFieldWithTopology(r.Dx(), r.Dy(), Plane)
+	f.rule = h.rule
+	// Only the nodes with live cells within r are descended into, so that
+	// the time taken depends on the cells copied rather than on the area.
+	var walk func(n *node, x, y int)
+	walk = func(n *node, x, y int) {
+		side := 1 << n.level
+		if n.pop == 0 || !r.Overlaps(image.Rect(x, y, x+side, y+side)) {
+			return
+		}
+		if n.level == 0 {
+			f.set(x-r.Min.X, y-r.Min.Y, true)
+			return
+		}
+		q := side / 2
+		walk(n.nw, x, y)
+		walk(n.ne, x+q, y)
+		walk(n.sw, x, y+q)
+		walk(n.se, x+q, y+q)
+	}
+	walk(h.root, -h.half(), -h.half())
+	return f
+}
+
+// Step advances the universe by one generation.
+func (h *HashLife) Step() {
+	h.stepPow2(0)
+}
+
+// Advance advances the universe by n generations, in steps of powers of
+// two, each of which takes time depending on the regularity of the pattern
+// rather than on the number of generations.
+func (h *HashLife) Advance(n int64) {
+	for j := uint(0); n > 0; j++ {
+		if n&1 != 0 {
+			h.stepPow2(j)
+		}
+		n >>= 1
+	}
+}
+
+// stepPow2 advances the universe by 2^j generations.
+func (h *HashLife) stepPow2(j uint) {
+	// The pattern must lie in the central square of a quarter of the side
+	// of a root of level at least j+3 for the result, the central half of
+	// the root, to hold it.
+	for h.root.level < j+3 || !h.centered() {
+		h.expand()
+	}
+	if len(h.nodes) > maxHashNodes {
+		h.compact()
+	}
+	// The result is centered like the root, with half its side.
+	h.root = h.successor(h.root, j)
+	for h.root.level < 3 {
+		h.expand()
+	}
+	h.gen += 1 << j
+}
+
+// centered reports whether every live cell of the root lies in the central
+// square of a quarter of its side.
+func (h *HashLife) centered() bool {
+	r := h.root
+	return r.nw.se.se.pop+r.ne.sw.sw.pop+r.sw.ne.ne.pop+r.se.nw.nw.pop == r.pop
+}
+
+// compact forgets the memoized results and every node but those of the
+// current root.
+func (h *HashLife) compact() {
+	h.reset()
+	var keep func(n *node)
+	keep = func(n *node) {
+		k := nodeKey{n.nw, n.ne, n.sw, n.se}
+		if _, ok := h.nodes[k]; ok || n.level == 0 {
+			return
+		}
+		h.nodes[k] = n
+		keep(n.nw)
+		keep(n.ne)
+		keep(n.sw)
+		keep(n.se)
+	}
+	keep(h.root)
+}
+
+// successor returns the central half of n advanced by 2^j generations,
+// where n has a level of at least 2 and j is at most its level minus 2.
+func (h *HashLife) successor(n *node, j uint) *node {
+	if n.pop == 0 {
+		return h.emptyNode(n.level - 1)
+	}
+	k := stepKey{n, j}
+	if r, ok := h.result[k]; ok {
+		return r
+	}
+	var r *node
+	if n.level == 2 {
+		r = h.base(n)
+	} else {
+		// The nine overlapping squares of half the side of n, centered
+		// on a 3×3 grid.
+		n00, n01, n02 := n.nw, h.join(n.nw.ne, n.ne.nw, n.nw.se, n.ne.sw), n.ne
+		n10 := h.join(n.nw.sw, n.nw.se, n.sw.nw, n.sw.ne)
+		n11 := h.join(n.nw.se, n.ne.sw, n.sw.ne, n.se.nw)
+		n12 := h.join(n.ne.sw, n.ne.se, n.se.nw, n.se.ne)
+		n20, n21, n22 := n.sw, h.join(n.sw.ne, n.se.nw, n.sw.se, n.se.sw), n.se
+		var adv func(*node) *node
+		if j == n.level-2 {
+			// Full speed: each stage advances by half the total.
+			adv = func(m *node) *node { return h.successor(m, j-1) }
+		} else {
+			// Only the last stage advances; the first just centers.
+			adv = h.center
+		}
+		c00, c01, c02 := adv(n00), adv(n01), adv(n02)
+		c10, c11, c12 := adv(n10), adv(n11), adv(n12)
+		c20, c21, c22 := adv(n20), adv(n21), adv(n22)
+		jj := j
+		if j == n.level-2 {
+			jj = j - 1
+		}
+		r = h.join(
+			h.successor(h.join(c00, c01, c10, c11), jj),
+			h.successor(h.join(c01, c02, c11, c12), jj),
+			h.successor(h.join(c10, c11, c20, c21), jj),
+			h.successor(h.join(c11, c12, c21, c22), jj))
+	}
+	h.result[k] = r
+	return r
+}
+
+// center returns the central half of n.
+func (h *HashLife) center(n *node) *node {
+	return h.join(n.nw.se, n.ne.sw, n.sw.ne, n.se.nw)
+}
+
+// base returns the central 2×2 cells of the 4×4 node n after one
+// generation.
+func (h *HashLife) base(n *node) *node {
+	var cells [4][4]bool
+	for i, q := range [4]*node{n.nw, n.ne, n.sw, n.se} {
+		x0, y0 := i%2*2, i/2*2
+		cells[y0][x0] = q.nw.pop > 0
+		cells[y0][x0+1] = q.ne.pop > 0
+		cells[y0+1][x0] = q.sw.pop > 0
+		cells[y0+1][x0+1] = q.se.pop > 0
+	}
+	var out [4]*node
+	for i := range out {
+		x, y := 1+i%2, 1+i/2
+		alive := 0
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if (dx != 0 || dy != 0) && cells[y+dy][x+dx] {
+					alive++
+				}
+			}
+		}
+		if h.rule.Next(cells[y][x], alive) {
+			out[i] = h.cells[1]
+		} else {
+			out[i] = h.cells[0]
+		}
+	}
+	return h.join(out[0], out[1], out[2], out[3])
+}
+
+// String returns the smallest rectangle of the universe containing every
+// live cell as a string, in the format of Life.String.
+func (h *HashLife) String() string {
+	r := h.Bounds()
+	var buf bytes.Buffer
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			b := byte(' ')
+			if h.Alive(x, y) {
+				b = '*'
+			}
+			buf.WriteByte(b)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
@@ -0,0 +1,79 @@
+package life
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// HexRule is an outer-totalistic rule on the hexagonal grid, written like a
+// B/S rule with an 'H' suffix, such as "B2/S34H". The hexagonal grid is
+// emulated on the square one, as in Golly: the six neighbors of a cell are
+// its Moore neighbors other than the top-right and bottom-left ones, which
+// amounts to shearing the grid so that each row sits half a cell to the
+// right of the row below it. HexRule implements Stepper.
+type HexRule struct {
+	Rule Rule // birth and survival conditions on 0 to 6 neighbors
+}
+
+// hexOffsets are the offsets of the neighbors of a cell on the hexagonal
+// grid.
+var hexOffsets = [6][2]int{{-1, -1}, {0, -1}, {-1, 0}, {1, 0}, {0, 1}, {1, 1}}
+
+// ParseHexRule parses a hexagonal rule such as "B2/S34H".
+func ParseHexRule(s string) (HexRule, error) {
+	t := strings.TrimSpace(s)
+	if !strings.HasSuffix(t, "H") && !strings.HasSuffix(t, "h") {
+		return HexRule{}, fmt.Errorf("life: invalid hexagonal rule %q", s)
+	}
+	r, err := ParseRule(t[:len(t)-1])
+	if err != nil {
+		return HexRule{}, fmt.Errorf("life: invalid hexagonal rule %q", s)
+	}
+	if (r.birth|r.survival)>>7 != 0 {
+		return HexRule{}, fmt.Errorf("life: hexagonal rule %q uses more than 6 neighbors", s)
+	}
+	return HexRule{Rule: r}, nil
+}
+
+// String returns the rule in B/S notation with the 'H' suffix.
+func (r HexRule) String() string {
+	return r.Rule.String() + "H"
+}
+
+// Step implements Stepper.
+func (r HexRule) Step(dst, src *Field) {
+	for y := 0; y < src.h; y++ {
+		for x := 0; x < src.width; x++ {
+			n := 0
+			for _, d := range hexOffsets {
+				if src.Alive(x+d[0], y+d[1]) {
+					n++
+				}
+			}
+			dst.set(x, y, r.Rule.Next(src.get(x, y), n))
+		}
+	}
+}
+
+// HexString returns the field drawn as a hexagonal grid, matching the
+// neighborhood used by HexRule: cells are two characters apart and each
+// row is indented one character more than the row below it.
+func (f *Field) HexString() string {
+	var buf bytes.Buffer
+	for y := 0; y < f.h; y++ {
+		buf.Write(bytes.Repeat([]byte{' '}, f.h-1-y))
+		for x := 0; x < f.width; x++ {
+			b := byte('.')
+			if f.get(x, y) {
+				b = '*'
+			}
+			if x > 0 {
+				buf.WriteByte(' ')
+			}
+			buf.WriteByte(b)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
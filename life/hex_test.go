@@ -0,0 +1,49 @@
+package life
+
+import "testing"
+
+// TestParseHexRule checks the notation of hexagonal rules.
+func TestParseHexRule(t *testing.T) {
+	r, err := ParseHexRule("b2/s34h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.String() != "B2/S34H" {
+		t.Errorf("String() = %q, want B2/S34H", r)
+	}
+	for _, in := range []string{"B2/S34", "B2/S7H", "B2x/S34H", "H"} {
+		if _, err := ParseHexRule(in); err == nil {
+			t.Errorf("ParseHexRule(%q): no error", in)
+		}
+	}
+}
+
+// TestHexRuleStep checks that the top-right and bottom-left cells are not
+// neighbors on the hexagonal grid, and how HexString shears the rows.
+func TestHexRuleStep(t *testing.T) {
+	r, err := ParseHexRule("B1/SH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		rows []string
+		born bool
+	}{
+		{[]string{"O..", "...", "..."}, true},
+		{[]string{".O.", "...", "..."}, true},
+		{[]string{"..O", "...", "..."}, false},
+		{[]string{"...", "O..", "..."}, true},
+		{[]string{"...", "...", "O.."}, false},
+		{[]string{"...", "...", "..O"}, true},
+	} {
+		src := fieldOf(t, Plane, tt.rows...)
+		dst := NewFieldWithTopology(3, 3, Plane)
+		r.Step(dst, src)
+		if dst.Alive(1, 1) != tt.born {
+			t.Errorf("%v: center born %v, want %v", tt.rows, dst.Alive(1, 1), tt.born)
+		}
+	}
+	if got, want := fieldOf(t, Torus, "O..", ".O.").HexString(), " * . .\n. * .\n"; got != want {
+		t.Errorf("HexString() = %q, want %q", got, want)
+	}
+}
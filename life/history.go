@@ -0,0 +1,62 @@
+package life
+
+// history is a ring of the generations before the current one of a game,
+// for StepBack. Each is kept as a snapshot of the field of the game, which
+// costs a copy of the field per step.
+type history struct {
+	ring []past // nil when none are kept
+	next int    // index in ring of the next generation recorded
+	n    int    // number of generations recorded
+}
+
+// A past is a generation kept in the history of a game.
+type past struct {
+	snap     *Snapshot
+	inverted bool // whether the snapshot holds the complement of the true state
+}
+
+// SetHistory makes the game keep up to n generations before the current
+// one, so that StepBack can return to them. The game keeps none by
+// default. Changing the number discards those kept.
+func (grid *Life) SetHistory(n int) {
+	grid.history = history{}
+	if n > 0 {
+		grid.history.ring = make([]past, n)
+	}
+}
+
+// StepBack returns the game to the previous generation, reporting whether
+// it was kept. The rule and topology of the game are left as they are,
+// including scheduled rule changes already made.
+func (grid *Life) StepBack() bool {
+	h := &grid.history
+	if h.n == 0 {
+		return false
+	}
+	h.n--
+	h.next = (h.next + len(h.ring) - 1) % len(h.ring)
+	p := h.ring[h.next]
+	h.ring[h.next] = past{}
+	f := p.snap.Field()
+	f.rule, f.mask, f.top, f.edits = grid.a.rule, grid.a.mask, grid.a.top, grid.a.edits+1
+	*grid.a = *f
+	grid.gen, grid.inverted = f.gen, p.inverted
+	grid.act.valid = false
+	return true
+}
+
+// record keeps the current generation of the game in its history.
+func (h *history) record(grid *Life) {
+	if h.ring == nil {
+		return
+	}
+	h.ring[h.next] = past{grid.a.Snapshot(), grid.inverted}
+	h.next = (h.next + 1) % len(h.ring)
+	h.n = min(h.n+1, len(h.ring))
+}
+
+// clear discards the generations kept.
+func (h *history) clear() {
+	clear(h.ring)
+	h.next, h.n = 0, 0
+}
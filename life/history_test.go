@@ -0,0 +1,39 @@
+package life
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestStepBack checks that StepBack returns to the generations kept, true
+// states and all under a B0 rule, and reports when none are left.
+func TestStepBack(t *testing.T) {
+	for _, rule := range []string{"B3/S23", "B0123478/S01234678"} {
+		grid := NewLife(32, 24, WithRandom(0.4, rand.NewSource(2)), WithRule(MustParseRule(rule)))
+		if grid.StepBack() {
+			t.Errorf("%s: StepBack with no history reported a generation", rule)
+		}
+		grid.SetHistory(3)
+		var states []*Field
+		for gen := 0; gen < 5; gen++ {
+			states = append(states, grid.State())
+			grid.Step()
+		}
+		for gen := int64(4); gen >= 2; gen-- {
+			if !grid.StepBack() {
+				t.Fatalf("%s: StepBack to generation %d reported none kept", rule, gen)
+			}
+			if grid.Generation() != gen || !grid.State().Equal(states[gen]) {
+				t.Errorf("%s: StepBack reached generation %d with population %d, want the state of generation %d", rule, grid.Generation(), grid.Population(), gen)
+			}
+		}
+		if grid.StepBack() {
+			t.Errorf("%s: StepBack beyond the 3 generations kept reported one", rule)
+		}
+		grid.Step()
+		grid.Step()
+		if !grid.StepBack() || !grid.StepBack() || !grid.State().Equal(states[2]) {
+			t.Errorf("%s: stepping on from generation 2 and back did not return to it", rule)
+		}
+	}
+}
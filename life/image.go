@@ -0,0 +1,82 @@
+package life
+
+import (
+	"image"
+	"image/color"
+)
+
+// ImageOptions controls how a field is rendered as an image.
+type ImageOptions struct {
+	CellSize int         // side of each cell in pixels; 0 means 1
+	Alive    color.Color // color of live cells; nil means black
+	Dead     color.Color // color of dead cells; nil means white
+}
+
+// palette returns the two-color palette described by the options, with
+// dead cells at index 0 and live cells at index 1.
+func (opt *ImageOptions) palette() color.Palette {
+	p := color.Palette{color.White, color.Black}
+	if opt != nil && opt.Dead != nil {
+		p[0] = opt.Dead
+	}
+	if opt != nil && opt.Alive != nil {
+		p[1] = opt.Alive
+	}
+	return p
+}
+
+// cellSize returns the side of each cell in pixels.
+func (opt *ImageOptions) cellSize() int {
+	if opt == nil || opt.CellSize < 1 {
+		return 1
+	}
+	return opt.CellSize
+}
+
+// Image renders the field as a paletted image. A nil opt uses one pixel per
+// cell, black for live cells and white for dead ones.
+func (f *Field) Image(opt *ImageOptions) *image.Paletted {
+	n := opt.cellSize()
+	img := image.NewPaletted(image.Rect(0, 0, f.width*n, f.h*n), opt.palette())
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; x++ {
+			if !f.get(x, y) {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				row := img.Pix[img.PixOffset(x*n, y*n+j):]
+				for i := 0; i < n; i++ {
+					row[i] = 1
+				}
+			}
+		}
+	}
+	return img
+}
+
+// Image returns the current generation rendered with the default
+// ImageOptions.
+func (grid *Life) Image() image.Image {
+	return grid.State().Image(nil)
+}
+
+// FieldFromImage returns a field with one cell per pixel of img, in which a
+// cell is alive if the luminance of its pixel is below threshold; dark
+// pixels thus become live cells.
+func FieldFromImage(img image.Image, threshold uint8) *Field {
+	r := img.Bounds()
+	f := NewField(r.Dx(), r.Dy())
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; x++ {
+			g := color.GrayModel.Convert(img.At(r.Min.X+x, r.Min.Y+y)).(color.Gray)
+			f.set(x, y, g.Y < threshold)
+		}
+	}
+	return f
+}
+
+// NewLifeFromImage returns a new Life game whose initial state is the field
+// converted from img by FieldFromImage.
+func NewLifeFromImage(img image.Image, threshold uint8) *Life {
+	return NewLifeFromField(FieldFromImage(img, threshold))
+}
@@ -0,0 +1,54 @@
+package life
+
+import (
+	"image"
+	"image/color"
+	"slices"
+	"testing"
+)
+
+// TestImage checks the cell size and colors of rendered fields, and that
+// FieldFromImage reads them back.
+func TestImage(t *testing.T) {
+	f := fieldOf(t, Torus, ".O..", "..O.", "OOO.")
+	red := color.RGBA{255, 0, 0, 255}
+	img := f.Image(&ImageOptions{CellSize: 3, Alive: red})
+	if b := img.Bounds(); b.Dx() != 12 || b.Dy() != 9 {
+		t.Fatalf("image of %v, want 12×9", b)
+	}
+	for _, p := range []struct {
+		x, y int
+		want color.Color
+	}{{3, 0, red}, {5, 2, red}, {6, 2, color.White}, {0, 0, color.White}, {2, 8, red}} {
+		if got := img.At(p.x, p.y); got != p.want {
+			t.Errorf("pixel %d, %d is %v, want %v", p.x, p.y, got, p.want)
+		}
+	}
+	if got := liveCells(FieldFromImage(f.Image(nil), 128)); !slices.Equal(got, glider) {
+		t.Errorf("FieldFromImage: cells %v, want %v", got, glider)
+	}
+}
+
+// TestNewLifeFromImage checks that cells come alive where the luminance of
+// their pixels is below the threshold, in images not anchored at the origin.
+func TestNewLifeFromImage(t *testing.T) {
+	img := image.NewGray(image.Rect(10, 20, 14, 22))
+	for x := 0; x < 4; x++ {
+		img.SetGray(10+x, 20, color.Gray{uint8(x * 80)})
+		img.SetGray(10+x, 21, color.Gray{255 - uint8(x*80)})
+	}
+	for _, tt := range []struct {
+		threshold uint8
+		want      []image.Point
+	}{
+		{0, nil},
+		{80, pts(0, 0, 3, 1)},
+		{81, pts(0, 0, 1, 0, 3, 1)},
+		{255, pts(0, 0, 1, 0, 2, 0, 3, 0, 1, 1, 2, 1, 3, 1)},
+	} {
+		grid := NewLifeFromImage(img, tt.threshold)
+		if got := liveCells(grid.Field()); grid.Field().Width() != 4 || grid.Field().Height() != 2 || !slices.Equal(got, tt.want) {
+			t.Errorf("threshold %d: %d×%d board with cells %v, want 4×2 with %v", tt.threshold, grid.Field().Width(), grid.Field().Height(), got, tt.want)
+		}
+	}
+}
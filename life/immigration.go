@@ -0,0 +1,223 @@
+package life
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// Immigration is the two-color variant of a two-state rule: every live
+// cell is in state 1 or 2, its color, and cells are born and survive as
+// under the rule, counting live neighbors of either color. Survivors keep
+// their color and newborns take the majority color of their live
+// neighbors, which under the Conway rule are exactly three parents.
+type Immigration struct {
+	Rule Rule // birth and survival conditions
+}
+
+// States returns the number of states of the rule.
+func (Immigration) States() int { return 3 }
+
+// Colors returns the number of colors of live cells.
+func (Immigration) Colors() int { return 2 }
+
+// String returns the name of the rule.
+func (im Immigration) String() string {
+	if im.Rule == Conway {
+		return "Immigration"
+	}
+	return "Immigration " + im.Rule.String()
+}
+
+// Next implements StateRule. Should there be as many live neighbors of
+// each color, as can happen under rules other than Conway's, the newborn
+// takes color 1.
+func (im Immigration) Next(g *Grid, x, y int) State {
+	s := g.At(x, y)
+	n1, n2 := g.Count(x, y, 1), g.Count(x, y, 2)
+	if !im.Rule.Next(s != 0, n1+n2) {
+		return 0
+	}
+	if s != 0 {
+		return s
+	}
+	if n2 > n1 {
+		return 2
+	}
+	return 1
+}
+
+// Palette implements Colorer, drawing the two colors red and blue on
+// black.
+func (Immigration) Palette() color.Palette {
+	return append(color.Palette(nil), teamColors[:3]...)
+}
+
+// QuadLife is the four-color variant of a two-state rule: like
+// Immigration, but with live cells in states 1 to 4. Newborns take the
+// majority color of their live neighbors or, when these are all of
+// different colors, as three parents are under the Conway rule, the color
+// none of them has.
+type QuadLife struct {
+	Rule Rule // birth and survival conditions
+}
+
+// States returns the number of states of the rule.
+func (QuadLife) States() int { return 5 }
+
+// Colors returns the number of colors of live cells.
+func (QuadLife) Colors() int { return 4 }
+
+// String returns the name of the rule.
+func (q QuadLife) String() string {
+	if q.Rule == Conway {
+		return "QuadLife"
+	}
+	return "QuadLife " + q.Rule.String()
+}
+
+// Next implements StateRule. Remaining ties between colors, which can
+// only arise under rules other than Conway's, go to the lowest color.
+func (q QuadLife) Next(g *Grid, x, y int) State {
+	s := g.At(x, y)
+	var n [5]int
+	for i := -1; i <= 1; i++ {
+		for j := -1; j <= 1; j++ {
+			if i != 0 || j != 0 {
+				n[g.At(x+i, y+j)]++
+			}
+		}
+	}
+	live := n[1] + n[2] + n[3] + n[4]
+	if !q.Rule.Next(s != 0, live) {
+		return 0
+	}
+	if s != 0 {
+		return s
+	}
+	best, missing, distinct := State(1), State(0), 0
+	for c := State(1); c <= 4; c++ {
+		switch {
+		case n[c] == 0:
+			missing = c
+		case n[c] > n[best]:
+			best = c
+		}
+		if n[c] > 0 {
+			distinct++
+		}
+	}
+	if live == 3 && distinct == 3 {
+		return missing
+	}
+	return best
+}
+
+// Palette implements Colorer, drawing the four colors red, blue, yellow
+// and green on black.
+func (QuadLife) Palette() color.Palette {
+	return append(color.Palette(nil), teamColors...)
+}
+
+// teamColors are the colors of the colored variants of Life.
+var teamColors = color.Palette{
+	color.Black,
+	color.RGBA{0xff, 0x40, 0x40, 0xff},
+	color.RGBA{0x40, 0x80, 0xff, 0xff},
+	color.RGBA{0xff, 0xd0, 0x20, 0xff},
+	color.RGBA{0x40, 0xd0, 0x40, 0xff},
+}
+
+// Species is the variant of a two-state rule for any number of competing
+// species: every live cell belongs to one of n species, in states 1 to n,
+// and cells are born and survive as under the rule, counting live
+// neighbors of every species. Survivors keep their species and newborns
+// join the species most of their live neighbors belong to, ties going to
+// the lowest-numbered species.
+type Species struct {
+	Rule Rule // birth and survival conditions
+	n    int
+}
+
+// NewSpecies returns the variant of r for n species, which must be between
+// 1 and 255.
+func NewSpecies(r Rule, n int) (Species, error) {
+	if n < 1 || n > 255 {
+		return Species{}, fmt.Errorf("life: invalid number of species %d", n)
+	}
+	return Species{Rule: r, n: n}, nil
+}
+
+// States returns the number of states of the rule.
+func (sp Species) States() int { return sp.n + 1 }
+
+// Colors returns the number of species.
+func (sp Species) Colors() int { return sp.n }
+
+// String returns the rule followed by the number of species.
+func (sp Species) String() string {
+	return fmt.Sprintf("%v with %d species", sp.Rule, sp.n)
+}
+
+// Next implements StateRule.
+func (sp Species) Next(g *Grid, x, y int) State {
+	s := g.At(x, y)
+	var n [9]State // species of the live neighbors
+	live := 0
+	for i := -1; i <= 1; i++ {
+		for j := -1; j <= 1; j++ {
+			if t := g.At(x+i, y+j); (i != 0 || j != 0) && t != 0 {
+				n[live] = t
+				live++
+			}
+		}
+	}
+	if !sp.Rule.Next(s != 0, live) {
+		return 0
+	}
+	if s != 0 {
+		return s
+	}
+	best, count := State(0), 0
+	for _, t := range n[:live] {
+		c := 0
+		for _, u := range n[:live] {
+			if u == t {
+				c++
+			}
+		}
+		if c > count || c == count && t < best {
+			best, count = t, c
+		}
+	}
+	return best
+}
+
+// Palette implements Colorer, drawing the first four species as
+// QuadLife does and further ones in hues spread around the color wheel.
+func (sp Species) Palette() color.Palette {
+	p := append(color.Palette(nil), teamColors[:min(sp.n+1, len(teamColors))]...)
+	for s := len(p); s <= sp.n; s++ {
+		p = append(p, hue((float64(s-len(teamColors))+0.5)/float64(sp.n+1-len(teamColors))))
+	}
+	return p
+}
+
+// hue returns the fully saturated color with the given hue, from 0 to 1.
+func hue(h float64) color.Color {
+	h = math.Mod(h*6, 6)
+	x := uint8(255 * (1 - math.Abs(math.Mod(h, 2)-1)))
+	switch int(h) {
+	case 0:
+		return color.RGBA{0xff, x, 0, 0xff}
+	case 1:
+		return color.RGBA{x, 0xff, 0, 0xff}
+	case 2:
+		return color.RGBA{0, 0xff, x, 0xff}
+	case 3:
+		return color.RGBA{0, x, 0xff, 0xff}
+	case 4:
+		return color.RGBA{x, 0, 0xff, 0xff}
+	}
+	return color.RGBA{0xff, 0, x, 0xff}
+}
@@ -0,0 +1,115 @@
+package life
+
+import "testing"
+
+// gridOf returns a grid holding the states drawn in rows, one digit per
+// cell, with '.' for state 0.
+func gridOf(rows ...string) *Grid {
+	g := NewGrid(len(rows[0]), len(rows))
+	for y, row := range rows {
+		for x := range row {
+			if row[x] != '.' {
+				g.Set(x, y, State(row[x]-'0'))
+			}
+		}
+	}
+	return g
+}
+
+// TestImmigration checks that newborns take the majority color of their
+// parents while survivors keep theirs, and that the colors together step
+// as the two-state rule.
+func TestImmigration(t *testing.T) {
+	im := Immigration{Rule: Conway}
+	for _, tt := range []struct {
+		rows []string
+		want State
+	}{
+		{[]string{".....", ".1.2.", ".....", "..2..", "....."}, 2},
+		{[]string{".....", ".1.1.", ".....", "..2..", "....."}, 1},
+		{[]string{".....", ".2.1.", "..2..", "..1..", "....."}, 2},
+		{[]string{".....", ".1...", "..2..", "..1..", "....."}, 2},
+		{[]string{".....", ".....", "..2..", "..1..", "....."}, 0},
+	} {
+		if got := im.Next(gridOf(tt.rows...), 2, 2); got != tt.want {
+			t.Errorf("%v: center in state %d, want %d", tt.rows, got, tt.want)
+		}
+	}
+	if s := (Immigration{Rule: MustParseRule("B36/S23")}).String(); s != "Immigration B36/S23" {
+		t.Errorf("String() = %q", s)
+	}
+
+	g := gridOf("..1.....", "...2....", ".121....", "........", "........", "........")
+	m := NewAutomatonFromGrid(g, im)
+	want := NewLifeFromField(fieldOf(t, Torus, "..O.....", "...O....", ".OOO....", "........", "........", "........"))
+	for gen := 1; gen <= 24; gen++ {
+		m.Step()
+		want.Step()
+		for y := 0; y < 6; y++ {
+			for x := 0; x < 8; x++ {
+				if (m.Grid().At(x, y) != 0) != want.Alive(x, y) {
+					t.Fatalf("generation %d: cell %d, %d differs from the Conway rule", gen, x, y)
+				}
+			}
+		}
+	}
+}
+
+// TestQuadLife checks that newborns of three differently colored parents
+// take the fourth color, and otherwise that of the majority.
+func TestQuadLife(t *testing.T) {
+	q := QuadLife{Rule: Conway}
+	for _, tt := range []struct {
+		rows []string
+		want State
+	}{
+		{[]string{".....", ".1.2.", ".....", "..3..", "....."}, 4},
+		{[]string{".....", ".4.2.", ".....", "..1..", "....."}, 3},
+		{[]string{".....", ".3.2.", ".....", "..3..", "....."}, 3},
+		{[]string{".....", ".1.1.", ".....", "..2..", "....."}, 1},
+		{[]string{".....", ".1.2.", "..4..", "..3..", "....."}, 4},
+		{[]string{".....", ".1.2.", "..4..", ".....", "....."}, 4},
+		{[]string{".....", ".1.2.", "..43.", "..3..", "....."}, 0},
+	} {
+		if got := q.Next(gridOf(tt.rows...), 2, 2); got != tt.want {
+			t.Errorf("%v: center in state %d, want %d", tt.rows, got, tt.want)
+		}
+	}
+	if len(q.Palette()) != q.States() || q.String() != "QuadLife" {
+		t.Errorf("QuadLife has %d colors for %d states and name %q", len(q.Palette()), q.States(), q)
+	}
+}
+
+// TestSpecies checks that newborns join the majority species of their
+// parents, ties going to the lowest-numbered, and the number of species
+// accepted.
+func TestSpecies(t *testing.T) {
+	sp, err := NewSpecies(MustParseRule("B36/S23"), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		rows []string
+		want State
+	}{
+		{[]string{".....", ".7.5.", ".....", "..7..", "....."}, 7},
+		{[]string{".....", ".7.5.", ".....", "..6..", "....."}, 5},
+		{[]string{".....", ".7.5.", ".6.6.", ".55..", "....."}, 5},
+		{[]string{".....", ".7.7.", ".6.6.", ".54..", "....."}, 6},
+	} {
+		if got := sp.Next(gridOf(tt.rows...), 2, 2); got != tt.want {
+			t.Errorf("%v: center in state %d, want %d", tt.rows, got, tt.want)
+		}
+	}
+	if len(sp.Palette()) != sp.States() || sp.Colors() != 7 {
+		t.Errorf("%d colors for %d states", len(sp.Palette()), sp.States())
+	}
+	if c := gridOf("7.5", "..7").Census(sp.States()); c[7] != 2 || c[5] != 1 || c[0] != 3 {
+		t.Errorf("Census() = %v", c)
+	}
+	for _, n := range []int{0, 256} {
+		if _, err := NewSpecies(Conway, n); err == nil {
+			t.Errorf("NewSpecies(%d): no error", n)
+		}
+	}
+}
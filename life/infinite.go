@@ -0,0 +1,203 @@
+package life
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+)
+
+// chunkSize is the side of the square chunks of cells an Infinite plane
+// is made of.
+const chunkSize = 32
+
+// chunk holds the cells of a square of an Infinite plane, indexed [y][x].
+type chunk [chunkSize][chunkSize]bool
+
+// Infinite is an unbounded plane of cells evolving under a B/S rule. Only
+// the chunks of the plane that hold live cells are stored, and chunks are
+// added as live cells reach them, so patterns such as spaceships travel
+// forever instead of wrapping around.
+type Infinite struct {
+	chunks map[image.Point]*chunk // by chunk coordinates
+	rule   Rule
+	gen    int64 // number of steps taken
+}
+
+// NewInfinite returns an empty plane that follows the Conway rule.
+func NewInfinite() *Infinite {
+	return &Infinite{chunks: make(map[image.Point]*chunk), rule: Conway}
+}
+
+// NewInfiniteFromField returns a plane whose cells at 0 ≤ x < width and
+// 0 ≤ y < height are those of f and which follows the rule of f.
+func NewInfiniteFromField(f *Field) (*Infinite, error) {
+	p := NewInfinite()
+	if err := p.SetRule(f.rule); err != nil {
+		return nil, err
+	}
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; x++ {
+			if f.get(x, y) {
+				p.Set(x, y, true)
+			}
+		}
+	}
+	return p, nil
+}
+
+// Rule returns the rule the plane follows.
+func (p *Infinite) Rule() Rule { return p.rule }
+
+// SetRule changes the rule the plane follows. Rules with B0 are rejected,
+// as they would bring the whole empty plane to life.
+func (p *Infinite) SetRule(r Rule) error {
+	if r.B0() {
+		return fmt.Errorf("life: rule %v with B0 is not supported on an infinite plane", r)
+	}
+	p.rule = r
+	return nil
+}
+
+// Generation returns the number of steps taken.
+func (p *Infinite) Generation() int64 { return p.gen }
+
+// chunkOf returns the coordinates of the chunk holding the cell at x, y and
+// those of the cell within it.
+func chunkOf(x, y int) (c image.Point, i, j int) {
+	c = image.Pt(floorDiv(x, chunkSize), floorDiv(y, chunkSize))
+	return c, x - c.X*chunkSize, y - c.Y*chunkSize
+}
+
+// Set sets the state of the specified cell to the given value.
+func (p *Infinite) Set(x, y int, b bool) {
+	c, i, j := chunkOf(x, y)
+	ch := p.chunks[c]
+	if ch == nil {
+		if !b {
+			return
+		}
+		ch = new(chunk)
+		p.chunks[c] = ch
+	}
+	ch[j][i] = b
+}
+
+// Alive reports whether the specified cell is alive.
+func (p *Infinite) Alive(x, y int) bool {
+	c, i, j := chunkOf(x, y)
+	ch := p.chunks[c]
+	return ch != nil && ch[j][i]
+}
+
+// Population returns the number of live cells.
+func (p *Infinite) Population() int {
+	n := 0
+	for _, ch := range p.chunks {
+		for j := range ch {
+			for _, b := range ch[j] {
+				if b {
+					n++
+				}
+			}
+		}
+	}
+	return n
+}
+
+// Bounds returns the smallest rectangle containing every live cell, or an
+// empty rectangle if there are none.
+func (p *Infinite) Bounds() image.Rectangle {
+	var r image.Rectangle
+	for c, ch := range p.chunks {
+		for j := range ch {
+			for i, b := range ch[j] {
+				if b {
+					x, y := c.X*chunkSize+i, c.Y*chunkSize+j
+					r = r.Union(image.Rect(x, y, x+1, y+1))
+				}
+			}
+		}
+	}
+	return r
+}
+
+// Field returns a copy of the cells of the plane within r as a field with
+// dead edges that follows the rule of the plane, the cell at r.Min being at
+// 0, 0.
+func (p *Infinite) Field(r image.Rectangle) *Field {
+	f := NewFieldWithTopology(r.Dx(), r.Dy(), Plane)
+	f.rule = p.rule
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; x++ {
+			f.set(x, y, p.Alive(r.Min.X+x, r.Min.Y+y))
+		}
+	}
+	return f
+}
+
+// Step advances the plane by one generation. Chunks next to those holding
+// live cells are considered for births, and chunks left empty are dropped.
+func (p *Infinite) Step() {
+	active := make(map[image.Point]bool, len(p.chunks))
+	for c := range p.chunks {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				active[c.Add(image.Pt(dx, dy))] = true
+			}
+		}
+	}
+	next := make(map[image.Point]*chunk, len(active))
+	for c := range active {
+		// Gather the chunk and its neighbors, any of which may be missing.
+		var around [3][3]*chunk
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				around[dy+1][dx+1] = p.chunks[c.Add(image.Pt(dx, dy))]
+			}
+		}
+		alive := func(i, j int) bool {
+			ch := around[floorDiv(j, chunkSize)+1][floorDiv(i, chunkSize)+1]
+			return ch != nil && ch[(j+chunkSize)%chunkSize][(i+chunkSize)%chunkSize]
+		}
+		var ch chunk
+		empty := true
+		for j := 0; j < chunkSize; j++ {
+			for i := 0; i < chunkSize; i++ {
+				n := 0
+				for dy := -1; dy <= 1; dy++ {
+					for dx := -1; dx <= 1; dx++ {
+						if (dx != 0 || dy != 0) && alive(i+dx, j+dy) {
+							n++
+						}
+					}
+				}
+				if ch[j][i] = p.rule.Next(alive(i, j), n); ch[j][i] {
+					empty = false
+				}
+			}
+		}
+		if !empty {
+			next[c] = &ch
+		}
+	}
+	p.chunks = next
+	p.gen++
+}
+
+// String returns the smallest rectangle of the plane containing every live
+// cell as a string, in the format of Life.String.
+func (p *Infinite) String() string {
+	r := p.Bounds()
+	var buf bytes.Buffer
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			b := byte(' ')
+			if p.Alive(x, y) {
+				b = '*'
+			}
+			buf.WriteByte(b)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
@@ -0,0 +1,211 @@
+package life
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Neighborhood configurations are indexed by nine bits, one per cell of
+// the 3x3 block around a cell, laid out as
+//
+//	8 7 6
+//	5 4 3
+//	2 1 0
+//
+// so that bit 4 is the cell itself.
+const centerBit = 1 << 4
+
+// henselLetters lists, for neighbor counts 1 to 4, the letters of the
+// Hensel notation in canonical order.
+var henselLetters = [5]string{"", "ce", "ceaikn", "ceaiknjqry", "ceaiknjqrtwyz"}
+
+// henselShapes holds a representative neighborhood configuration for each
+// letter of henselLetters. The configurations for counts 5 to 8 are the
+// complements of those for 3 to 0.
+var henselShapes = [5][]int{
+	{},
+	{1, 2},
+	{5, 10, 3, 40, 33, 68},
+	{69, 42, 11, 7, 98, 13, 14, 70, 41, 97},
+	{325, 170, 15, 45, 99, 71, 106, 102, 43, 101, 105, 78, 108},
+}
+
+// henselClass maps each configuration of the eight neighbors, with the
+// center bit clear, to its neighbor count and Hensel letter. It is built
+// from henselShapes by applying the eight symmetries of the square.
+var henselClass = buildHenselClasses()
+
+// hensel identifies a class of neighborhood configurations by neighbor
+// count and letter; the letter is 0 for counts 0 and 8.
+type hensel struct {
+	count  int
+	letter byte
+}
+
+// buildHenselClasses returns the table stored in henselClass.
+func buildHenselClasses() map[int]hensel {
+	m := make(map[int]hensel, 256)
+	for n := 0; n <= 8; n++ {
+		k, invert := n, false
+		if n > 4 {
+			k, invert = 8-n, true
+		}
+		if k == 0 {
+			cfg := 0
+			if invert {
+				cfg = 0x1ff &^ centerBit
+			}
+			m[cfg] = hensel{n, 0}
+			continue
+		}
+		for i, shape := range henselShapes[k] {
+			if invert {
+				shape = 0x1ff &^ centerBit &^ shape
+			}
+			for _, cfg := range symmetries(shape) {
+				m[cfg] = hensel{n, henselLetters[k][i]}
+			}
+		}
+	}
+	return m
+}
+
+// symmetries returns the images of a neighborhood configuration under the
+// rotations and reflections of the square.
+func symmetries(cfg int) []int {
+	out := make([]int, 0, 8)
+	for t := 0; t < 8; t++ {
+		img := 0
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if cfg&nbit(dx, dy) == 0 {
+					continue
+				}
+				x, y := dx, dy
+				for r := 0; r < t%4; r++ {
+					x, y = -y, x
+				}
+				if t >= 4 {
+					x = -x
+				}
+				img |= nbit(x, y)
+			}
+		}
+		out = append(out, img)
+	}
+	return out
+}
+
+// nbit returns the configuration bit of the neighbor at offset dx, dy.
+func nbit(dx, dy int) int {
+	return 1 << (8 - ((dy+1)*3 + dx + 1))
+}
+
+// Isotropic is a non-totalistic isotropic two-state rule, written in
+// Hensel notation such as "B2-a/S12": the fate of a cell depends on the
+// arrangement of its live neighbors up to rotation and reflection, not
+// just on their number. Isotropic implements Stepper using a lookup table
+// indexed by the full 3x3 neighborhood.
+type Isotropic struct {
+	table [512]bool
+	name  string
+}
+
+// ParseIsotropic parses a rule in Hensel notation. Each neighbor count of
+// the birth and survival parts may be followed by letters selecting only
+// some of its configurations, or by '-' and letters excluding some, so
+// "B2-a/S12" gives birth on two neighbors unless they are adjacent.
+// Totalistic rules such as "B3/S23" are accepted too.
+func ParseIsotropic(s string) (Isotropic, error) {
+	bad := func() (Isotropic, error) { return Isotropic{}, fmt.Errorf("life: invalid isotropic rule %q", s) }
+	b, sv, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok || len(b) == 0 || len(sv) == 0 {
+		return bad()
+	}
+	if b[0] == 'S' || b[0] == 's' {
+		b, sv = sv, b
+	}
+	if b[0] != 'B' && b[0] != 'b' || sv[0] != 'S' && sv[0] != 's' {
+		return bad()
+	}
+	birth, err := parseHensel(b[1:])
+	if err != nil {
+		return bad()
+	}
+	survival, err := parseHensel(sv[1:])
+	if err != nil {
+		return bad()
+	}
+	r := Isotropic{name: "B" + strings.ToLower(b[1:]) + "/S" + strings.ToLower(sv[1:])}
+	for cfg, c := range henselClass {
+		r.table[cfg] = birth[c]
+		r.table[cfg|centerBit] = survival[c]
+	}
+	return r, nil
+}
+
+// parseHensel parses the conditions of one half of a Hensel rule into the
+// set of neighborhood classes it selects.
+func parseHensel(s string) (map[hensel]bool, error) {
+	set := make(map[hensel]bool)
+	s = strings.ToLower(s)
+	for i := 0; i < len(s); {
+		if s[i] < '0' || s[i] > '8' {
+			return nil, fmt.Errorf("invalid neighbor count %q", s[i])
+		}
+		n := int(s[i] - '0')
+		i++
+		negate := i < len(s) && s[i] == '-'
+		if negate {
+			i++
+		}
+		j := i
+		for j < len(s) && s[j] >= 'a' && s[j] <= 'z' {
+			j++
+		}
+		letters := s[i:j]
+		i = j
+		k := min(n, 8-n)
+		if negate && letters == "" {
+			return nil, fmt.Errorf("missing letters after %d-", n)
+		}
+		for _, c := range letters {
+			if !strings.ContainsRune(henselLetters[k], c) {
+				return nil, fmt.Errorf("invalid letter %q for %d neighbors", c, n)
+			}
+		}
+		if k == 0 {
+			set[hensel{n, 0}] = true
+			continue
+		}
+		for _, c := range []byte(henselLetters[k]) {
+			in := letters == "" || strings.IndexByte(letters, c) >= 0
+			if in != negate {
+				set[hensel{n, c}] = true
+			}
+		}
+	}
+	return set, nil
+}
+
+// String returns the rule in Hensel notation.
+func (r Isotropic) String() string {
+	return r.name
+}
+
+// Step implements Stepper.
+func (r Isotropic) Step(dst, src *Field) {
+	for y := 0; y < src.h; y++ {
+		for x := 0; x < src.width; x++ {
+			cfg := 0
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					if src.Alive(x+dx, y+dy) {
+						cfg |= nbit(dx, dy)
+					}
+				}
+			}
+			dst.set(x, y, r.table[cfg])
+		}
+	}
+}
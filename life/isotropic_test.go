@@ -0,0 +1,71 @@
+package life
+
+import "testing"
+
+// TestParseIsotropic checks the notations accepted for isotropic rules.
+func TestParseIsotropic(t *testing.T) {
+	for _, tt := range []struct{ in, want string }{
+		{"B2-a/S12", "B2-a/S12"},
+		{"b3/s23", "B3/S23"},
+		{"S1e2/B3aik", "B3aik/S1e2"},
+	} {
+		r, err := ParseIsotropic(tt.in)
+		if err != nil {
+			t.Errorf("ParseIsotropic(%q): %v", tt.in, err)
+		} else if r.String() != tt.want {
+			t.Errorf("ParseIsotropic(%q) = %v, want %s", tt.in, r, tt.want)
+		}
+	}
+	for _, in := range []string{"B3", "X3/S23", "B/", "B2-/S", "B2x/S", "B1a/S", "B9/S", "B0c/S"} {
+		if _, err := ParseIsotropic(in); err == nil {
+			t.Errorf("ParseIsotropic(%q): no error", in)
+		}
+	}
+	// A count without letters selects all of its configurations.
+	all, _ := ParseIsotropic("B2/S")
+	spelled, _ := ParseIsotropic("B2ceaikn/S")
+	if all.table != spelled.table {
+		t.Error("B2/S and B2ceaikn/S differ")
+	}
+}
+
+// TestIsotropicStep checks that totalistic rules step as their Rule, and
+// that letters tell configurations with the same count apart.
+func TestIsotropicStep(t *testing.T) {
+	conway, err := ParseIsotropic("B3/S23")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := NewLifeFromField(soup("B3/S23"))
+	grid := want.Clone()
+	grid.SetStepper(conway)
+	for gen := 1; gen <= 20; gen++ {
+		want.Step()
+		grid.Step()
+		if !grid.Field().Equal(want.Field()) {
+			t.Fatalf("B3/S23 differs from the Rule at generation %d", gen)
+		}
+	}
+
+	r, err := ParseIsotropic("B2-a/S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		rows []string
+		born bool
+	}{
+		{[]string{"OO.", "...", "..."}, false}, // 2a: an edge and the corner next to it
+		{[]string{".O.", "...", ".O."}, true},  // 2i: opposite edges
+		{[]string{"O..", "...", "..O"}, true},  // 2n: opposite corners
+		{[]string{"...", "O.O", "..."}, true},  // 2i turned a quarter
+		{[]string{"...", "...", ".OO"}, false}, // 2a reflected
+	} {
+		src := fieldOf(t, Plane, tt.rows...)
+		dst := NewFieldWithTopology(3, 3, Plane)
+		r.Step(dst, src)
+		if dst.Alive(1, 1) != tt.born {
+			t.Errorf("%v: center born %v, want %v", tt.rows, dst.Alive(1, 1), tt.born)
+		}
+	}
+}
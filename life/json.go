@@ -0,0 +1,124 @@
+package life
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// lifeJSON is the JSON representation of a Life game. Fields are stored
+// as rows of '.' and 'O' characters, the current generation with the true
+// states of its cells and the other buffer as the game holds it.
+type lifeJSON struct {
+	Width      int      `json:"width"`
+	Height     int      `json:"height"`
+	Generation int64    `json:"generation"`
+	Rule       string   `json:"rule"`
+	Topology   string   `json:"topology,omitempty"`
+	Inverted   bool     `json:"inverted,omitempty"`
+	Current    []string `json:"current"`
+	Next       []string `json:"next"`
+}
+
+// MarshalJSON implements json.Marshaler. The encoding holds the dimensions,
+// generation counter, rule and topology of the game, the rule being that of
+// its MaskRule if one is set, along with both of its buffers and whether its
+// field is inverted; see Life.Inverted.
+func (grid *Life) MarshalJSON() ([]byte, error) {
+	return json.Marshal(lifeJSON{
+		Width:      grid.width,
+		Height:     grid.h,
+		Generation: grid.gen,
+		Rule:       grid.a.ruleName(),
+		Topology:   grid.a.top.String(),
+		Inverted:   grid.inverted,
+		Current:    grid.State().rows(),
+		Next:       grid.b.rows(),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, restoring a game encoded by
+// MarshalJSON. Games encoded without a topology are on a torus.
+func (grid *Life) UnmarshalJSON(data []byte) error {
+	var v lifeJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Width < 0 || v.Height < 0 {
+		return fmt.Errorf("life: invalid dimensions %dx%d", v.Width, v.Height)
+	}
+	rule, mask := Conway, (*MaskRule)(nil)
+	if v.Rule != "" {
+		var err error
+		if rule, mask, err = parseFileRule(v.Rule); err != nil {
+			return err
+		}
+	}
+	top := Torus
+	if v.Topology != "" {
+		var err error
+		if top, err = ParseTopology(v.Topology); err != nil {
+			return err
+		}
+	}
+	a, err := fieldFromRows(v.Current, v.Width, v.Height)
+	if err != nil {
+		return err
+	}
+	b := NewField(v.Width, v.Height)
+	if v.Next != nil {
+		if b, err = fieldFromRows(v.Next, v.Width, v.Height); err != nil {
+			return err
+		}
+	}
+	if v.Inverted {
+		a.invert()
+	}
+	a.rule, b.rule = rule, rule
+	a.top, b.top = top, top
+	a.gen = v.Generation
+	*grid = Life{a: a, b: b, width: v.Width, h: v.Height, gen: v.Generation, inverted: v.Inverted}
+	if mask != nil {
+		grid.SetStepper(mask)
+	}
+	return nil
+}
+
+// rows returns the cells of the field as rows of '.' and 'O' characters.
+func (f *Field) rows() []string {
+	rows := make([]string, f.h)
+	buf := make([]byte, f.width)
+	for y := range rows {
+		for x := range buf {
+			buf[x] = '.'
+			if f.get(x, y) {
+				buf[x] = 'O'
+			}
+		}
+		rows[y] = string(buf)
+	}
+	return rows
+}
+
+// fieldFromRows returns a field of the given size holding the cells of rows,
+// which are in the form returned by Field.rows.
+func fieldFromRows(rows []string, width, h int) (*Field, error) {
+	if len(rows) != h {
+		return nil, fmt.Errorf("life: got %d rows, want %d", len(rows), h)
+	}
+	f := NewField(width, h)
+	for y, row := range rows {
+		if len(row) != width {
+			return nil, fmt.Errorf("life: row %d has %d cells, want %d", y, len(row), width)
+		}
+		for x := 0; x < width; x++ {
+			switch row[x] {
+			case '.':
+			case 'O':
+				f.set(x, y, true)
+			default:
+				return nil, fmt.Errorf("life: unexpected character %q in row %d", row[x], y)
+			}
+		}
+	}
+	return f, nil
+}
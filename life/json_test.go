@@ -0,0 +1,46 @@
+package life
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+// TestJSONRoundTrip checks that a game restored from its JSON encoding
+// holds the same state and evolves as the original does.
+func TestJSONRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		rule string
+		top  Topology
+	}{
+		{"B3/S23", Torus},
+		{"B3/S23", Plane},
+		{"B36/S23", Topology{X: Wrap, Y: Mirror}},
+		{"B3/S23", KleinBottle},
+		{"B3/S23", Topology{X: Wrap, Y: Wrap, ShiftX: 1}},
+		{"B0123/S45", Torus},
+		{"B0/S8", Torus},
+	} {
+		grid := NewLife(70, 30, WithRandom(0.3, rand.NewSource(2)), WithRule(MustParseRule(tt.rule)), WithTopology(tt.top))
+		grid.StepN(7)
+		data, err := json.Marshal(grid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Life
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("%s on %v: %v", tt.rule, tt.top, err)
+		}
+		if got.Rule() != grid.Rule() || got.Field().Topology() != tt.top || got.Inverted() != grid.Inverted() || got.Generation() != grid.Generation() {
+			t.Errorf("%s on %v: restored %v on %v, inverted %v, at generation %d", tt.rule, tt.top,
+				got.Rule(), got.Field().Topology(), got.Inverted(), got.Generation())
+		}
+		for gen := 0; gen < 20; gen++ {
+			if x, y, ok := firstDiff(trueCells(grid), trueCells(&got)); ok {
+				t.Fatalf("%s on %v: cell %d, %d differs %d steps after restoring", tt.rule, tt.top, x, y, gen)
+			}
+			grid.Step()
+			got.Step()
+		}
+	}
+}
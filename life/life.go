@@ -0,0 +1,486 @@
+// Package life implements Conway's Game of Life.
+package life
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"math/rand"
+	"slices"
+	"unicode/utf8"
+)
+
+// Life stores the state of a round of Conway's Game of Life.
+type Life struct {
+	a, b      *Field
+	width, h  int
+	gen       int64                               // number of steps taken
+	stepper   Stepper                             // nil for the rule of the field
+	nbhd      NeighborhoodFunc                    // nil for Moore
+	next      func(self bool, neighbors int) bool // nil for the rule of the field
+	cellNext  func(n *Neighborhood) bool          // nil for next
+	noise     float64                             // probability of each cell flipping per step
+	inverted  bool                                // whether a holds the complement of the true state
+	schedule  []RuleChange                        // pending rule changes, by generation
+	workers   int                                 // goroutines stepping the game, 0 for GOMAXPROCS
+	act       activity                            // changes into the current generation
+	wordRule  Rule                                // rule applied by wordRows
+	wordRows  func(y0, y1 int)                    // steps rows with stepWords, made once to save allocations
+	text      []byte                              // buffer of Render
+	table     *ruleTable                          // transition table of tableRule
+	tableRule Rule
+	cycles    cycles                    // hashes of recent generations, for DetectCycle
+	history   history                   // generations before the current one, for StepBack
+	onChange  func(x, y int, born bool) // set by OnCellChange
+	observers []Observer                // added by AddObserver
+	glyphs    *glyphs                   // nil for defaultGlyphs
+}
+
+// A RuleChange is a change of the rule of a game scheduled for a given
+// generation.
+type RuleChange struct {
+	Generation int64
+	Rule       Rule
+}
+
+// A Stepper computes successive generations of a field, as an alternative
+// to the per-cell rule evaluation of Field.Next.
+type Stepper interface {
+	// Step writes the generation following src into dst, which has the
+	// same dimensions.
+	Step(dst, src *Field)
+}
+
+// NewLife returns a new Life game state of the given size configured by
+// opts, with a random initial state unless the options give one. Without
+// options, a quarter of the cells are set alive at random, some more than
+// once, with the global random source.
+func NewLife(width, h int, opts ...Option) *Life {
+	var c config
+	for _, o := range opts {
+		o(&c)
+	}
+	grid := NewLifeFromField(c.field(width, h))
+	for _, set := range c.settings {
+		set(grid)
+	}
+	return grid
+}
+
+// NewLifeRandom returns a new Life game state in which each cell is alive
+// with probability density, drawing from src so that the same source gives
+// the same initial state, as NewLife does with WithRandom.
+func NewLifeRandom(width, h int, density float64, src rand.Source) *Life {
+	return NewLife(width, h, WithRandom(density, src))
+}
+
+// NewLifeFromField returns a new Life game state whose initial state is the
+// given field, starting from the generation it holds. The field becomes
+// owned by the game and must not be modified by the caller afterwards. A
+// masked rule recorded with the field, as LoadRLE reads it, becomes the
+// Stepper of the game.
+func NewLifeFromField(a *Field) *Life {
+	b := NewField(a.width, a.h)
+	b.rule, b.mask, b.top = a.rule, a.mask, a.top
+	grid := &Life{
+		a: a, b: b,
+		width: a.width, h: a.h,
+		gen: a.gen,
+	}
+	if a.mask != nil {
+		grid.stepper = a.mask
+	}
+	return grid
+}
+
+// Clone returns a copy of the game, with its settings, schedule and
+// history, that can be stepped and changed independently of it. The
+// Stepper, functions and observers set on the game are shared by the copy.
+func (grid *Life) Clone() *Life {
+	c := *grid
+	c.a, c.b = grid.a.Clone(), grid.b.Clone()
+	c.schedule = slices.Clone(grid.schedule)
+	c.act = activity{}
+	c.wordRows, c.text = nil, nil
+	c.cycles.hashes = slices.Clone(grid.cycles.hashes)
+	c.history.ring = slices.Clone(grid.history.ring)
+	c.observers = slices.Clone(grid.observers)
+	if grid.glyphs != nil {
+		g := *grid.glyphs
+		c.glyphs = &g
+	}
+	return &c
+}
+
+// Reset restarts the game with a copy of seed as its state, from the
+// generation it holds, following the rule and topology of seed, while keeping its other
+// settings. The buffers of the game are reused where they are large
+// enough, so that running many games in turn, as a soup search does,
+// allocates little. Scheduled rule changes are dropped.
+func (grid *Life) Reset(seed *Field) {
+	for _, f := range [2]*Field{grid.a, grid.b} {
+		f.resize(seed.width, seed.h)
+		f.rule, f.top = seed.rule, seed.top
+		f.edits++
+	}
+	copy(grid.a.bits, seed.bits)
+	grid.a.gen = seed.gen
+	grid.width, grid.h = seed.width, seed.h
+	grid.gen, grid.inverted = seed.gen, false
+	grid.schedule = grid.schedule[:0]
+	grid.history.clear()
+	grid.act.valid = false
+}
+
+// Rule returns the rule the game follows.
+func (grid *Life) Rule() Rule {
+	return grid.a.rule
+}
+
+// SetRule changes the rule the game follows from the next step on.
+func (grid *Life) SetRule(r Rule) {
+	if grid.inverted {
+		grid.a.invert()
+		grid.inverted = false
+		grid.act.valid = false
+	}
+	grid.a.rule, grid.b.rule = r, r
+}
+
+// SetTopology changes the topology of the field of the game.
+func (grid *Life) SetTopology(top Topology) {
+	grid.a.top, grid.b.top = top, top
+}
+
+// ScheduleRule makes the game switch to rule r when it reaches generation
+// gen, before computing the following generation. Changes scheduled for
+// generations already reached take effect at the next step.
+func (grid *Life) ScheduleRule(gen int64, r Rule) {
+	i := len(grid.schedule)
+	for i > 0 && grid.schedule[i-1].Generation > gen {
+		i--
+	}
+	grid.schedule = slices.Insert(grid.schedule, i, RuleChange{gen, r})
+}
+
+// Inverted reports whether the field of the game holds the complement of
+// its true state. Under rules with B0, in which empty cells come alive,
+// the game emulates the rule as Golly does so that the background stays
+// empty: each generation in which the background would be alive is stored
+// inverted and computed with an equivalent rule. This applies while the
+// game counts neighbors in the Moore neighborhood, has no Stepper or
+// transition function set and its field has no dead edges.
+func (grid *Life) Inverted() bool {
+	return grid.inverted
+}
+
+// phaseRule returns the rule that computes the next stored generation
+// under the B0 rule r and records whether it is inverted.
+func (grid *Life) phaseRule(r Rule) Rule {
+	switch {
+	case !grid.inverted:
+		// The background comes alive, so store its complement.
+		grid.inverted = true
+		return r.complement()
+	case r.survival&(1<<8) != 0:
+		// With S8 the background stays alive.
+		return r.dual().complement()
+	default:
+		// The background dies again.
+		grid.inverted = false
+		return r.dual()
+	}
+}
+
+// SetStepper makes the game compute each generation with s rather than
+// with the rule of its field. A nil Stepper restores the rule. A *MaskRule
+// is recorded with the field, so that files saved from State hold it.
+func (grid *Life) SetStepper(s Stepper) {
+	grid.stepper = s
+	m, _ := s.(*MaskRule)
+	grid.a.mask, grid.b.mask = m, m
+}
+
+// SetNeighborhood makes the game count live neighbors with n when applying
+// the rule of its field. A nil NeighborhoodFunc restores the Moore
+// neighborhood. It has no effect while a Stepper is set.
+func (grid *Life) SetNeighborhood(n NeighborhoodFunc) {
+	grid.nbhd = n
+}
+
+// SetTransition makes the game compute the next state of each cell with
+// next, given the state of the cell and its number of live neighbors as
+// counted by the NeighborhoodFunc of the game, rather than with the rule of
+// its field. A nil function restores the rule. It has no effect while a
+// Stepper is set. The function is tabulated for every state and count
+// before each step, so it must depend on nothing else.
+func (grid *Life) SetTransition(next func(self bool, neighbors int) bool) {
+	grid.next = next
+}
+
+// SetNeighborhoodTransition is like SetTransition but has the game compute
+// the next state of each cell from its whole neighborhood, for rules that
+// depend on where its live neighbors are. It takes precedence over a
+// function set by SetTransition and ignores the NeighborhoodFunc of the
+// game. Like NeighborhoodFuncs and the functions set by SetTransition, it
+// may be called from several goroutines at once; see SetWorkers.
+func (grid *Life) SetNeighborhoodTransition(next func(n *Neighborhood) bool) {
+	grid.cellNext = next
+}
+
+// SetNoise makes every cell of the game flip its state with probability
+// rate after each step, so that on average that fraction of the cells is
+// disturbed every generation. A rate of 0 turns the noise off.
+func (grid *Life) SetNoise(rate float64) {
+	grid.noise = rate
+}
+
+// Stepper returns the Stepper set by SetStepper, or nil if the game
+// follows the rule of its field.
+func (grid *Life) Stepper() Stepper {
+	return grid.stepper
+}
+
+// Generation returns the number of the current generation, counting the
+// steps taken from that held by the initial field.
+func (grid *Life) Generation() int64 {
+	return grid.gen
+}
+
+// Population returns the number of live cells in the current generation.
+func (grid *Life) Population() int {
+	n := grid.a.Population()
+	if grid.inverted {
+		n = grid.width*grid.h - n
+	}
+	return n
+}
+
+// BoundingBox returns the corners of the smallest rectangle containing
+// every live cell of the current generation, min being its top-left cell
+// and max lying just beyond its bottom-right one, as in an
+// image.Rectangle. Both are zero if there are no live cells.
+func (grid *Life) BoundingBox() (min, max image.Point) {
+	r := grid.a.bounds(grid.inverted)
+	return r.Min, r.Max
+}
+
+// Field returns the field holding the current generation, which holds the
+// complement of its true state while the game is inverted; see Inverted,
+// and Alive and State for the true state.
+func (grid *Life) Field() *Field {
+	return grid.a
+}
+
+// Alive reports whether the specified cell of the current generation is
+// alive, mapping coordinates outside the field as Field.Alive does. It gives
+// the true state of the cell even while the field is inverted.
+func (grid *Life) Alive(x, y int) bool {
+	return grid.a.Alive(x, y) != grid.inverted
+}
+
+// State returns a copy of the field holding the current generation with
+// the true states of its cells, as Alive reports them, that the caller may
+// change freely.
+func (grid *Life) State() *Field {
+	f := grid.a.Clone()
+	if grid.inverted {
+		f.invert()
+	}
+	return f
+}
+
+// Step advances the game by one instant, recomputing and updating all cells.
+func (grid *Life) Step() {
+	for len(grid.schedule) > 0 && grid.schedule[0].Generation <= grid.gen {
+		grid.SetRule(grid.schedule[0].Rule)
+		grid.schedule = grid.schedule[1:]
+	}
+	keep := grid.cycles.hashes != nil && grid.cycles.current(grid)
+	grid.history.record(grid)
+	wasInverted := grid.inverted
+	// Update the state of the next field (b) from the current field (a),
+	// in bands of rows computed concurrently unless a Stepper is set.
+	grid.b.own()
+	var rows func(y0, y1 int)
+	track := false // whether the changes into the next generation are recorded
+	if grid.stepper != nil {
+		grid.stepper.Step(grid.b, grid.a)
+	} else if grid.cellNext != nil {
+		rows = func(y0, y1 int) {
+			for y := y0; y < y1; y++ {
+				for x := 0; x < grid.width; x++ {
+					n := grid.a.Neighborhood(x, y)
+					grid.b.set(x, y, grid.cellNext(&n))
+				}
+			}
+		}
+	} else if grid.nbhd != nil || grid.next != nil {
+		next, count := grid.next, grid.nbhd
+		if next == nil {
+			next = grid.a.rule.Next
+		}
+		if count == nil {
+			count = Moore
+		}
+		t := transitionTable(next)
+		rows = func(y0, y1 int) {
+			for y := y0; y < y1; y++ {
+				for x := 0; x < grid.width; x++ {
+					self := 0
+					if grid.a.get(x, y) {
+						self = 1
+					}
+					grid.b.set(x, y, t[self][count(grid.a, x, y)])
+				}
+			}
+		}
+	} else {
+		r := grid.a.rule
+		if r.B0() && grid.a.top.X != DeadEdge && grid.a.top.Y != DeadEdge {
+			r = grid.phaseRule(r)
+		}
+		if wordParallel(grid.a.top) {
+			// Under a B0 rule the generations are computed with alternating
+			// phase rules, so the changes into one tell nothing of the next.
+			track = !grid.a.rule.B0() && grid.noise == 0
+			grid.prepareActivity(track)
+			if grid.wordRows == nil {
+				grid.wordRows = func(y0, y1 int) {
+					stepWords(grid.b, grid.a, grid.wordRule, y0, y1, &grid.act)
+				}
+			}
+			grid.wordRule, rows = r, grid.wordRows
+		} else {
+			if grid.table == nil || grid.tableRule != r {
+				grid.table, grid.tableRule = newRuleTable(r), r
+			}
+			rows = func(y0, y1 int) { stepTable(grid.b, grid.a, grid.table, y0, y1) }
+		}
+	}
+	if rows != nil {
+		grid.bands(rows)
+	}
+	if grid.noise > 0 {
+		for y := 0; y < grid.h; y++ {
+			for x := 0; x < grid.width; x++ {
+				if rand.Float64() < grid.noise {
+					grid.b.set(x, y, !grid.b.get(x, y))
+				}
+			}
+		}
+	}
+	// Swap fields a and b.
+	grid.a, grid.b = grid.b, grid.a
+	grid.gen++
+	grid.a.gen = grid.gen
+	grid.act.valid = track
+	if track {
+		act := &grid.act
+		act.word, act.nextWord = act.nextWord, act.word
+		act.row, act.nextRow = act.nextRow, act.row
+		act.rule, act.top, act.edits = grid.a.rule, grid.a.top, grid.a.edits
+	}
+	if grid.cycles.hashes != nil {
+		grid.cycles.record(grid, keep)
+	}
+	if grid.onChange != nil {
+		grid.reportChanges(grid.b, wasInverted)
+	}
+	if len(grid.observers) > 0 {
+		f := grid.a
+		if grid.inverted {
+			f = grid.State()
+		}
+		for _, o := range grid.observers {
+			o.Observe(grid.gen, f)
+		}
+	}
+}
+
+// StepN advances the game by n generations, as n calls to Step would but
+// without anything in between, reusing the buffers of the game throughout.
+func (grid *Life) StepN(n int) {
+	for i := 0; i < n; i++ {
+		grid.Step()
+	}
+}
+
+// prepareActivity sizes the record of the changes into the current
+// generation and invalidates it if it is not to be used for the next step,
+// or if the field, its rule or its topology have changed since it was made,
+// in which case it marks the live tiles instead.
+func (grid *Life) prepareActivity(track bool) {
+	act, a := &grid.act, grid.a
+	if n := len(a.bits); len(act.word) != n || len(act.row) != a.h {
+		act.word, act.nextWord = make([]bool, n), make([]bool, n)
+		act.row, act.nextRow = make([]bool, a.h), make([]bool, a.h)
+		act.live = make([]bool, n/tileSize)
+		act.valid = false
+	}
+	if !track || act.rule != a.rule || act.top != a.top || act.edits != a.edits {
+		act.valid = false
+	}
+	if !act.valid {
+		act.markLive(a)
+	}
+}
+
+// String returns the game board as a string, a line per row, with live
+// cells as '*' and dead ones as spaces unless set otherwise by SetGlyphs
+// and SetRowEnd.
+func (grid *Life) String() string {
+	var buf bytes.Buffer
+	grid.Render(&buf)
+	return buf.String()
+}
+
+// Format implements fmt.Formatter. The verbs %v and %s print the board as
+// String does, preceded with the + flag by a line giving the generation and
+// population, and %#v prints the current generation in RLE.
+func (grid *Life) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('#'):
+		grid.State().WriteRLE(s)
+	case verb == 'v' || verb == 's':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "generation %d, population %d\n", grid.gen, grid.Population())
+		}
+		grid.Render(s)
+	default:
+		fmt.Fprintf(s, "%%!%c(*life.Life)", verb)
+	}
+}
+
+// Render writes the game board to w as String formats it, with a single
+// call to w.Write. The text is built in a buffer kept by the game, so that
+// rendering successive generations allocates nothing.
+func (grid *Life) Render(w io.Writer) error {
+	grid.text = grid.appendText(grid.text[:0])
+	_, err := w.Write(grid.text)
+	return err
+}
+
+// appendText appends the game board to buf as String formats it.
+func (grid *Life) appendText(buf []byte) []byte {
+	g := grid.glyphs
+	if g == nil {
+		g = &defaultGlyphs
+	}
+	for y := 0; y < grid.h; y++ {
+		for x := 0; x < grid.width; x++ {
+			r := g.dead
+			if grid.Alive(x, y) {
+				r = g.alive
+			}
+			if r < utf8.RuneSelf {
+				buf = append(buf, byte(r))
+			} else {
+				buf = utf8.AppendRune(buf, r)
+			}
+		}
+		buf = append(buf, g.rowEnd...)
+	}
+	return buf
+}
@@ -0,0 +1,228 @@
+package life
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Rule3D is a totalistic rule for three-dimensional Life in Carter Bays'
+// notation: a live cell survives if it has between SurviveMin and
+// SurviveMax of its 26 neighbors alive, and a dead cell becomes alive if it
+// has between BirthMin and BirthMax, inclusive.
+type Rule3D struct {
+	SurviveMin, SurviveMax int
+	BirthMin, BirthMax     int
+}
+
+// Life4555 and Life5766 are the two 3D rules proposed by Bays as
+// counterparts of Conway's Life.
+var (
+	Life4555 = Rule3D{4, 5, 5, 5}
+	Life5766 = Rule3D{5, 7, 6, 6}
+)
+
+// ParseRule3D parses a 3D rule written as four numbers in Bays' order,
+// either as four digits such as "4555" or separated by commas or slashes
+// such as "5,7,6,6".
+func ParseRule3D(s string) (Rule3D, error) {
+	var parts []string
+	if t := strings.TrimSpace(s); len(t) == 4 && strings.Trim(t, "0123456789") == "" {
+		parts = strings.Split(t, "")
+	} else {
+		parts = strings.FieldsFunc(t, func(r rune) bool { return r == ',' || r == '/' })
+	}
+	if len(parts) != 4 {
+		return Rule3D{}, fmt.Errorf("life: invalid 3D rule %q", s)
+	}
+	var n [4]int
+	for i, p := range parts {
+		v, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || v < 0 || v > 26 {
+			return Rule3D{}, fmt.Errorf("life: invalid 3D rule %q", s)
+		}
+		n[i] = v
+	}
+	if n[0] > n[1] || n[2] > n[3] {
+		return Rule3D{}, fmt.Errorf("life: invalid 3D rule %q", s)
+	}
+	return Rule3D{n[0], n[1], n[2], n[3]}, nil
+}
+
+// String returns the rule in Bays' notation, such as "4555".
+func (r Rule3D) String() string {
+	n := [4]int{r.SurviveMin, r.SurviveMax, r.BirthMin, r.BirthMax}
+	sep := ""
+	for _, v := range n {
+		if v > 9 {
+			sep = ","
+		}
+	}
+	return fmt.Sprintf("%d%s%d%s%d%s%d", n[0], sep, n[1], sep, n[2], sep, n[3])
+}
+
+// Next returns the next state of a cell with the given state and number of
+// live neighbors.
+func (r Rule3D) Next(alive bool, neighbors int) bool {
+	if alive {
+		return neighbors >= r.SurviveMin && neighbors <= r.SurviveMax
+	}
+	return neighbors >= r.BirthMin && neighbors <= r.BirthMax
+}
+
+// Field3D represents a three-dimensional field of cells evolving under a
+// 3D rule. It is the three-dimensional counterpart of Field, and likewise
+// wraps toroidally along every axis.
+type Field3D struct {
+	s               []bool // x varies fastest, then y, then z
+	width, h, depth int
+	rule            Rule3D
+}
+
+// NewField3D returns an empty field of the specified width, height and
+// depth that follows the 4555 rule.
+func NewField3D(width, h, depth int) *Field3D {
+	return &Field3D{s: make([]bool, width*h*depth), width: width, h: h, depth: depth, rule: Life4555}
+}
+
+// Width returns the width of the field.
+func (f *Field3D) Width() int { return f.width }
+
+// Height returns the height of the field.
+func (f *Field3D) Height() int { return f.h }
+
+// Depth returns the depth of the field.
+func (f *Field3D) Depth() int { return f.depth }
+
+// Rule returns the rule the field follows.
+func (f *Field3D) Rule() Rule3D { return f.rule }
+
+// SetRule sets the rule the field follows.
+func (f *Field3D) SetRule(r Rule3D) { f.rule = r }
+
+// Set sets the state of the specified cell to the given value.
+func (f *Field3D) Set(x, y, z int, b bool) {
+	f.s[(z*f.h+y)*f.width+x] = b
+}
+
+// Alive reports whether the specified cell is alive, wrapping coordinates
+// outside the field as Field.Alive does.
+func (f *Field3D) Alive(x, y, z int) bool {
+	x, y, z = wrap(x, f.width), wrap(y, f.h), wrap(z, f.depth)
+	return f.s[(z*f.h+y)*f.width+x]
+}
+
+// Next returns the state of the specified cell at the next time step.
+func (f *Field3D) Next(x, y, z int) bool {
+	alive := 0
+	for k := -1; k <= 1; k++ {
+		for j := -1; j <= 1; j++ {
+			for i := -1; i <= 1; i++ {
+				if (i != 0 || j != 0 || k != 0) && f.Alive(x+i, y+j, z+k) {
+					alive++
+				}
+			}
+		}
+	}
+	return f.rule.Next(f.Alive(x, y, z), alive)
+}
+
+// Slice returns layer z of the field as a two-dimensional field, for
+// rendering with the tools for Field.
+func (f *Field3D) Slice(z int) *Field {
+	s := NewField(f.width, f.h)
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; x++ {
+			s.set(x, y, f.Alive(x, y, z))
+		}
+	}
+	return s
+}
+
+// layersPerRow is the number of layers String draws side by side.
+const layersPerRow = 4
+
+// String returns the field as a string, drawing its layers side by side in
+// rows of four and their cells as Life.String does.
+func (f *Field3D) String() string {
+	var buf bytes.Buffer
+	for z0 := 0; z0 < f.depth; z0 += layersPerRow {
+		if z0 > 0 {
+			buf.WriteByte('\n')
+		}
+		for y := 0; y < f.h; y++ {
+			for z := z0; z < min(z0+layersPerRow, f.depth); z++ {
+				if z > z0 {
+					buf.WriteString(" | ")
+				}
+				for x := 0; x < f.width; x++ {
+					b := byte(' ')
+					if f.Alive(x, y, z) {
+						b = '*'
+					}
+					buf.WriteByte(b)
+				}
+			}
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.String()
+}
+
+// Life3D stores the state of a round of three-dimensional Life. It is the
+// three-dimensional counterpart of Life.
+type Life3D struct {
+	a, b *Field3D
+	gen  int64 // number of steps taken
+}
+
+// NewLife3D returns a new game state following rule with a random initial
+// state in which a quarter of the cells are alive.
+func NewLife3D(width, h, depth int, rule Rule3D) *Life3D {
+	a := NewField3D(width, h, depth)
+	a.rule = rule
+	for i := 0; i < (width * h * depth / 4); i++ {
+		a.Set(rand.Intn(width), rand.Intn(h), rand.Intn(depth), true)
+	}
+	return NewLife3DFromField(a)
+}
+
+// NewLife3DFromField returns a new game state whose initial state is the
+// given field. The field becomes owned by the game and must not be
+// modified by the caller afterwards.
+func NewLife3DFromField(a *Field3D) *Life3D {
+	b := NewField3D(a.width, a.h, a.depth)
+	b.rule = a.rule
+	return &Life3D{a: a, b: b}
+}
+
+// Field returns the field holding the current generation.
+func (grid *Life3D) Field() *Field3D {
+	return grid.a
+}
+
+// Generation returns the number of steps taken.
+func (grid *Life3D) Generation() int64 {
+	return grid.gen
+}
+
+// Step advances the game by one instant, recomputing and updating all
+// cells.
+func (grid *Life3D) Step() {
+	for z := 0; z < grid.a.depth; z++ {
+		for y := 0; y < grid.a.h; y++ {
+			for x := 0; x < grid.a.width; x++ {
+				grid.b.Set(x, y, z, grid.a.Next(x, y, z))
+			}
+		}
+	}
+	grid.a, grid.b = grid.b, grid.a
+	grid.gen++
+}
+
+// String returns the current generation as Field3D.String does.
+func (grid *Life3D) String() string {
+	return grid.a.String()
+}
@@ -0,0 +1,92 @@
+package life
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// TestParseRule3D checks the notations of 3D rules.
+func TestParseRule3D(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want Rule3D
+	}{
+		{"4555", Life4555},
+		{"5,7,6,6", Life5766},
+		{"2/10/3/12", Rule3D{2, 10, 3, 12}},
+	} {
+		r, err := ParseRule3D(tt.in)
+		if err != nil || r != tt.want {
+			t.Errorf("ParseRule3D(%q) = %v, %v, want %v", tt.in, r, err, tt.want)
+		}
+		if back, err := ParseRule3D(r.String()); err != nil || back != r {
+			t.Errorf("ParseRule3D(%q) = %v, %v, want %v", r.String(), back, err, r)
+		}
+	}
+	for _, in := range []string{"455", "5,4,5,5", "4,5,6,5", "4,5,5,27", "a555"} {
+		if _, err := ParseRule3D(in); err == nil {
+			t.Errorf("ParseRule3D(%q): no error", in)
+		}
+	}
+}
+
+// TestLife3DStep checks each generation against a direct count of the 26
+// neighbors of every cell, across the faces of the torus.
+func TestLife3DStep(t *testing.T) {
+	const w, h, d = 8, 6, 5
+	rng := rand.New(rand.NewSource(5))
+	f := NewField3D(w, h, d)
+	f.SetRule(Life5766)
+	for z := 0; z < d; z++ {
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				f.Set(x, y, z, rng.Intn(3) == 0)
+			}
+		}
+	}
+	grid := NewLife3DFromField(f)
+	prev := NewField3D(w, h, d)
+	for gen := 1; gen <= 6; gen++ {
+		a := grid.Field()
+		for z := 0; z < d; z++ {
+			for y := 0; y < h; y++ {
+				for x := 0; x < w; x++ {
+					prev.Set(x, y, z, a.Alive(x, y, z))
+				}
+			}
+		}
+		grid.Step()
+		for z := 0; z < d; z++ {
+			for y := 0; y < h; y++ {
+				for x := 0; x < w; x++ {
+					n := 0
+					for dz := -1; dz <= 1; dz++ {
+						for dy := -1; dy <= 1; dy++ {
+							for dx := -1; dx <= 1; dx++ {
+								if (dx != 0 || dy != 0 || dz != 0) && prev.Alive(x+dx, y+dy, z+dz) {
+									n++
+								}
+							}
+						}
+					}
+					if want := Life5766.Next(prev.Alive(x, y, z), n); grid.Field().Alive(x, y, z) != want {
+						t.Fatalf("generation %d: cell %d, %d, %d alive %v, want %v", gen, x, y, z, !want, want)
+					}
+				}
+			}
+		}
+	}
+	if grid.Generation() != 6 {
+		t.Errorf("generation %d, want 6", grid.Generation())
+	}
+
+	s := NewField3D(3, 2, 2)
+	s.Set(2, 1, 1, true)
+	if got := liveCells(s.Slice(1)); !slices.Equal(got, pts(2, 1)) {
+		t.Errorf("Slice(1): cells %v, want (2,1)", got)
+	}
+	if got := liveCells(s.Slice(0)); got != nil {
+		t.Errorf("Slice(0): cells %v, want none", got)
+	}
+}
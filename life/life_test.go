@@ -0,0 +1,328 @@
+package life
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// trueCells returns the true state of every cell of grid, row by row.
+func trueCells(grid *Life) [][]bool {
+	cells := make([][]bool, grid.h)
+	for y := range cells {
+		cells[y] = make([]bool, grid.width)
+		for x := range cells[y] {
+			cells[y][x] = grid.a.Alive(x, y) != grid.inverted
+		}
+	}
+	return cells
+}
+
+// firstDiff returns the first cell in which a and b differ, or false if they
+// agree.
+func firstDiff(a, b [][]bool) (x, y int, ok bool) {
+	for y := range a {
+		for x := range a[y] {
+			if a[y][x] != b[y][x] {
+				return x, y, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// TestStepMatchesNaive checks that the word-parallel engine agrees with the
+// rule applied cell by cell, including under B0 rules, which the engine
+// emulates by inverting alternate generations.
+func TestStepMatchesNaive(t *testing.T) {
+	for _, rule := range []string{"B3/S23", "B36/S23", "B0123/S45", "B0/S8", "B01/S012345678", "B0123478/S01234678"} {
+		r := MustParseRule(rule)
+		seed := NewLife(100, 40, WithRandom(0.25, rand.NewSource(11)), WithRule(r)).Field()
+		field := NewLifeFromField(seed.Clone())
+		naive := NewLifeFromField(seed.Clone())
+		naive.SetTransition(r.Next)
+		for gen := 1; gen <= 300; gen++ {
+			field.Step()
+			naive.Step()
+			if x, y, ok := firstDiff(trueCells(field), trueCells(naive)); ok {
+				t.Fatalf("%s: generation %d: cell %d, %d differs from the naive engine", rule, gen, x, y)
+			}
+		}
+	}
+}
+
+// TestInvertedReaders checks that the readers of a game give its true state
+// while its field is inverted.
+func TestInvertedReaders(t *testing.T) {
+	grid := NewLife(6, 4, WithRandom(0, rand.NewSource(1)), WithRule(MustParseRule("B0/S")))
+	grid.Step()
+	if !grid.Inverted() {
+		t.Fatal("field not inverted under B0/S")
+	}
+	if n := grid.Population(); n != 24 {
+		t.Errorf("Population() = %d, want 24", n)
+	}
+	if n := grid.State().Population(); n != 24 {
+		t.Errorf("State().Population() = %d, want 24", n)
+	}
+	if s, want := grid.String(), strings.Repeat("******\n", 4); s != want {
+		t.Errorf("String() = %q, want %q", s, want)
+	}
+	var buf bytes.Buffer
+	if err := grid.WriteCSV(&buf, false); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 24 {
+		t.Errorf("WriteCSV wrote %d cells, want 24", n)
+	}
+	if s := Synced(grid).Snapshot(); s.Population() != 24 {
+		t.Errorf("SyncedLife.Snapshot().Population() = %d, want 24", s.Population())
+	}
+}
+
+// TestNeighborhoodTransition checks that a transition function given the
+// whole neighborhood, applied by several workers, agrees with the rule it
+// implements and leaves the field unedited.
+func TestNeighborhoodTransition(t *testing.T) {
+	seed := NewLife(100, 40, WithRandom(0.25, rand.NewSource(3))).Field()
+	field := NewLifeFromField(seed.Clone())
+	custom := NewLifeFromField(seed.Clone())
+	custom.SetWorkers(4)
+	custom.SetNeighborhoodTransition(func(n *Neighborhood) bool {
+		count := 0
+		for j := range n {
+			for i := range n[j] {
+				if n[j][i] && (i != 1 || j != 1) {
+					count++
+				}
+			}
+		}
+		return Conway.Next(n[1][1], count)
+	})
+	for gen := 1; gen <= 50; gen++ {
+		field.Step()
+		custom.Step()
+		if x, y, ok := firstDiff(trueCells(field), trueCells(custom)); ok {
+			t.Fatalf("generation %d: cell %d, %d differs from the rule", gen, x, y)
+		}
+	}
+	if e := custom.Field().edits; e != seed.edits {
+		t.Errorf("stepping counted %d edits of the field", e-seed.edits)
+	}
+}
+
+// TestRenderAllocs checks that rendering successive generations allocates
+// nothing once the buffer of the game has grown to the board.
+func TestRenderAllocs(t *testing.T) {
+	grid := NewLife(80, 40, WithRandom(0.25, rand.NewSource(1)))
+	grid.SetWorkers(1)
+	grid.Render(io.Discard)
+	if n := testing.AllocsPerRun(100, func() {
+		grid.Step()
+		grid.Render(io.Discard)
+	}); n != 0 {
+		t.Errorf("stepping and rendering allocate %v times per frame, want 0", n)
+	}
+}
+
+// BenchmarkRender times rendering a 200×60 board with glyphs of several
+// bytes.
+func BenchmarkRender(b *testing.B) {
+	grid := NewLife(200, 60, WithRandom(0.25, rand.NewSource(1)), WithGlyphs('█', '·'))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		grid.Render(io.Discard)
+	}
+}
+
+// TestScheduleRule checks that scheduled rule changes take effect at their
+// generations whatever order they were scheduled in, and that a change for
+// a generation already reached takes effect at the next step.
+func TestScheduleRule(t *testing.T) {
+	highLife, seeds := MustParseRule("B36/S23"), MustParseRule("B2/S")
+	seed := NewLife(48, 32, WithRandom(0.4, rand.NewSource(6))).State()
+	grid, want := NewLifeFromField(seed.Clone()), NewLifeFromField(seed.Clone())
+	grid.ScheduleRule(5, seeds)
+	grid.ScheduleRule(2, highLife)
+	want.StepN(2)
+	want.SetRule(highLife)
+	want.StepN(3)
+	want.SetRule(seeds)
+	want.StepN(2)
+	grid.StepN(7)
+	if !grid.Field().Equal(want.Field()) || grid.Field().Rule() != seeds {
+		t.Errorf("generation 7 under %v:\n%s\nwant under %v:\n%s", grid.Field().Rule(), grid, seeds, want)
+	}
+	grid.ScheduleRule(0, Conway)
+	want.SetRule(Conway)
+	grid.Step()
+	want.Step()
+	if !grid.Field().Equal(want.Field()) {
+		t.Errorf("change scheduled for generation 0 at generation 7:\n%s\nwant:\n%s", grid, want)
+	}
+}
+
+// TestSetNoise checks that noise flips cells after the rule is applied, at
+// about the rate set, and that a rate of 0 turns it off.
+func TestSetNoise(t *testing.T) {
+	grid := NewLifeFromField(fieldOf(t, Torus, ".O....", "..O...", "OOO...", "......", "......"))
+	want := grid.Clone()
+	grid.SetNoise(1)
+	grid.Step()
+	want.Step()
+	want.Field().invert()
+	if !grid.Field().Equal(want.Field()) {
+		t.Errorf("noise at rate 1:\n%s\nwant the complement of the next generation:\n%s", grid, want)
+	}
+	grid.SetNoise(0)
+	grid.Step()
+	want.Step()
+	if !grid.Field().Equal(want.Field()) {
+		t.Errorf("noise at rate 0:\n%s\nwant:\n%s", grid, want)
+	}
+	// Under a rule in which every cell dies, the cells alive after a step
+	// are those the noise flipped.
+	f := NewField(64, 64)
+	f.SetRule(MustParseRule("B/S"))
+	grid = NewLifeFromField(f)
+	grid.SetNoise(0.25)
+	grid.Step()
+	if n := grid.Population(); n < 850 || n > 1200 {
+		t.Errorf("noise at rate 0.25 flipped %d of 4096 cells, want about 1024", n)
+	}
+}
+
+// TestClone checks that a copy of a game, and of its field, follows the
+// same schedule but is stepped and changed independently of the original.
+func TestClone(t *testing.T) {
+	grid := NewLife(40, 30, WithRandom(0.4, rand.NewSource(9)))
+	grid.ScheduleRule(3, MustParseRule("B36/S23"))
+	grid.SetHistory(2)
+	c := grid.Clone()
+	before := grid.State()
+	c.StepN(5)
+	if !grid.State().Equal(before) || grid.Generation() != 0 {
+		t.Fatal("stepping the copy changed the original")
+	}
+	grid.StepN(5)
+	if !grid.Field().Equal(c.Field()) || grid.Field().Rule() != c.Field().Rule() {
+		t.Errorf("copy stepped 5 generations under %v differs from the original under %v", c.Field().Rule(), grid.Field().Rule())
+	}
+	if !c.StepBack() || !c.StepBack() || c.StepBack() {
+		t.Error("copy did not keep 2 generations of history")
+	}
+	f := grid.Field().Clone()
+	f.Fill(true)
+	if grid.Population() == f.Population() {
+		t.Error("filling a copy of the field filled the game")
+	}
+}
+
+// TestNewLifeRandom checks that soups drawn from sources seeded alike are
+// the same, and the soups of densities 0 and 1.
+func TestNewLifeRandom(t *testing.T) {
+	a := NewLifeRandom(50, 40, 0.5, rand.NewSource(11))
+	if b := NewLifeRandom(50, 40, 0.5, rand.NewSource(11)); !a.Field().Equal(b.Field()) {
+		t.Error("soups drawn from sources seeded alike differ")
+	}
+	if b := NewLifeRandom(50, 40, 0.5, rand.NewSource(12)); a.Field().Equal(b.Field()) {
+		t.Error("soups drawn from sources seeded differently are the same")
+	}
+	if n := NewLifeRandom(50, 40, 0, rand.NewSource(1)).Population(); n != 0 {
+		t.Errorf("soup of density 0: population %d", n)
+	}
+	if n := NewLifeRandom(50, 40, 1, rand.NewSource(1)).Population(); n != 2000 {
+		t.Errorf("soup of density 1: population %d, want 2000", n)
+	}
+}
+
+// TestPopulationBoundingBox checks the population and bounding box of the
+// true state of a game, also while its field is inverted.
+func TestPopulationBoundingBox(t *testing.T) {
+	grid := NewLifeFromField(fieldOf(t, Torus, "......", "..O...", "...O..", ".OOO..", "......"))
+	if min, max := grid.BoundingBox(); grid.Population() != 5 || min != image.Pt(1, 1) || max != image.Pt(4, 4) {
+		t.Errorf("glider: population %d, bounding box %v–%v", grid.Population(), min, max)
+	}
+	grid = NewLifeFromField(NewField(6, 5))
+	if min, max := grid.BoundingBox(); grid.Population() != 0 || min != (image.Point{}) || max != (image.Point{}) {
+		t.Errorf("empty board: population %d, bounding box %v–%v", grid.Population(), min, max)
+	}
+	// Under B0 with S8, the empty board comes alive and stays so, stored
+	// inverted.
+	grid.SetRule(MustParseRule("B0123478/S01234678"))
+	grid.Step()
+	if min, max := grid.BoundingBox(); !grid.Inverted() || grid.Population() != 30 || min != image.Pt(0, 0) || max != image.Pt(6, 5) {
+		t.Errorf("full board: inverted %v, population %d, bounding box %v–%v", grid.Inverted(), grid.Population(), min, max)
+	}
+}
+
+// TestFormat checks the text printed for a game by each verb.
+func TestFormat(t *testing.T) {
+	grid := NewLifeFromField(fieldOf(t, Torus, ".O....", "..O...", "OOO...", "......", "......", "......"))
+	grid.StepN(4)
+	for _, tt := range []struct {
+		format, want string
+	}{
+		{"%v", grid.String()},
+		{"%s", grid.String()},
+		{"%+v", "generation 4, population 5\n" + grid.String()},
+		{"%d", "%!d(*life.Life)"},
+	} {
+		if got := fmt.Sprintf(tt.format, grid); got != tt.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+	f, err := LoadRLE(strings.NewReader(fmt.Sprintf("%#v", grid)))
+	if err != nil {
+		t.Fatalf("%%#v: %v", err)
+	}
+	if !f.Trim().Equal(grid.Field().Trim()) || f.Generation() != 4 {
+		t.Errorf("%%#v printed an RLE of a different board, or of generation %d", f.Generation())
+	}
+}
+
+// TestReset checks that a game restarted from seeds of other sizes, rules
+// and generations runs as a new game made from them would, with its
+// schedule and history dropped.
+func TestReset(t *testing.T) {
+	grid := NewLife(40, 30, WithRandom(0.4, rand.NewSource(13)), WithHistory(3))
+	grid.ScheduleRule(20, MustParseRule("B2/S"))
+	grid.StepN(10)
+	for i, size := range [][2]int{{70, 20}, {40, 30}, {8, 9}} {
+		seed := NewLife(size[0], size[1], WithRandom(0.4, rand.NewSource(int64(i)))).State()
+		seed.SetRule(MustParseRule("B36/S23"))
+		seed.SetTopology(KleinBottle)
+		seed.SetGeneration(5)
+		grid.Reset(seed)
+		want := NewLifeFromField(seed.Clone())
+		if grid.StepBack() {
+			t.Errorf("%d×%d seed: history kept across Reset", size[0], size[1])
+		}
+		grid.StepN(20)
+		want.StepN(20)
+		if !grid.Field().Equal(want.Field()) || grid.Generation() != 25 || grid.Field().Rule() != seed.Rule() || grid.Field().Topology() != KleinBottle {
+			t.Errorf("%d×%d seed: generation %d under %v on %v differs from a new game", size[0], size[1], grid.Generation(), grid.Field().Rule(), grid.Field().Topology())
+		}
+	}
+}
+
+// TestStepN checks that StepN advances a game as many calls to Step would.
+func TestStepN(t *testing.T) {
+	grid := NewLife(64, 48, WithRandom(0.35, rand.NewSource(14)))
+	want := grid.Clone()
+	grid.StepN(0)
+	if grid.Generation() != 0 || !grid.Field().Equal(want.Field()) {
+		t.Error("StepN(0) changed the game")
+	}
+	grid.StepN(37)
+	for i := 0; i < 37; i++ {
+		want.Step()
+	}
+	if grid.Generation() != 37 || !grid.Field().Equal(want.Field()) {
+		t.Errorf("StepN(37) reached generation %d, differing from 37 calls to Step", grid.Generation())
+	}
+}
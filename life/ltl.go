@@ -0,0 +1,167 @@
+package life
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LtL is a Larger than Life rule: an outer-totalistic two-state rule over
+// a neighborhood of radius R, in which a dead cell is born if the number
+// of live cells in its neighborhood lies in the birth range and a live cell
+// survives if it lies in the survival range. LtL implements Stepper.
+type LtL struct {
+	Radius                 int
+	Middle                 bool // whether the cell counts itself
+	VonNeumann             bool // diamond-shaped neighborhood instead of a square
+	BirthMin, BirthMax     int
+	SurviveMin, SurviveMax int
+}
+
+// ParseLtL parses a Larger than Life rule written in Golly's notation,
+// such as "R5,C0,M1,S34..58,B34..45,NM" for Bosco's rule. Only the
+// two-state rules, with C0, C1 or C2, are supported.
+func ParseLtL(s string) (LtL, error) {
+	bad := func() (LtL, error) { return LtL{}, fmt.Errorf("life: invalid Larger than Life rule %q", s) }
+	var r LtL
+	seen := make(map[byte]bool)
+	for _, part := range strings.Split(strings.ToUpper(strings.TrimSpace(s)), ",") {
+		if part == "" {
+			return bad()
+		}
+		key, val := part[0], part[1:]
+		if seen[key] {
+			return bad()
+		}
+		seen[key] = true
+		var err error
+		switch key {
+		case 'R':
+			if r.Radius, err = strconv.Atoi(val); err != nil || r.Radius < 1 || r.Radius > 500 {
+				return bad()
+			}
+		case 'C':
+			c, err := strconv.Atoi(val)
+			if err != nil || c < 0 {
+				return bad()
+			}
+			if c > 2 {
+				return LtL{}, fmt.Errorf("life: Larger than Life rule %q has %d states; only two-state rules are supported", s, c)
+			}
+		case 'M':
+			if val != "0" && val != "1" {
+				return bad()
+			}
+			r.Middle = val == "1"
+		case 'S':
+			if r.SurviveMin, r.SurviveMax, err = parseRange(val); err != nil {
+				return bad()
+			}
+		case 'B':
+			if r.BirthMin, r.BirthMax, err = parseRange(val); err != nil {
+				return bad()
+			}
+		case 'N':
+			switch val {
+			case "M":
+			case "N":
+				r.VonNeumann = true
+			default:
+				return bad()
+			}
+		default:
+			return bad()
+		}
+	}
+	if !seen['R'] || !seen['S'] || !seen['B'] {
+		return bad()
+	}
+	return r, nil
+}
+
+// parseRange parses a range of counts written as "min..max" or as a
+// single count.
+func parseRange(s string) (lo, hi int, err error) {
+	a, b, ok := strings.Cut(s, "..")
+	if !ok {
+		b = a
+	}
+	if lo, err = strconv.Atoi(a); err != nil {
+		return 0, 0, err
+	}
+	if hi, err = strconv.Atoi(b); err != nil {
+		return 0, 0, err
+	}
+	if lo < 0 || hi < lo {
+		return 0, 0, fmt.Errorf("invalid range %q", s)
+	}
+	return lo, hi, nil
+}
+
+// String returns the rule in Golly's notation.
+func (r LtL) String() string {
+	m, n := 0, 'M'
+	if r.Middle {
+		m = 1
+	}
+	if r.VonNeumann {
+		n = 'N'
+	}
+	return fmt.Sprintf("R%d,C0,M%d,S%d..%d,B%d..%d,N%c",
+		r.Radius, m, r.SurviveMin, r.SurviveMax, r.BirthMin, r.BirthMax, n)
+}
+
+// Step implements Stepper. Neighborhood counts are read from a summed-area
+// table of the field, so the cost per cell does not depend on the radius
+// for square neighborhoods and grows only linearly with it for diamond
+// ones.
+func (r LtL) Step(dst, src *Field) {
+	w, h, rad := src.width, src.h, r.Radius
+	// sum[j][i] is the number of live cells in the padded field above row j
+	// and left of column i, where padded row j is field row j-rad-1 and
+	// likewise for columns, beyond the edges as given by the topology of the
+	// field.
+	pw, ph := w+2*rad+1, h+2*rad+1
+	sum := make([][]int32, ph)
+	for j := range sum {
+		sum[j] = make([]int32, pw)
+		if j == 0 {
+			continue
+		}
+		var acc int32
+		for i := 1; i < pw; i++ {
+			if src.Alive(i-rad-1, j-rad-1) {
+				acc++
+			}
+			sum[j][i] = sum[j-1][i] + acc
+		}
+	}
+	// rect returns the number of live cells in the padded rectangle of
+	// columns [x0, x1) and rows [y0, y1).
+	rect := func(x0, y0, x1, y1 int) int {
+		return int(sum[y1][x1] - sum[y0][x1] - sum[y1][x0] + sum[y0][x0])
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			// The cell is at padded column x+rad, row y+rad.
+			var n int
+			if r.VonNeumann {
+				for dy := -rad; dy <= rad; dy++ {
+					d := rad - max(dy, -dy)
+					n += rect(x+rad-d, y+rad+dy, x+rad+d+1, y+rad+dy+1)
+				}
+			} else {
+				n = rect(x, y, x+2*rad+1, y+2*rad+1)
+			}
+			alive := src.get(x, y)
+			if alive && !r.Middle {
+				n--
+			}
+			if alive {
+				dst.set(x, y, n >= r.SurviveMin && n <= r.SurviveMax)
+			} else {
+				dst.set(x, y, n >= r.BirthMin && n <= r.BirthMax)
+			}
+		}
+	}
+}
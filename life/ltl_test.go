@@ -0,0 +1,90 @@
+package life
+
+import "testing"
+
+// TestParseLtL checks the notation of Larger than Life rules.
+func TestParseLtL(t *testing.T) {
+	for _, tt := range []struct{ in, want string }{
+		{"R5,C0,M1,S34..58,B34..45,NM", "R5,C0,M1,S34..58,B34..45,NM"},
+		{"r2,c2,m0,s3,b4..6,nn", "R2,C0,M0,S3..3,B4..6,NN"},
+		{"R1,S2..3,B3", "R1,C0,M0,S2..3,B3..3,NM"},
+	} {
+		r, err := ParseLtL(tt.in)
+		if err != nil {
+			t.Errorf("ParseLtL(%q): %v", tt.in, err)
+		} else if r.String() != tt.want {
+			t.Errorf("ParseLtL(%q) = %v, want %s", tt.in, r, tt.want)
+		}
+	}
+	for _, in := range []string{
+		"", "R5,S34..58", "R0,S1,B1", "R1,C3,S1,B1", "R1,M2,S1,B1", "R1,S3..2,B1",
+		"R1,S1,B1,NX", "R1,R2,S1,B1", "R1,S1,B1,", "R1,S1,B1,Q1",
+	} {
+		if _, err := ParseLtL(in); err == nil {
+			t.Errorf("ParseLtL(%q): no error", in)
+		}
+	}
+}
+
+// ltl returns the Larger than Life rule s, which must be valid.
+func ltl(t *testing.T, s string) LtL {
+	t.Helper()
+	r, err := ParseLtL(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// TestLtLStep checks that a rule of radius 1 steps as the Life-like rule
+// it equals, and that larger neighborhoods count the cells a direct count
+// over the radius finds, across the edges of the torus.
+func TestLtLStep(t *testing.T) {
+	conway := ltl(t, "R1,C0,M0,S2..3,B3,NM")
+	want := NewLifeFromField(soup("B3/S23"))
+	grid := want.Clone()
+	grid.SetStepper(conway)
+	for gen := 1; gen <= 20; gen++ {
+		want.Step()
+		grid.Step()
+		if !grid.Field().Equal(want.Field()) {
+			t.Fatalf("R1 differs from B3/S23 at generation %d", gen)
+		}
+	}
+
+	for _, r := range []LtL{
+		ltl(t, "R3,C0,M1,S8..20,B10..16,NM"),
+		ltl(t, "R3,C0,M0,S5..12,B6..9,NN"),
+	} {
+		f := soup("B3/S23")
+		for gen := 1; gen <= 5; gen++ {
+			got := NewField(f.Width(), f.Height())
+			r.Step(got, f)
+			want := NewField(f.Width(), f.Height())
+			for y := 0; y < f.Height(); y++ {
+				for x := 0; x < f.Width(); x++ {
+					n := 0
+					for dy := -r.Radius; dy <= r.Radius; dy++ {
+						for dx := -r.Radius; dx <= r.Radius; dx++ {
+							if r.VonNeumann && max(dx, -dx)+max(dy, -dy) > r.Radius || dx == 0 && dy == 0 && !r.Middle {
+								continue
+							}
+							if f.Alive(x+dx, y+dy) {
+								n++
+							}
+						}
+					}
+					lo, hi := r.BirthMin, r.BirthMax
+					if f.Alive(x, y) {
+						lo, hi = r.SurviveMin, r.SurviveMax
+					}
+					want.Set(x, y, n >= lo && n <= hi)
+				}
+			}
+			if !got.Equal(want) {
+				t.Fatalf("%v differs from a direct count at generation %d", r, gen)
+			}
+			f = got
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package life
+
+import "math/bits"
+
+// ruleTable is the transition table of a two-state rule on the Moore
+// neighborhood, giving the next state of a cell for each of the 512
+// configurations of its 3×3 neighborhood. A configuration packs the cells
+// column by column from the west, each column as three bits from the north,
+// so that moving a cell east shifts it left by three bits.
+type ruleTable [512]bool
+
+// center is the bit of the cell itself in a configuration.
+const center = 1 << 4
+
+// newRuleTable returns the transition table of r.
+func newRuleTable(r Rule) *ruleTable {
+	t := new(ruleTable)
+	for cfg := range t {
+		t[cfg] = r.Next(cfg&center != 0, bits.OnesCount(uint(cfg&^center)))
+	}
+	return t
+}
+
+// stepTable writes rows y0 to y1-1 of the generation following src under
+// the rule of table t into dst, which has the same dimensions, sliding the
+// configuration of each cell along the row by three bits at a time.
+func stepTable(dst, src *Field, t *ruleTable, y0, y1 int) {
+	column := func(x, y int) int {
+		c := 0
+		for dy := -1; dy <= 1; dy++ {
+			c <<= 1
+			if src.Alive(x, y+dy) {
+				c |= 1
+			}
+		}
+		return c
+	}
+	for y := y0; y < y1; y++ {
+		cfg := column(-1, y)<<3 | column(0, y)
+		for x := 0; x < src.width; x++ {
+			cfg = (cfg<<3 | column(x+1, y)) & 511
+			dst.set(x, y, t[cfg])
+		}
+	}
+}
+
+// transitionTable tabulates a transition function for each state of a cell
+// and each number of live neighbors.
+func transitionTable(next func(self bool, neighbors int) bool) (t [2][9]bool) {
+	for n := 0; n <= 8; n++ {
+		t[0][n], t[1][n] = next(false, n), next(true, n)
+	}
+	return t
+}
@@ -0,0 +1,22 @@
+package life
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestStepTable checks the lookup-table engine, which steps the fields
+// whose edges are joined with a twist or a shift, under random rules,
+// including B0 ones.
+func TestStepTable(t *testing.T) {
+	rng := rand.New(rand.NewSource(16))
+	tops := []Topology{KleinBottle, MobiusBand, ProjectivePlane, {X: Wrap, Y: Wrap, ShiftX: 2}}
+	for i := 0; i < 24; i++ {
+		r := Rule{birth: uint16(rng.Intn(512)), survival: uint16(rng.Intn(512))}
+		top := tops[i%len(tops)]
+		f := randomField(70, 20, int64(i))
+		f.SetRule(r)
+		f.SetTopology(top)
+		checkSteps(t, r.String()+" on "+top.String(), NewLifeFromField(f), 3)
+	}
+}
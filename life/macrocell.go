@@ -0,0 +1,302 @@
+package life
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// mcHeader is the first line of a macrocell file.
+const mcHeader = "[M2]"
+
+// mcNode is a node of the quadtree described by a macrocell file. Leaf
+// nodes hold an 8x8 block of cells, one row per byte with the leftmost cell
+// in the low bit; other nodes hold the indexes of their four quadrants.
+type mcNode struct {
+	level int
+	leaf  [8]uint8
+	kids  [4]int // nw, ne, sw, se; 0 is the empty node
+}
+
+// LoadMacrocell reads a pattern in Golly's macrocell (.mc) format, a
+// hash-consed quadtree in which identical subpatterns are stored only once.
+// The pattern is translated so that its bounding box starts at the origin of
+// the returned field, which is rasterized from a HashLife universe built
+// from the nodes of the file. Patterns whose bounding box exceeds
+// MaxPatternCells are rejected; use LoadMacrocellHashLife for patterns too
+// large to expand. The field follows the rule given by the #R line, or the
+// Conway rule if there is none, and holds the generation given by the #G
+// line, as written by Golly.
+func LoadMacrocell(r io.Reader) (*Field, error) {
+	return loadMacrocell(r, MaxPatternCells)
+}
+
+// loadMacrocell is LoadMacrocell for patterns of at most maxCells cells.
+func loadMacrocell(r io.Reader, maxCells int) (*Field, error) {
+	h, rule, err := readMacrocell(r)
+	if err != nil {
+		return nil, err
+	}
+	b := h.Bounds()
+	if err := checkArea(b.Dx(), b.Dy(), maxCells); err != nil {
+		return nil, err
+	}
+	f := h.Field(b)
+	f.rule, f.top, f.gen = rule, Torus, h.gen
+	return f, nil
+}
+
+// LoadMacrocellHashLife reads a pattern in Golly's macrocell format into a
+// HashLife universe whose quadtree is built node for node from that of the
+// file, so that it takes memory in proportion to the size of the file
+// rather than to the area of the pattern. The root of the file is centered
+// on the origin, as in Golly. The universe follows the rule given by the #R
+// line and starts from the generation given by the #G line.
+func LoadMacrocellHashLife(r io.Reader) (*HashLife, error) {
+	h, rule, err := readMacrocell(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.SetRule(rule); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// readMacrocell reads a pattern in macrocell format into a HashLife
+// universe following the Conway rule, returning the rule of the file
+// separately as HashLife may not support it.
+func readMacrocell(r io.Reader) (*HashLife, Rule, error) {
+	sc := bufio.NewScanner(r)
+	nodes := []mcNode{{}} // index 0 is the empty node
+	rule := Conway
+	var gen int64
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if g, ok := strings.CutPrefix(line, "#G"); ok {
+			var err error
+			if gen, err = strconv.ParseInt(strings.TrimSpace(g), 10, 64); err != nil {
+				return nil, Rule{}, fmt.Errorf("life: invalid macrocell generation on line %d: %q", n, line)
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "#R") {
+			var err error
+			if rule, err = ParseRule(line[2:]); err != nil {
+				return nil, Rule{}, err
+			}
+			continue
+		}
+		if line == "" || line[0] == '#' || line[0] == '[' {
+			continue
+		}
+		if line[0] == '.' || line[0] == '*' || line[0] == '$' {
+			nd := mcNode{level: 3}
+			x, y := 0, 0
+			for i := 0; i < len(line); i++ {
+				switch line[i] {
+				case '.':
+					x++
+				case '*':
+					if x >= 8 || y >= 8 {
+						return nil, Rule{}, fmt.Errorf("life: macrocell leaf on line %d exceeds 8x8", n)
+					}
+					nd.leaf[y] |= 1 << x
+					x++
+				case '$':
+					x, y = 0, y+1
+				default:
+					return nil, Rule{}, fmt.Errorf("life: unexpected character %q in macrocell line %d", line[i], n)
+				}
+			}
+			nodes = append(nodes, nd)
+			continue
+		}
+		var nd mcNode
+		k := &nd.kids
+		if _, err := fmt.Sscan(line, &nd.level, &k[0], &k[1], &k[2], &k[3]); err != nil {
+			return nil, Rule{}, fmt.Errorf("life: invalid macrocell node on line %d: %q", n, line)
+		}
+		if nd.level < 4 || nd.level > 62 {
+			return nil, Rule{}, fmt.Errorf("life: unsupported macrocell node level %d on line %d", nd.level, n)
+		}
+		for _, id := range k {
+			if id < 0 || id >= len(nodes) || id > 0 && nodes[id].level != nd.level-1 {
+				return nil, Rule{}, fmt.Errorf("life: invalid macrocell child %d on line %d", id, n)
+			}
+		}
+		nodes = append(nodes, nd)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, Rule{}, err
+	}
+	h := NewHashLife()
+	h.gen = gen
+	if len(nodes) == 1 {
+		return h, rule, nil
+	}
+	// Nodes only refer to those before them, so they are built in order.
+	built := make([]*node, len(nodes))
+	for id := 1; id < len(nodes); id++ {
+		nd := &nodes[id]
+		if nd.level == 3 {
+			built[id] = h.leafNode(&nd.leaf, 0, 0, 3)
+			continue
+		}
+		var q [4]*node
+		for i, k := range nd.kids {
+			if k == 0 {
+				q[i] = h.emptyNode(uint(nd.level - 1))
+			} else {
+				q[i] = built[k]
+			}
+		}
+		built[id] = h.join(q[0], q[1], q[2], q[3])
+	}
+	h.root = built[len(built)-1]
+	return h, rule, nil
+}
+
+// leafNode returns the node of the given level holding the cells of the
+// macrocell leaf rows from x, y.
+func (h *HashLife) leafNode(rows *[8]uint8, x, y int, level uint) *node {
+	if level == 0 {
+		return h.cells[rows[y]>>x&1]
+	}
+	q := 1 << (level - 1)
+	return h.join(h.leafNode(rows, x, y, level-1), h.leafNode(rows, x+q, y, level-1),
+		h.leafNode(rows, x, y+q, level-1), h.leafNode(rows, x+q, y+q, level-1))
+}
+
+// WriteMacrocell writes the field to w in Golly's macrocell format.
+func (f *Field) WriteMacrocell(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, mcHeader, "(gol)")
+	fmt.Fprintln(bw, "#R", f.rule)
+	if f.gen != 0 {
+		fmt.Fprintln(bw, "#G", f.gen)
+	}
+
+	level := 3
+	for 1<<level < max(f.width, f.h) {
+		level++
+	}
+	ids := make(map[string]int)
+	var node func(x, y, level int) int
+	node = func(x, y, level int) int {
+		var line string
+		if level == 3 {
+			line = f.mcLeaf(x, y)
+			if line == "" {
+				return 0
+			}
+		} else {
+			half := 1 << (level - 1)
+			nw, ne := node(x, y, level-1), node(x+half, y, level-1)
+			sw, se := node(x, y+half, level-1), node(x+half, y+half, level-1)
+			if nw|ne|sw|se == 0 {
+				return 0
+			}
+			line = fmt.Sprintf("%d %d %d %d %d", level, nw, ne, sw, se)
+		}
+		if id, ok := ids[line]; ok {
+			return id
+		}
+		ids[line] = len(ids) + 1
+		fmt.Fprintln(bw, line)
+		return len(ids)
+	}
+	if node(0, 0, level) == 0 {
+		// An empty pattern still needs a root node.
+		fmt.Fprintf(bw, "%d 0 0 0 0\n", max(level, 4))
+	}
+	return bw.Flush()
+}
+
+// mcLeaf returns the macrocell leaf line for the 8x8 block of cells whose
+// top-left corner is at x, y, or "" if the block is empty.
+func (f *Field) mcLeaf(x0, y0 int) string {
+	var rows [8]uint8
+	for y := y0; y < y0+8 && y < f.h; y++ {
+		for x := x0; x < x0+8 && x < f.width; x++ {
+			if f.get(x, y) {
+				rows[y-y0] |= 1 << (x - x0)
+			}
+		}
+	}
+	return mcLeafLine(&rows)
+}
+
+// mcLeafLine returns the macrocell leaf line for the 8x8 block of cells
+// held in rows as in an mcNode, or "" if the block is empty.
+func mcLeafLine(rows *[8]uint8) string {
+	var b strings.Builder
+	n := 0 // rows since the last one written
+	for _, r := range rows {
+		var row strings.Builder
+		for x := 0; x < 8; x++ {
+			c := byte('.')
+			if r&(1<<x) != 0 {
+				c = '*'
+			}
+			row.WriteByte(c)
+		}
+		if line := strings.TrimRight(row.String(), "."); line != "" {
+			for ; n > 0; n-- {
+				b.WriteByte('$')
+			}
+			b.WriteString(line)
+		}
+		n++
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	b.WriteByte('$')
+	return b.String()
+}
+
+// WriteMacrocell writes the universe to w in Golly's macrocell format node
+// for node from its quadtree, without expanding it, the root being centered
+// on the origin as LoadMacrocellHashLife expects.
+func (h *HashLife) WriteMacrocell(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, mcHeader, "(gol)")
+	fmt.Fprintln(bw, "#R", h.rule)
+	if h.gen != 0 {
+		fmt.Fprintln(bw, "#G", h.gen)
+	}
+	ids := make(map[*node]int)
+	var write func(n *node) int
+	write = func(n *node) int {
+		if n.pop == 0 {
+			return 0
+		}
+		if id, ok := ids[n]; ok {
+			return id
+		}
+		var line string
+		if n.level == 3 {
+			var rows [8]uint8
+			for y := range rows {
+				for x := 0; x < 8; x++ {
+					if nodeAlive(n, x, y) {
+						rows[y] |= 1 << x
+					}
+				}
+			}
+			line = mcLeafLine(&rows)
+		} else {
+			line = fmt.Sprintf("%d %d %d %d %d", n.level, write(n.nw), write(n.ne), write(n.sw), write(n.se))
+		}
+		ids[n] = len(ids) + 1
+		fmt.Fprintln(bw, line)
+		return len(ids)
+	}
+	if write(h.root) == 0 {
+		fmt.Fprintf(bw, "%d 0 0 0 0\n", max(h.root.level, 4))
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,82 @@
+package life
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Margolus is a block cellular automaton on the Margolus neighborhood: the
+// field is partitioned into 2x2 blocks, each of which is replaced according
+// to a table, and the partition shifts by one cell diagonally between
+// successive steps. Reversible rules such as Critters and the billiard-ball
+// machine are of this kind. Margolus implements Stepper; the same value
+// must be used for all steps of a game, as it tracks the partition. The
+// field should have even dimensions, and wraps toroidally whatever its
+// topology.
+type Margolus struct {
+	// Table gives the new contents of a block for each of its 16 possible
+	// contents, encoded with 1 for the top-left cell, 2 for the top-right,
+	// 4 for the bottom-left and 8 for the bottom-right.
+	Table [16]uint8
+	odd   bool // whether the next step uses the shifted partition
+}
+
+// ParseMargolus parses a Margolus rule written in Golly's notation: "M"
+// followed by the 16 entries of the table separated by commas, such as
+// "M15,14,13,3,11,5,6,1,7,9,10,2,12,4,8,0" for Critters.
+func ParseMargolus(s string) (*Margolus, error) {
+	t := strings.TrimSpace(s)
+	if !strings.HasPrefix(t, "M") && !strings.HasPrefix(t, "m") {
+		return nil, fmt.Errorf("life: invalid Margolus rule %q", s)
+	}
+	parts := strings.Split(t[1:], ",")
+	if len(parts) != 16 {
+		return nil, fmt.Errorf("life: Margolus rule %q must have 16 entries", s)
+	}
+	m := new(Margolus)
+	for i, p := range parts {
+		v, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || v < 0 || v > 15 {
+			return nil, fmt.Errorf("life: invalid Margolus rule %q", s)
+		}
+		m.Table[i] = uint8(v)
+	}
+	return m, nil
+}
+
+// String returns the rule in Golly's notation.
+func (m *Margolus) String() string {
+	var b strings.Builder
+	b.WriteByte('M')
+	for i, v := range m.Table {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Itoa(int(v)))
+	}
+	return b.String()
+}
+
+// Step implements Stepper.
+func (m *Margolus) Step(dst, src *Field) {
+	off := 0
+	if m.odd {
+		off = 1
+	}
+	m.odd = !m.odd
+	for y := off; y < src.h+off; y += 2 {
+		for x := off; x < src.width+off; x += 2 {
+			v := 0
+			for i, d := range [4][2]int{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+				if src.get((x+d[0])%src.width, (y+d[1])%src.h) {
+					v |= 1 << i
+				}
+			}
+			v = int(m.Table[v])
+			for i, d := range [4][2]int{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+				dst.set((x+d[0])%src.width, (y+d[1])%src.h, v&(1<<i) != 0)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package life
+
+import (
+	"slices"
+	"testing"
+)
+
+// critters is the Critters rule in Golly's notation.
+const critters = "M15,14,13,3,11,5,6,1,7,9,10,2,12,4,8,0"
+
+// TestParseMargolus checks the notation of Margolus rules.
+func TestParseMargolus(t *testing.T) {
+	m, err := ParseMargolus(critters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.String() != critters {
+		t.Errorf("String() = %q, want %q", m, critters)
+	}
+	for _, in := range []string{"15,14,13,3,11,5,6,1,7,9,10,2,12,4,8,0", "M1,2,3", "M15,14,13,3,11,5,6,1,7,9,10,2,12,4,8,16", "M15,14,13,3,11,5,6,1,7,9,10,2,12,4,8,x"} {
+		if _, err := ParseMargolus(in); err == nil {
+			t.Errorf("ParseMargolus(%q): no error", in)
+		}
+	}
+}
+
+// TestMargolusStep checks that the partition alternates between steps,
+// moving a cell pushed to the opposite corner of its block diagonally
+// across the torus, and that Critters is undone by its inverse table.
+func TestMargolusStep(t *testing.T) {
+	// Blocks holding only their top-left cell move it to the bottom right.
+	var m Margolus
+	for i := range m.Table {
+		m.Table[i] = uint8(i)
+	}
+	m.Table[1] = 8
+	grid := NewLifeFromField(fieldOf(t, Torus, "O...", "....", "....", "...."))
+	grid.SetStepper(&m)
+	for gen := 1; gen <= 4; gen++ {
+		grid.Step()
+		if got, want := liveCells(grid.Field()), pts(gen%4, gen%4); !slices.Equal(got, want) {
+			t.Errorf("generation %d: cells %v, want %v", gen, got, want)
+		}
+	}
+
+	c, err := ParseMargolus(critters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed := soup("B3/S23")
+	grid = NewLifeFromField(seed.Clone())
+	grid.SetStepper(c)
+	grid.StepN(11)
+	if grid.Field().Equal(seed) {
+		t.Fatal("Critters left the soup unchanged")
+	}
+	// The inverse steps with the partitions of the steps it undoes, in
+	// reverse order.
+	inv := &Margolus{odd: !c.odd}
+	for i, v := range c.Table {
+		inv.Table[v] = uint8(i)
+	}
+	grid.SetStepper(inv)
+	grid.StepN(11)
+	if !grid.Field().Equal(seed) {
+		t.Error("Critters was not undone by its inverse")
+	}
+}
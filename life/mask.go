@@ -0,0 +1,255 @@
+package life
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// MaskRule is an outer-totalistic rule over an arbitrary neighborhood: the
+// cells counted as neighbors are those at the offsets set in a square mask
+// centered on the cell, so that rules may favor some directions over
+// others. A dead cell is born if the number of its live neighbors is in
+// the birth set and a live cell survives if it is in the survival set.
+// MaskRule implements Stepper.
+type MaskRule struct {
+	radius          int
+	mask            [][]bool // [dy+radius][dx+radius], center unset
+	birth, survival uint64   // bit n is set if n live neighbors give birth or survival
+}
+
+// maxMaskRadius is the largest radius of a mask, for which there are 48
+// offsets.
+const maxMaskRadius = 3
+
+// NewMaskRule returns the rule counting the offsets set in mask, a square
+// of odd side of at most 7 whose center is the cell itself and is ignored,
+// with the birth and survival counts of r. Counts above 8 are not
+// available in r; use ParseMaskRule for larger masks.
+func NewMaskRule(r Rule, mask [][]bool) (*MaskRule, error) {
+	m := &MaskRule{birth: uint64(r.birth), survival: uint64(r.survival)}
+	if err := m.setMask(mask); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// setMask validates and copies mask into the rule.
+func (m *MaskRule) setMask(mask [][]bool) error {
+	n := len(mask)
+	if n%2 == 0 || n > 2*maxMaskRadius+1 {
+		return fmt.Errorf("life: invalid neighbor mask of side %d", n)
+	}
+	m.radius = n / 2
+	m.mask = make([][]bool, n)
+	for j, row := range mask {
+		if len(row) != n {
+			return fmt.Errorf("life: neighbor mask is not square")
+		}
+		m.mask[j] = append([]bool(nil), row...)
+	}
+	m.mask[m.radius][m.radius] = false
+	return nil
+}
+
+// ParseMask parses a neighbor mask written as rows separated by slashes,
+// with '1' or '#' for the offsets that count and '0' or '.' for the others,
+// such as "111/101/000" for the five neighbors above and beside a cell.
+func ParseMask(s string) ([][]bool, error) {
+	var mask [][]bool
+	for _, line := range strings.Split(strings.TrimSpace(s), "/") {
+		row := make([]bool, len(line))
+		for i := 0; i < len(line); i++ {
+			switch line[i] {
+			case '1', '#':
+				row[i] = true
+			case '0', '.':
+			default:
+				return nil, fmt.Errorf("life: invalid neighbor mask %q", s)
+			}
+		}
+		mask = append(mask, row)
+	}
+	return mask, nil
+}
+
+// ParseMaskRule parses a rule in B/S notation followed by "N@" and its
+// mask in hexadecimal, such as "B3/S23N@F78" for the Conway rule. The
+// mask gives the offsets of a square of side 3, 5 or 7 in row-major order,
+// one bit each with the most significant first, padded with zero bits to a
+// whole number of hex digits; the side is that which requires as many
+// digits as given. The bit of the cell itself is ignored. Neighbor counts of 10 or more must be
+// separated by commas, as in "B3,10/S2,3N@...".
+func ParseMaskRule(s string) (*MaskRule, error) {
+	bad := func() (*MaskRule, error) { return nil, fmt.Errorf("life: invalid mask rule %q", s) }
+	u := strings.ToUpper(strings.TrimSpace(s))
+	bs, hex, ok := strings.Cut(u, "N@")
+	p, q, ok2 := strings.Cut(bs, "/")
+	if !ok || !ok2 || !strings.HasPrefix(p, "B") || !strings.HasPrefix(q, "S") {
+		return bad()
+	}
+	var mask [][]bool
+	for n := 3; n <= 2*maxMaskRadius+1; n += 2 {
+		if (n*n+3)/4 != len(hex) {
+			continue
+		}
+		v := make([]bool, 0, len(hex)*4)
+		for i := 0; i < len(hex); i++ {
+			d, err := strconv.ParseUint(hex[i:i+1], 16, 4)
+			if err != nil {
+				return bad()
+			}
+			for b := 3; b >= 0; b-- {
+				v = append(v, d>>b&1 == 1)
+			}
+		}
+		for j := 0; j < n; j++ {
+			mask = append(mask, v[j*n:(j+1)*n])
+		}
+	}
+	if mask == nil {
+		return bad()
+	}
+	m := &MaskRule{}
+	if err := m.setMask(mask); err != nil {
+		return nil, err
+	}
+	var err error
+	if m.birth, err = m.parseCounts(p[1:]); err != nil {
+		return bad()
+	}
+	if m.survival, err = m.parseCounts(q[1:]); err != nil {
+		return bad()
+	}
+	return m, nil
+}
+
+// parseFileRule parses the rule named in a saved pattern or game, either
+// in the notation of ParseRule or, for a masked rule, of ParseMaskRule.
+func parseFileRule(s string) (Rule, *MaskRule, error) {
+	r, err := ParseRule(s)
+	if err == nil {
+		return r, nil, nil
+	}
+	m, merr := ParseMaskRule(s)
+	if merr != nil {
+		return Rule{}, nil, err
+	}
+	return Conway, m, nil
+}
+
+// ruleName returns the rule the field follows, as parseFileRule reads it.
+func (f *Field) ruleName() string {
+	if f.mask != nil {
+		return f.mask.String()
+	}
+	return f.rule.String()
+}
+
+// parseCounts parses a set of neighbor counts, written as digits or, if it
+// contains commas, as comma-separated numbers.
+func (m *MaskRule) parseCounts(s string) (uint64, error) {
+	var counts []string
+	if strings.Contains(s, ",") {
+		counts = strings.Split(s, ",")
+	} else {
+		counts = strings.Split(s, "")
+	}
+	var c uint64
+	for _, f := range counts {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 || n > m.Neighbors() || c&(1<<n) != 0 {
+			return 0, fmt.Errorf("invalid neighbor count %q", f)
+		}
+		c |= 1 << n
+	}
+	return c, nil
+}
+
+// Neighbors returns the number of offsets in the mask of the rule.
+func (m *MaskRule) Neighbors() int {
+	n := 0
+	for _, row := range m.mask {
+		for _, b := range row {
+			if b {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Mask returns a copy of the neighbor mask of the rule.
+func (m *MaskRule) Mask() [][]bool {
+	mask := make([][]bool, len(m.mask))
+	for j, row := range m.mask {
+		mask[j] = append([]bool(nil), row...)
+	}
+	return mask
+}
+
+// String returns the rule in the notation of ParseMaskRule.
+func (m *MaskRule) String() string {
+	var b strings.Builder
+	sep := ""
+	if bits.Len64(m.birth|m.survival) > 10 {
+		sep = ","
+	}
+	write := func(c uint64) {
+		first := true
+		for n := 0; c>>n != 0; n++ {
+			if c&(1<<n) != 0 {
+				if !first {
+					b.WriteString(sep)
+				}
+				b.WriteString(strconv.Itoa(n))
+				first = false
+			}
+		}
+	}
+	b.WriteByte('B')
+	write(m.birth)
+	b.WriteString("/S")
+	write(m.survival)
+	b.WriteString("N@")
+	var d, nb byte
+	for _, row := range m.mask {
+		for _, v := range row {
+			d <<= 1
+			if v {
+				d |= 1
+			}
+			if nb++; nb == 4 {
+				b.WriteByte("0123456789ABCDEF"[d])
+				d, nb = 0, 0
+			}
+		}
+	}
+	if nb > 0 {
+		b.WriteByte("0123456789ABCDEF"[d<<(4-nb)])
+	}
+	return b.String()
+}
+
+// Step implements Stepper.
+func (m *MaskRule) Step(dst, src *Field) {
+	r := m.radius
+	for y := 0; y < src.h; y++ {
+		for x := 0; x < src.width; x++ {
+			n := 0
+			for j, row := range m.mask {
+				for i, v := range row {
+					if v && src.Alive(x+i-r, y+j-r) {
+						n++
+					}
+				}
+			}
+			c := m.birth
+			if src.get(x, y) {
+				c = m.survival
+			}
+			dst.set(x, y, c&(1<<n) != 0)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package life
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+// TestMaskRule checks that a full Moore mask steps as the rule without
+// one, and that a directional mask moves a cell along its one offset.
+func TestMaskRule(t *testing.T) {
+	m, err := ParseMaskRule("B3/S23N@F78")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.String(); got != "B3/S23N@F78" || m.Neighbors() != 8 {
+		t.Errorf("String() = %q with %d neighbors, want B3/S23N@F78 with 8", got, m.Neighbors())
+	}
+	want := NewLifeFromField(fieldOf(t, Torus, "......", ".O....", "..O...", "OOO...", "......"))
+	grid := want.Clone()
+	grid.SetStepper(m)
+	for gen := 1; gen <= 8; gen++ {
+		want.Step()
+		grid.Step()
+		if !slices.Equal(liveCells(grid.Field()), liveCells(want.Field())) {
+			t.Fatalf("generation %d: cells %v, want %v", gen, liveCells(grid.Field()), liveCells(want.Field()))
+		}
+	}
+
+	// Only the cell to the west counts: a lone cell moves east each step.
+	east, err := ParseMaskRule("B1/SN@100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid = NewLifeFromField(fieldOf(t, Torus, "O...", "...."))
+	grid.SetStepper(east)
+	grid.StepN(2)
+	if got, want := liveCells(grid.Field()), pts(2, 0); !slices.Equal(got, want) {
+		t.Errorf("after 2 steps: cells %v, want %v", got, want)
+	}
+
+	for _, s := range []string{"B3/S23", "B3/S23N@F7", "B3/S23N@G78", "B9/S23N@F78"} {
+		if _, err := ParseMaskRule(s); err == nil {
+			t.Errorf("ParseMaskRule(%q): no error", s)
+		}
+	}
+}
+
+// TestMaskRuleSave checks that the mask of a game survives saving it to a
+// bounded RLE file and encoding it as JSON, and that the restored games
+// replay the same generations.
+func TestMaskRuleSave(t *testing.T) {
+	m, err := ParseMaskRule("B3/S23N@F08")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid := NewLifeFromField(fieldOf(t, Plane, "........", ".O......", "..O.....", "OOO.....", "........", "........"))
+	grid.SetStepper(m)
+	grid.Step()
+
+	var rle bytes.Buffer
+	if err := grid.State().WriteRLE(&rle); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(rle.Bytes(), []byte("rule = "+m.String())) {
+		t.Errorf("RLE header does not hold the mask:\n%s", rle.Bytes())
+	}
+	f, err := LoadRLE(&rle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON Life
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name string
+		g    *Life
+	}{
+		{"RLE", NewLifeFromField(f)},
+		{"JSON", &fromJSON},
+	} {
+		s, ok := tt.g.Stepper().(*MaskRule)
+		if !ok || s.String() != m.String() {
+			t.Errorf("%s: Stepper() = %v, want %v", tt.name, tt.g.Stepper(), m)
+			continue
+		}
+		want := grid.Clone()
+		for gen := 1; gen <= 6; gen++ {
+			want.Step()
+			tt.g.Step()
+			if got := liveCells(tt.g.State()); !slices.Equal(got, liveCells(want.State())) {
+				t.Errorf("%s: generation %d: cells %v, want %v", tt.name, gen, got, liveCells(want.State()))
+				break
+			}
+		}
+	}
+}
+
+// TestMaskRuleSnapshot checks that fields made from snapshots of a masked
+// game save its mask too.
+func TestMaskRuleSnapshot(t *testing.T) {
+	m, err := ParseMaskRule("B3/S23N@F08")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid := NewLifeFromField(fieldOf(t, Torus, ".O..", "..O.", "OOO."))
+	grid.SetStepper(m)
+	var rle bytes.Buffer
+	if err := grid.Field().Snapshot().Field().WriteRLE(&rle); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(rle.Bytes(), []byte("rule = "+m.String())) {
+		t.Errorf("RLE header does not hold the mask:\n%s", rle.Bytes())
+	}
+}
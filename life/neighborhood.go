@@ -0,0 +1,106 @@
+package life
+
+// A NeighborhoodFunc returns the number of live neighbors of the specified
+// cell of a field. Rules are applied to the count it returns, so it must
+// not exceed 8.
+type NeighborhoodFunc func(f *Field, x, y int) int
+
+// Moore counts the live cells among the eight cells surrounding the
+// specified cell.
+func Moore(f *Field, x, y int) int {
+	alive := 0
+	for i := -1; i <= 1; i++ {
+		for j := -1; j <= 1; j++ {
+			if (j != 0 || i != 0) && f.Alive(x+i, y+j) {
+				alive++
+			}
+		}
+	}
+	return alive
+}
+
+// VonNeumann counts the live cells among the four cells orthogonally
+// adjacent to the specified cell.
+func VonNeumann(f *Field, x, y int) int {
+	alive := 0
+	for _, d := range [4][2]int{{0, -1}, {-1, 0}, {1, 0}, {0, 1}} {
+		if f.Alive(x+d[0], y+d[1]) {
+			alive++
+		}
+	}
+	return alive
+}
+
+// Neighborhood holds the states of a cell and the eight cells surrounding
+// it: the cell at offset dx, dy from it is at [dy+1][dx+1], putting the
+// cell itself at [1][1].
+type Neighborhood [3][3]bool
+
+// Self reports whether the cell at the center of the neighborhood is
+// alive.
+func (n *Neighborhood) Self() bool {
+	return n[1][1]
+}
+
+// Count returns the number of live cells surrounding the center of the
+// neighborhood.
+func (n *Neighborhood) Count() int {
+	alive := 0
+	for j := range n {
+		for i, b := range n[j] {
+			if b && (i != 1 || j != 1) {
+				alive++
+			}
+		}
+	}
+	return alive
+}
+
+// Neighborhood returns the neighborhood of the specified cell, wrapping
+// around the edges of the field as Alive does.
+func (f *Field) Neighborhood(x, y int) Neighborhood {
+	var n Neighborhood
+	for j := -1; j <= 1; j++ {
+		for i := -1; i <= 1; i++ {
+			n[j+1][i+1] = f.Alive(x+i, y+j)
+		}
+	}
+	return n
+}
+
+// Neighbors returns the number of live cells among the eight cells
+// surrounding the specified cell, wrapping around the edges of the field as
+// Alive does.
+func (f *Field) Neighbors(x, y int) int {
+	return Moore(f, x, y)
+}
+
+// NeighborCounts returns the number of live neighbors of every cell of the
+// field, as Neighbors counts them, indexed by row and then by column. Live
+// cells add themselves to the counts of the cells around them away from the
+// edges of the field, and the cells along the edges, whose neighbors may be
+// mapped by the topology, are counted one by one.
+func (f *Field) NeighborCounts() [][]int {
+	counts := make([][]int, f.h)
+	for y := range counts {
+		counts[y] = make([]int, f.width)
+	}
+	for x, y := range f.LiveCells() {
+		for j := max(y-1, 1); j <= min(y+1, f.h-2); j++ {
+			for i := max(x-1, 1); i <= min(x+1, f.width-2); i++ {
+				if i != x || j != y {
+					counts[j][i]++
+				}
+			}
+		}
+	}
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; x++ {
+			if x == 1 && y > 0 && y < f.h-1 {
+				x = max(f.width-1, 1) // skip to the right edge
+			}
+			counts[y][x] = f.Neighbors(x, y)
+		}
+	}
+	return counts
+}
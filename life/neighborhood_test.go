@@ -0,0 +1,54 @@
+package life
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// TestSetNeighborhood checks that a game counts neighbors with the
+// NeighborhoodFunc set, and with the Moore neighborhood once it is cleared.
+func TestSetNeighborhood(t *testing.T) {
+	f := fieldOf(t, Torus, ".....", ".....", "..O..", ".....", ".....")
+	f.SetRule(MustParseRule("B1/S"))
+	grid := NewLifeFromField(f)
+	grid.SetNeighborhood(VonNeumann)
+	grid.Step()
+	if got, want := liveCells(grid.Field()), pts(2, 1, 1, 2, 3, 2, 2, 3); !slices.Equal(got, want) {
+		t.Errorf("von Neumann neighborhood: cells %v, want %v", got, want)
+	}
+	grid.SetNeighborhood(nil)
+	grid.Field().Clear()
+	grid.Field().Set(2, 2, true)
+	grid.Step()
+	if got, want := liveCells(grid.Field()), pts(1, 1, 2, 1, 3, 1, 1, 2, 3, 2, 1, 3, 2, 3, 3, 3); !slices.Equal(got, want) {
+		t.Errorf("Moore neighborhood: cells %v, want %v", got, want)
+	}
+}
+
+// TestNeighborCounts checks that NeighborCounts agrees with Neighbors on
+// every cell of random fields of several sizes, down to a single cell, and
+// topologies.
+func TestNeighborCounts(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for _, top := range []Topology{Torus, Plane, KleinBottle, MobiusBand, ProjectivePlane, {X: Mirror, Y: Wrap, ShiftX: 2}, {X: Wrap, Y: Wrap, ShiftY: 1}} {
+		for _, size := range [][2]int{{1, 1}, {1, 4}, {5, 2}, {3, 3}, {9, 7}, {70, 6}} {
+			f := NewFieldWithTopology(size[0], size[1], top)
+			for y := 0; y < f.Height(); y++ {
+				for x := 0; x < f.Width(); x++ {
+					f.Set(x, y, rng.Intn(2) == 0)
+				}
+			}
+			counts := f.NeighborCounts()
+		cells:
+			for y := 0; y < f.Height(); y++ {
+				for x := 0; x < f.Width(); x++ {
+					if got, want := counts[y][x], f.Neighbors(x, y); got != want {
+						t.Errorf("%v %d×%d: cell %d, %d has count %d, want %d", top, size[0], size[1], x, y, got, want)
+						break cells
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package life
+
+// An Observer is told of each generation a game steps to.
+type Observer interface {
+	// Observe is called at the end of each step with the number of the new
+	// generation and a field holding it with the true states of its cells,
+	// as Life.State returns them. The field must not be changed, nor kept
+	// beyond the call, as the game may reuse it; take a Snapshot to keep it.
+	Observe(gen int64, f *Field)
+}
+
+// An ObserverFunc is a function used as an Observer.
+type ObserverFunc func(gen int64, f *Field)
+
+// Observe calls fn(gen, f).
+func (fn ObserverFunc) Observe(gen int64, f *Field) { fn(gen, f) }
+
+// AddObserver makes the game tell o of each generation it steps to, after
+// the observers added before it.
+func (grid *Life) AddObserver(o Observer) {
+	grid.observers = append(grid.observers, o)
+}
@@ -0,0 +1,32 @@
+package life
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestAddObserver checks that observers are told of each generation in the
+// order they were added, with the true states of its cells.
+func TestAddObserver(t *testing.T) {
+	grid := NewLifeFromField(soup("B3/S23"))
+	grid.SetRule(MustParseRule("B03/S23"))
+	var calls []string
+	var gens []int64
+	grid.AddObserver(ObserverFunc(func(gen int64, f *Field) {
+		calls = append(calls, "first")
+		gens = append(gens, gen)
+		if !f.Equal(grid.State()) {
+			t.Errorf("generation %d: observed field differs from the state of the game", gen)
+		}
+	}))
+	grid.AddObserver(ObserverFunc(func(gen int64, f *Field) {
+		calls = append(calls, "second")
+	}))
+	grid.StepN(3)
+	if want := []int64{1, 2, 3}; !slices.Equal(gens, want) {
+		t.Errorf("observed generations %v, want %v", gens, want)
+	}
+	if want := []string{"first", "second", "first", "second", "first", "second"}; !slices.Equal(calls, want) {
+		t.Errorf("calls %v, want %v", calls, want)
+	}
+}
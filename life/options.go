@@ -0,0 +1,95 @@
+package life
+
+import (
+	"image"
+	"math/rand"
+)
+
+// An Option configures a game made by NewLife.
+type Option func(*config)
+
+// config is the configuration of a game gathered from the options given to
+// NewLife.
+type config struct {
+	seed     *Field      // initial cells, nil for a random soup
+	density  float64     // probability of each cell of a random soup being alive
+	src      rand.Source // source of a random soup, nil for the global one
+	settings []func(grid *Life)
+}
+
+// WithSeed makes the game start from the cells of f, and from the
+// generation it holds, cropping them or adding dead cells to fit the size
+// of the game. The rule and topology of f are not taken; see WithRule and
+// WithTopology.
+func WithSeed(f *Field) Option {
+	return func(c *config) { c.seed = f }
+}
+
+// WithRandom makes the game start from a random soup in which each cell is
+// alive with probability density, drawn from src so that the same source
+// gives the same soup.
+func WithRandom(density float64, src rand.Source) Option {
+	return func(c *config) { c.density, c.src = density, src }
+}
+
+// WithRule makes the game follow the rule r.
+func WithRule(r Rule) Option {
+	return setting(func(grid *Life) { grid.SetRule(r) })
+}
+
+// WithTopology gives the field of the game the topology t.
+func WithTopology(t Topology) Option {
+	return setting(func(grid *Life) { grid.SetTopology(t) })
+}
+
+// WithEngine makes the game compute each generation with s, as SetStepper
+// does.
+func WithEngine(s Stepper) Option {
+	return setting(func(grid *Life) { grid.SetStepper(s) })
+}
+
+// WithWorkers makes the game compute each generation with n goroutines, as
+// SetWorkers does.
+func WithWorkers(n int) Option {
+	return setting(func(grid *Life) { grid.SetWorkers(n) })
+}
+
+// WithHistory makes the game keep up to n past generations, as SetHistory
+// does.
+func WithHistory(n int) Option {
+	return setting(func(grid *Life) { grid.SetHistory(n) })
+}
+
+// setting returns an option applying set to the game once it is made.
+func setting(set func(grid *Life)) Option {
+	return func(c *config) { c.settings = append(c.settings, set) }
+}
+
+// field returns the initial field of a game of the given size.
+func (c *config) field(width, h int) *Field {
+	a := NewField(width, h)
+	switch {
+	case c.seed != nil:
+		s := c.seed.Crop(image.Rect(0, 0, width, h))
+		for y := 0; y < s.h; y++ {
+			for i := 0; i < s.stride; i++ {
+				a.bits[a.index(i, y)] = s.bits[s.index(i, y)]
+			}
+		}
+		a.gen = c.seed.gen
+	case c.src != nil:
+		r := rand.New(c.src)
+		for y := 0; y < h; y++ {
+			for x := 0; x < width; x++ {
+				if r.Float64() < c.density {
+					a.set(x, y, true)
+				}
+			}
+		}
+	default:
+		for i := 0; i < (width * h / 4); i++ {
+			a.Set(rand.Intn(width), rand.Intn(h), true)
+		}
+	}
+	return a
+}
@@ -0,0 +1,41 @@
+package life
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// TestNewLifeOptions checks that the options given to NewLife configure the
+// game they make, and that a seed is cropped or padded to its size.
+func TestNewLifeOptions(t *testing.T) {
+	seed := fieldOf(t, Torus, ".O..", "..O.", "OOO.")
+	seed.SetGeneration(7)
+	if grid := NewLife(2, 5, WithSeed(seed)); grid.Generation() != 7 || !slices.Equal(liveCells(grid.Field()), pts(1, 0, 0, 2, 1, 2)) {
+		t.Errorf("seed cropped to 2×5: generation %d, cells %v", grid.Generation(), liveCells(grid.Field()))
+	}
+	highLife := MustParseRule("B36/S23")
+	mask, err := ParseMaskRule("B1/SN@100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid := NewLife(6, 5, WithSeed(seed), WithRule(highLife), WithTopology(Plane), WithWorkers(3), WithHistory(2), WithEngine(mask))
+	if got := liveCells(grid.Field()); grid.Field().Width() != 6 || grid.Field().Height() != 5 || !slices.Equal(got, glider) {
+		t.Errorf("seed padded to 6×5: %d×%d field with cells %v", grid.Field().Width(), grid.Field().Height(), got)
+	}
+	if grid.Field().Rule() != highLife || grid.Field().Topology() != Plane || grid.Workers() != 3 || grid.Stepper() != mask {
+		t.Errorf("game with rule %v, topology %v, %d workers and Stepper %v", grid.Field().Rule(), grid.Field().Topology(), grid.Workers(), grid.Stepper())
+	}
+	grid.StepN(3)
+	if !grid.StepBack() || !grid.StepBack() || grid.StepBack() {
+		t.Error("game did not keep 2 generations of history")
+	}
+	a := NewLife(30, 20, WithRandom(0.3, rand.NewSource(5)))
+	b := NewLife(30, 20, WithRandom(0.3, rand.NewSource(5)))
+	if !a.Field().Equal(b.Field()) {
+		t.Error("soups drawn from sources seeded alike differ")
+	}
+	if n := a.Population(); n < 130 || n > 230 {
+		t.Errorf("soup of density 0.3: population %d of 600", n)
+	}
+}
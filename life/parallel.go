@@ -0,0 +1,49 @@
+package life
+
+import (
+	"runtime"
+	"sync"
+)
+
+// minBand is the smallest number of rows a worker is given, below which
+// splitting a board costs more than it saves.
+const minBand = 16
+
+// SetWorkers sets the number of goroutines that compute each generation of
+// the game, each taking a horizontal band of the board; 0, the default,
+// uses GOMAXPROCS of them. Functions set by SetNeighborhood, SetTransition
+// and SetNeighborhoodTransition must then be safe to call concurrently,
+// unless n is 1. A Stepper always runs on the calling goroutine.
+func (grid *Life) SetWorkers(n int) {
+	grid.workers = max(n, 0)
+}
+
+// Workers returns the number of goroutines that compute each generation.
+func (grid *Life) Workers() int {
+	if grid.workers == 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return grid.workers
+}
+
+// bands calls rows for consecutive bands of the rows of the board, which
+// together cover it, from as many goroutines as there are workers. Bands
+// write to disjoint rows of the next field and only read the current one,
+// so they need no synchronization.
+func (grid *Life) bands(rows func(y0, y1 int)) {
+	n := min(grid.Workers(), grid.h/minBand)
+	if n <= 1 {
+		rows(0, grid.h)
+		return
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		y0, y1 := i*grid.h/n, (i+1)*grid.h/n
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rows(y0, y1)
+		}()
+	}
+	wg.Wait()
+}
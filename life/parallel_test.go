@@ -0,0 +1,51 @@
+package life
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+)
+
+// TestWorkersAgree checks that splitting the board into bands gives the
+// same generations as stepping it on one goroutine.
+func TestWorkersAgree(t *testing.T) {
+	seed := NewLife(200, 130, WithRandom(0.3, rand.NewSource(5))).Field()
+	single := NewLifeFromField(seed.Clone())
+	single.SetWorkers(1)
+	parallel := NewLifeFromField(seed.Clone())
+	parallel.SetWorkers(7)
+	for gen := 1; gen <= 100; gen++ {
+		single.Step()
+		parallel.Step()
+		if !single.Field().Equal(parallel.Field()) {
+			t.Fatalf("generation %d differs with 7 workers", gen)
+		}
+	}
+}
+
+// BenchmarkStep times a step of a random soup on a 2048×2048 torus with
+// increasing numbers of workers, up to GOMAXPROCS. The soup is restarted
+// every 100 generations, so that it never settles.
+func BenchmarkStep(b *testing.B) {
+	seed := NewLife(2048, 2048, WithRandom(0.3, rand.NewSource(1))).Field()
+	workers := []int{1, 2, 4}
+	if n := runtime.GOMAXPROCS(0); n > 4 {
+		workers = append(workers, n)
+	}
+	for _, n := range workers {
+		b.Run(fmt.Sprintf("workers=%d", n), func(b *testing.B) {
+			grid := NewLifeFromField(seed.Clone())
+			grid.SetWorkers(n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if i%100 == 0 {
+					b.StopTimer()
+					grid.Reset(seed)
+					b.StartTimer()
+				}
+				grid.Step()
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Package patterns provides a library of classic Game of Life patterns,
+// along with a few patterns for other rules.
+package patterns
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life"
+)
+
+//go:embed rle/*.rle
+var files embed.FS
+
+// Names returns the names of the patterns in the library in sorted order.
+func Names() []string {
+	entries, _ := files.ReadDir("rle")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".rle"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns a new field holding the named pattern.
+func Get(name string) (*life.Field, error) {
+	data, err := files.ReadFile(path.Join("rle", name+".rle"))
+	if err != nil {
+		return nil, fmt.Errorf("patterns: unknown pattern %q", name)
+	}
+	return life.LoadRLE(bytes.NewReader(data))
+}
+
+// GetGrid returns a new grid holding the named pattern with the states of
+// its cells, along with the rule named in its header. It is needed for
+// multi-state patterns such as brain-gliders.
+func GetGrid(name string) (*life.Grid, string, error) {
+	data, err := files.ReadFile(path.Join("rle", name+".rle"))
+	if err != nil {
+		return nil, "", fmt.Errorf("patterns: unknown pattern %q", name)
+	}
+	return life.LoadRLEGrid(bytes.NewReader(data))
+}
+
+// Describe returns the title and description of the named pattern, taken
+// from the #N and #C lines of its RLE file.
+func Describe(name string) (title, desc string, err error) {
+	data, err := files.ReadFile(path.Join("rle", name+".rle"))
+	if err != nil {
+		return "", "", fmt.Errorf("patterns: unknown pattern %q", name)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		switch {
+		case strings.HasPrefix(line, "#N "):
+			title = line[3:]
+		case strings.HasPrefix(line, "#C "):
+			desc = line[3:]
+		}
+	}
+	return title, desc, nil
+}
@@ -0,0 +1,56 @@
+package patterns
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestPatterns checks that every pattern of the library has a title, that
+// the glider loads, and that unknown names are rejected.
+func TestPatterns(t *testing.T) {
+	names := Names()
+	if !slices.IsSorted(names) || !slices.Contains(names, "glider") {
+		t.Fatalf("Names() = %v, want a sorted list including glider", names)
+	}
+	for _, name := range names {
+		if title, _, err := Describe(name); err != nil || title == "" {
+			t.Errorf("Describe(%q) = %q, %v; want a title", name, title, err)
+		}
+	}
+	f, err := Get("glider")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Width() != 3 || f.Height() != 3 || f.Population() != 5 {
+		t.Errorf("glider: %d×%d field of %d cells, want 3×3 of 5", f.Width(), f.Height(), f.Population())
+	}
+	if title, desc, _ := Describe("glider"); title != "Glider" || desc != "The smallest, most common spaceship." {
+		t.Errorf("Describe(\"glider\") = %q, %q", title, desc)
+	}
+	if _, err := Get("no-such-pattern"); err == nil {
+		t.Error("Get of an unknown pattern: no error")
+	}
+	if _, _, err := Describe("no-such-pattern"); err == nil {
+		t.Error("Describe of an unknown pattern: no error")
+	}
+}
+
+// TestGetGrid checks that every pattern of the library loads as a grid,
+// and that the Brian's Brain seed keeps its states and rule.
+func TestGetGrid(t *testing.T) {
+	for _, name := range Names() {
+		if _, _, err := GetGrid(name); err != nil {
+			t.Errorf("GetGrid(%q): %v", name, err)
+		}
+	}
+	g, rule, err := GetGrid("brain-gliders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule != "B2/S/C3" || g.Width() != 4 || g.Height() != 2 || g.At(0, 0) != 1 || g.At(1, 0) != 0 || g.At(3, 1) != 1 {
+		t.Errorf("brain-gliders: %d×%d grid under %q", g.Width(), g.Height(), rule)
+	}
+	if _, _, err := GetGrid("no-such-pattern"); err == nil {
+		t.Error("GetGrid of an unknown pattern: no error")
+	}
+}
@@ -0,0 +1,225 @@
+package life
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// LoadPBM reads a netpbm bitmap or graymap and returns it as a field. The
+// plain (P1) and raw (P4) bitmap formats are supported, in which set bits
+// are black and become live cells, as are the plain (P2) and raw (P5)
+// graymap formats, in which pixels darker than half the maximum value
+// become live cells. A generation recorded in a header comment, as WritePBM
+// and WritePGM record it, is read back. Images larger than MaxPatternCells
+// are rejected.
+func LoadPBM(r io.Reader) (*Field, error) {
+	return loadPBM(r, MaxPatternCells)
+}
+
+// loadPBM is LoadPBM for images of at most maxCells pixels.
+func loadPBM(r io.Reader, maxCells int) (*Field, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, 2)
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, err
+	}
+	if magic[0] != 'P' || magic[1] != '1' && magic[1] != '2' && magic[1] != '4' && magic[1] != '5' {
+		return nil, fmt.Errorf("life: unsupported netpbm format %q", magic)
+	}
+	var gen int64
+	width, err := pbmInt(br, &gen)
+	if err != nil {
+		return nil, err
+	}
+	h, err := pbmInt(br, &gen)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkArea(width, h, maxCells); err != nil {
+		return nil, err
+	}
+	maxval := 1
+	if magic[1] == '2' || magic[1] == '5' {
+		if maxval, err = pbmInt(br, &gen); err != nil {
+			return nil, err
+		}
+		if maxval < 1 || maxval > 0xffff {
+			return nil, fmt.Errorf("life: invalid netpbm maximum value %d", maxval)
+		}
+	}
+	if magic[1] == '4' || magic[1] == '5' {
+		// A single whitespace character precedes the raster.
+		if _, err := br.ReadByte(); err != nil {
+			return nil, err
+		}
+	}
+	f := NewField(width, h)
+	f.gen = gen
+	switch magic[1] {
+	case '1':
+		for y := 0; y < h; y++ {
+			for x := 0; x < width; x++ {
+				c, err := pbmSkip(br, nil)
+				if err != nil {
+					return nil, err
+				}
+				if c != '0' && c != '1' {
+					return nil, fmt.Errorf("life: unexpected character %q in PBM raster", c)
+				}
+				f.set(x, y, c == '1')
+			}
+		}
+	case '2':
+		for y := 0; y < h; y++ {
+			for x := 0; x < width; x++ {
+				v, err := pbmInt(br, nil)
+				if err != nil {
+					return nil, err
+				}
+				f.set(x, y, v*2 < maxval)
+			}
+		}
+	case '4':
+		row := make([]byte, (width+7)/8)
+		for y := 0; y < h; y++ {
+			if _, err := io.ReadFull(br, row); err != nil {
+				return nil, err
+			}
+			for x := 0; x < width; x++ {
+				f.set(x, y, row[x/8]&(0x80>>(x%8)) != 0)
+			}
+		}
+	case '5':
+		n := 1
+		if maxval > 0xff {
+			n = 2
+		}
+		row := make([]byte, width*n)
+		for y := 0; y < h; y++ {
+			if _, err := io.ReadFull(br, row); err != nil {
+				return nil, err
+			}
+			for x := 0; x < width; x++ {
+				v := int(row[x*n])
+				if n == 2 {
+					v = v<<8 | int(row[x*n+1])
+				}
+				f.set(x, y, v*2 < maxval)
+			}
+		}
+	}
+	return f, nil
+}
+
+// pbmSkip skips whitespace and comments and returns the next byte. If gen is
+// not nil, a generation recorded in a comment is stored in it.
+func pbmSkip(br *bufio.Reader, gen *int64) (byte, error) {
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		switch c {
+		case ' ', '\t', '\n', '\r', '\v', '\f':
+		case '#':
+			line, err := br.ReadString('\n')
+			if err != nil {
+				return 0, err
+			}
+			if n, ok := parseGenerationComment(line); ok && gen != nil {
+				*gen = n
+			}
+		default:
+			return c, nil
+		}
+	}
+}
+
+// pbmInt reads a decimal number from a netpbm header or plain raster,
+// skipping comments as pbmSkip does.
+func pbmInt(br *bufio.Reader, gen *int64) (int, error) {
+	c, err := pbmSkip(br, gen)
+	if err != nil {
+		return 0, err
+	}
+	var digits []byte
+	for c >= '0' && c <= '9' {
+		digits = append(digits, c)
+		if c, err = br.ReadByte(); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		br.UnreadByte()
+	}
+	if len(digits) == 0 {
+		return 0, errors.New("life: malformed netpbm file")
+	}
+	return strconv.Atoi(string(digits))
+}
+
+// WritePBM writes the field to w as a netpbm bitmap with live cells in
+// black, using the plain (P1) format if plain is true and the raw (P4)
+// format otherwise. A generation other than 0 is recorded in a comment.
+func (f *Field) WritePBM(w io.Writer, plain bool) error {
+	bw := bufio.NewWriter(w)
+	if plain {
+		fmt.Fprintf(bw, "P1\n%s%d %d\n", f.pbmComment(), f.width, f.h)
+		for y := 0; y < f.h; y++ {
+			for x := 0; x < f.width; x++ {
+				b := byte('0')
+				if f.get(x, y) {
+					b = '1'
+				}
+				bw.WriteByte(b)
+			}
+			bw.WriteByte('\n')
+		}
+		return bw.Flush()
+	}
+	fmt.Fprintf(bw, "P4\n%s%d %d\n", f.pbmComment(), f.width, f.h)
+	row := make([]byte, (f.width+7)/8)
+	for y := 0; y < f.h; y++ {
+		clear(row)
+		for x := 0; x < f.width; x++ {
+			if f.get(x, y) {
+				row[x/8] |= 0x80 >> (x % 8)
+			}
+		}
+		bw.Write(row)
+	}
+	return bw.Flush()
+}
+
+// WritePGM writes the field to w as a raw (P5) netpbm graymap with live
+// cells in black and dead cells in white, with a comment recording its
+// generation as in WritePBM.
+func (f *Field) WritePGM(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "P5\n%s%d %d\n255\n", f.pbmComment(), f.width, f.h)
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; x++ {
+			b := byte(0xff)
+			if f.get(x, y) {
+				b = 0
+			}
+			bw.WriteByte(b)
+		}
+	}
+	return bw.Flush()
+}
+
+// pbmComment returns the header comment line recording the generation of
+// the field, or "" if it is 0.
+func (f *Field) pbmComment() string {
+	if c := generationComment(f.gen); c != "" {
+		return "# " + c + "\n"
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package life
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestLoadPBM checks the netpbm readers on small images, valid and not.
+func TestLoadPBM(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		w, h int
+		want []string // nil for an invalid image
+	}{
+		{"P1\n# glider\n3 3\n0 1 0\n0 0 1\n1 1 1\n", 3, 3, []string{".O.", "..O", "OOO"}},
+		{"P1 4 1 0110", 4, 1, []string{".OO."}},
+		{"P4\n3 2\n\x40\xa0", 3, 2, []string{".O.", "O.O"}},
+		{"P2\n2 2\n255\n0 255\n128 127\n", 2, 2, []string{"O.", ".O"}},
+		{"P5\n2 1\n255\n\x00\xff", 2, 1, []string{"O."}},
+		{"P3\n1 1\n1\n0 0 0\n", 0, 0, nil},
+		{"P1\n2 2\n0 1 2 0\n", 0, 0, nil},
+		{"P1\n2 2\n0 1\n", 0, 0, nil},
+		{"P2\n1 1\n0\n0\n", 0, 0, nil},
+		{"P1\n1000000000 1000000000\n", 0, 0, nil},
+		{"P4\n99999999999999999999 1\n", 0, 0, nil},
+	} {
+		f, err := LoadPBM(strings.NewReader(tt.in))
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("LoadPBM(%q): no error", tt.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("LoadPBM(%q): %v", tt.in, err)
+			continue
+		}
+		if want := fieldOf(t, Torus, tt.want...); f.Width() != tt.w || f.Height() != tt.h || !f.Equal(want) {
+			t.Errorf("LoadPBM(%q) = %d×%d %v, want %d×%d %v", tt.in, f.Width(), f.Height(), liveCells(f), tt.w, tt.h, liveCells(want))
+		}
+	}
+}
+
+// TestWritePBM checks that the images written by WritePBM and WritePGM
+// read back unchanged.
+func TestWritePBM(t *testing.T) {
+	f := NewLife(41, 13, WithRandom(0.4, nil)).Field()
+	for _, tt := range []struct {
+		name  string
+		write func(*bytes.Buffer) error
+	}{
+		{"plain", func(buf *bytes.Buffer) error { return f.WritePBM(buf, true) }},
+		{"raw", func(buf *bytes.Buffer) error { return f.WritePBM(buf, false) }},
+		{"pgm", func(buf *bytes.Buffer) error { return f.WritePGM(buf) }},
+	} {
+		var buf bytes.Buffer
+		if err := tt.write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		g, err := LoadPBM(&buf)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !slices.Equal(liveCells(g), liveCells(f)) || g.Width() != f.Width() || g.Height() != f.Height() {
+			t.Errorf("%s: image changed by a round trip", tt.name)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package life
+
+import "fmt"
+
+// A PlaceMode is the way Place combines the cells of a pattern with those of
+// a field.
+type PlaceMode uint8
+
+const (
+	PlaceOverwrite PlaceMode = iota // cells take the state of the pattern
+	PlaceOr                         // live cells of the pattern come alive
+	PlaceXor                        // live cells of the pattern toggle
+)
+
+// String returns the name of the mode in the notation of ParsePlaceMode.
+func (m PlaceMode) String() string {
+	switch m {
+	case PlaceOverwrite:
+		return "overwrite"
+	case PlaceOr:
+		return "or"
+	case PlaceXor:
+		return "xor"
+	}
+	return "unknown"
+}
+
+// ParsePlaceMode parses a mode written as "overwrite", "or" or "xor".
+func ParsePlaceMode(s string) (PlaceMode, error) {
+	for _, m := range [...]PlaceMode{PlaceOverwrite, PlaceOr, PlaceXor} {
+		if s == m.String() {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("life: unknown place mode %q", s)
+}
+
+// Place copies the cells of the pattern p onto f with the top-left corner of
+// p at x, y, combining them with those of f as mode says and wrapping around
+// the edges of f as given by its topology. Cells beyond dead edges are
+// dropped.
+func (f *Field) Place(p *Field, x, y int, mode PlaceMode) {
+	for j := 0; j < p.h; j++ {
+		for i := 0; i < p.width; i++ {
+			b := p.get(i, j)
+			if !b && mode != PlaceOverwrite {
+				continue
+			}
+			u, v, ok := f.Wrap(x+i, y+j)
+			if !ok {
+				continue
+			}
+			if mode == PlaceXor {
+				b = !f.get(u, v)
+			}
+			f.set(u, v, b)
+		}
+	}
+	f.edits++
+}
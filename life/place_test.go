@@ -0,0 +1,38 @@
+package life
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestPlace checks each mode of Place, and that patterns wrap around the
+// torus but are cut off at dead edges.
+func TestPlace(t *testing.T) {
+	p := fieldOf(t, Torus, "OO", ".O")
+	for _, tt := range []struct {
+		top  Topology
+		x, y int
+		mode PlaceMode
+		want []string
+	}{
+		{Torus, 1, 0, PlaceOverwrite, []string{"OOO.", "O.O.", "O..."}},
+		{Torus, 1, 0, PlaceOr, []string{"OOO.", "OOO.", "O..."}},
+		{Torus, 0, 0, PlaceXor, []string{".O..", "O...", "O..."}},
+		{Torus, 3, 1, PlaceXor, []string{"O...", ".O.O", "...."}},
+		{Plane, 3, 1, PlaceXor, []string{"O...", "OO.O", "O..."}},
+	} {
+		f := fieldOf(t, tt.top, "O...", "OO..", "O...")
+		f.Place(p, tt.x, tt.y, tt.mode)
+		if got, want := liveCells(f), liveCells(fieldOf(t, tt.top, tt.want...)); !slices.Equal(got, want) {
+			t.Errorf("%v at %d, %d on %v: cells %v, want %v", tt.mode, tt.x, tt.y, tt.top, got, want)
+		}
+	}
+	for _, m := range []PlaceMode{PlaceOverwrite, PlaceOr, PlaceXor} {
+		if got, err := ParsePlaceMode(m.String()); err != nil || got != m {
+			t.Errorf("ParsePlaceMode(%q) = %v, %v", m, got, err)
+		}
+	}
+	if _, err := ParsePlaceMode("and"); err == nil {
+		t.Error(`ParsePlaceMode("and"): no error`)
+	}
+}
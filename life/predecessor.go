@@ -0,0 +1,155 @@
+package life
+
+// FindPredecessor searches for a predecessor of the field: a field of the
+// same size, rule and topology that the rule turns into it in one step,
+// the cells beyond its edges being mapped as Alive does. It reports false
+// if there is none, making the field a Garden of Eden within its bounds,
+// or if the field has more than maxArea cells, in which case no search is
+// made, as the search can take time exponential in the area. A field
+// holding a pattern with a border of dead cells around it and dead edges
+// looks for parents of the pattern that fit within the field.
+func (f *Field) FindPredecessor(maxArea int) (*Field, bool) {
+	n := f.width * f.h
+	if n > maxArea {
+		return nil, false
+	}
+	s := newPredecessorSearch(f)
+	if !s.search(0) {
+		return nil, false
+	}
+	p := NewFieldWithTopology(f.width, f.h, f.top)
+	p.rule, p.gen = f.rule, max(f.gen-1, 0)
+	for i, c := range s.cells {
+		if c == 1 {
+			p.set(i%f.width, i/f.width, true)
+		}
+	}
+	return p, true
+}
+
+// A predecessorSearch assigns the cells of a predecessor of a field one by
+// one in order of rows, backtracking as soon as some cell of the field can
+// no longer come out right whatever the cells around it left to assign.
+// Cells are numbered y*width+x, in the predecessor and the field alike.
+type predecessorSearch struct {
+	cells   []int8              // state of each cell of the predecessor, -1 while unassigned
+	next    [2][9]bool          // the rule, by state and number of live neighbors
+	want    []bool              // state of each cell of the field
+	alive   []int               // live neighbors assigned to each cell of the field
+	unknown []int               // neighbors left to assign to each cell of the field
+	affects [][]predecessorLink // cells of the field each cell of the predecessor bears on
+}
+
+// A predecessorLink records that a cell of a predecessor is the cell t of
+// the field itself or one of its neighbors, as many times as n says.
+type predecessorLink struct {
+	t, n int
+}
+
+// newPredecessorSearch returns a search for a predecessor of f with every
+// cell unassigned.
+func newPredecessorSearch(f *Field) *predecessorSearch {
+	n := f.width * f.h
+	s := &predecessorSearch{
+		cells:   make([]int8, n),
+		want:    make([]bool, n),
+		alive:   make([]int, n),
+		unknown: make([]int, n),
+		affects: make([][]predecessorLink, n),
+	}
+	for self := range 2 {
+		for k := range 9 {
+			s.next[self][k] = f.rule.Next(self == 1, k)
+		}
+	}
+	for t := range s.cells {
+		s.cells[t] = -1
+		s.want[t] = f.get(t%f.width, t/f.width)
+		s.affects[t] = []predecessorLink{{t, 0}}
+	}
+	for t := range s.cells {
+		x, y := t%f.width, t/f.width
+		for j := -1; j <= 1; j++ {
+			for i := -1; i <= 1; i++ {
+				if i == 0 && j == 0 {
+					continue
+				}
+				u, v, ok := f.Wrap(x+i, y+j)
+				if !ok {
+					continue
+				}
+				s.unknown[t]++
+				s.link(u+v*f.width, t)
+			}
+		}
+	}
+	return s
+}
+
+// link records that cell p of the predecessor is a neighbor of cell t of
+// the field, once more.
+func (s *predecessorSearch) link(p, t int) {
+	for i, l := range s.affects[p] {
+		if l.t == t {
+			s.affects[p][i].n++
+			return
+		}
+	}
+	s.affects[p] = append(s.affects[p], predecessorLink{t, 1})
+}
+
+// search assigns cells p onward, reporting whether it found an assignment
+// under which every cell of the field comes out right.
+func (s *predecessorSearch) search(p int) bool {
+	if p == len(s.cells) {
+		return true
+	}
+	for _, c := range [2]int8{0, 1} {
+		if s.assign(p, c) && s.search(p+1) {
+			return true
+		}
+		s.unassign(p, c)
+	}
+	return false
+}
+
+// assign gives cell p of the predecessor the state c, reporting whether
+// every cell of the field it bears on can still come out right.
+func (s *predecessorSearch) assign(p int, c int8) bool {
+	s.cells[p] = c
+	for _, l := range s.affects[p] {
+		s.unknown[l.t] -= l.n
+		s.alive[l.t] += l.n * int(c)
+	}
+	for _, l := range s.affects[p] {
+		if !s.possible(l.t) {
+			return false
+		}
+	}
+	return true
+}
+
+// unassign undoes assign(p, c).
+func (s *predecessorSearch) unassign(p int, c int8) {
+	s.cells[p] = -1
+	for _, l := range s.affects[p] {
+		s.unknown[l.t] += l.n
+		s.alive[l.t] -= l.n * int(c)
+	}
+}
+
+// possible reports whether some assignment of the cells left could make
+// cell t of the field come out right.
+func (s *predecessorSearch) possible(t int) bool {
+	for self := range 2 {
+		if s.cells[t] >= 0 && int(s.cells[t]) != self {
+			continue
+		}
+		for k := s.alive[t]; k <= s.alive[t]+s.unknown[t]; k++ {
+			if s.next[self][k] == s.want[t] {
+				return true
+			}
+		}
+	}
+	return false
+}
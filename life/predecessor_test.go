@@ -0,0 +1,66 @@
+package life
+
+import "testing"
+
+// TestFindPredecessor checks, for every field of a few small sizes, that a
+// predecessor is found exactly when one exists, as found by stepping every
+// field of the size, and that it steps to the field.
+func TestFindPredecessor(t *testing.T) {
+	for _, tt := range []struct {
+		w, h int
+		top  Topology
+	}{
+		{3, 3, Plane},
+		{3, 3, Torus},
+		{4, 2, KleinBottle},
+	} {
+		n := tt.w * tt.h
+		// fieldOfBits returns the field whose cell i is alive if bit i of v
+		// is set.
+		fieldOfBits := func(v int) *Field {
+			f := NewFieldWithTopology(tt.w, tt.h, tt.top)
+			for i := 0; i < n; i++ {
+				f.Set(i%tt.w, i/tt.w, v>>i&1 != 0)
+			}
+			return f
+		}
+		bitsOf := func(f *Field) int {
+			v := 0
+			for i := 0; i < n; i++ {
+				if f.Alive(i%tt.w, i/tt.w) {
+					v |= 1 << i
+				}
+			}
+			return v
+		}
+		step := func(f *Field) *Field {
+			grid := NewLifeFromField(f.Clone())
+			grid.Step()
+			return grid.Field()
+		}
+		reached := make([]bool, 1<<n)
+		for v := range reached {
+			reached[bitsOf(step(fieldOfBits(v)))] = true
+		}
+		eden := 0
+		for v, want := range reached {
+			f := fieldOfBits(v)
+			p, ok := f.FindPredecessor(n)
+			if ok != want {
+				t.Fatalf("%d×%d on %v: field %#x: FindPredecessor reported %v, want %v", tt.w, tt.h, tt.top, v, ok, want)
+			}
+			if ok && bitsOf(step(p)) != v {
+				t.Fatalf("%d×%d on %v: field %#x: predecessor %#x steps to %#x", tt.w, tt.h, tt.top, v, bitsOf(p), bitsOf(step(p)))
+			}
+			if !ok {
+				eden++
+			}
+		}
+		if eden == 0 {
+			t.Errorf("%d×%d on %v: every field has a predecessor", tt.w, tt.h, tt.top)
+		}
+	}
+	if _, ok := NewField(4, 4).FindPredecessor(15); ok {
+		t.Error("FindPredecessor searched a field larger than maxArea")
+	}
+}
@@ -0,0 +1,304 @@
+package life
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math/bits"
+)
+
+// quadLeafSize is the side of the square blocks of cells held by the
+// leaves of a QuadField.
+const quadLeafSize = 8
+
+// A quadNode is a node of the tree of a QuadField. Nodes of level 0 are
+// leaves holding an 8×8 block of cells, bit y*8+x being the cell at x, y of
+// the block; the others have four quadrants, in the order northwest,
+// northeast, southwest and southeast, nil where they are empty.
+type quadNode struct {
+	quad  [4]*quadNode
+	cells uint64
+}
+
+// QuadField is an unbounded plane of cells evolving under a B/S rule that
+// stores its cells in a quadtree whose nodes are only allocated where
+// there are live cells, so that patterns spread over huge areas take
+// memory in proportion to their populated regions. Unlike HashLife, it
+// shares no nodes and memoizes nothing; it is simply a sparse field.
+type QuadField struct {
+	root  *quadNode // covers the cells from -half to half-1 on each axis
+	level uint      // level of the root, whose side is quadLeafSize<<level
+	rule  Rule
+	table *ruleTable
+	gen   int64 // number of steps taken
+}
+
+// NewQuadField returns an empty quadtree field that follows the Conway
+// rule.
+func NewQuadField() *QuadField {
+	return &QuadField{root: &quadNode{}, level: 1, rule: Conway, table: newRuleTable(Conway)}
+}
+
+// NewQuadFieldFromField returns a quadtree field whose cells at
+// 0 ≤ x < width and 0 ≤ y < height are those of f and which follows the rule
+// of f.
+func NewQuadFieldFromField(f *Field) (*QuadField, error) {
+	q := NewQuadField()
+	if err := q.SetRule(f.rule); err != nil {
+		return nil, err
+	}
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; x++ {
+			if f.get(x, y) {
+				q.Set(x, y, true)
+			}
+		}
+	}
+	return q, nil
+}
+
+// Rule returns the rule the field follows.
+func (q *QuadField) Rule() Rule { return q.rule }
+
+// SetRule changes the rule the field follows. Rules with B0 are rejected,
+// as they would bring the whole empty plane to life.
+func (q *QuadField) SetRule(r Rule) error {
+	if r.B0() {
+		return fmt.Errorf("life: rule %v with B0 is not supported on a quadtree field", r)
+	}
+	q.rule, q.table = r, newRuleTable(r)
+	return nil
+}
+
+// Generation returns the number of steps taken.
+func (q *QuadField) Generation() int64 { return q.gen }
+
+// half returns half the side of the root.
+func (q *QuadField) half() int {
+	return quadLeafSize << q.level / 2
+}
+
+// grow doubles the side of the root, keeping it centered on the origin.
+func (q *QuadField) grow() {
+	var root quadNode
+	for i, c := range q.root.quad {
+		if c != nil {
+			// The old quadrant i becomes the quadrant of the new one i
+			// opposite to it, nearest the center.
+			n := &quadNode{}
+			n.quad[3-i] = c
+			root.quad[i] = n
+		}
+	}
+	q.root = &root
+	q.level++
+}
+
+// leaf returns the leaf holding the block with the given coordinates, in
+// units of blocks from the top-left corner of the root, allocating it if
+// create is set and returning nil otherwise if the block is empty.
+func (q *QuadField) leaf(bx, by int, create bool) *quadNode {
+	n := q.root
+	for l := q.level; l > 0; l-- {
+		s := 1 << (l - 1) // side of quadrants in blocks
+		i := 0
+		if bx >= s {
+			i, bx = i+1, bx-s
+		}
+		if by >= s {
+			i, by = i+2, by-s
+		}
+		c := n.quad[i]
+		if c == nil {
+			if !create {
+				return nil
+			}
+			c = &quadNode{}
+			n.quad[i] = c
+		}
+		n = c
+	}
+	return n
+}
+
+// locate returns the coordinates in blocks of the block holding the cell
+// at x, y and the index of the cell in it, or false if it is outside the
+// root.
+func (q *QuadField) locate(x, y int) (bx, by int, bit uint, ok bool) {
+	h := q.half()
+	if x < -h || x >= h || y < -h || y >= h {
+		return 0, 0, 0, false
+	}
+	x, y = x+h, y+h
+	return x / quadLeafSize, y / quadLeafSize, uint(y%quadLeafSize*quadLeafSize + x%quadLeafSize), true
+}
+
+// Set sets the state of the specified cell to the given value.
+func (q *QuadField) Set(x, y int, b bool) {
+	for b {
+		if _, _, _, ok := q.locate(x, y); ok {
+			break
+		}
+		q.grow()
+	}
+	bx, by, bit, ok := q.locate(x, y)
+	if !ok {
+		return
+	}
+	if n := q.leaf(bx, by, b); n != nil {
+		if b {
+			n.cells |= 1 << bit
+		} else {
+			n.cells &^= 1 << bit
+		}
+	}
+}
+
+// Alive reports whether the specified cell is alive.
+func (q *QuadField) Alive(x, y int) bool {
+	bx, by, bit, ok := q.locate(x, y)
+	if !ok {
+		return false
+	}
+	n := q.leaf(bx, by, false)
+	return n != nil && n.cells&(1<<bit) != 0
+}
+
+// leaves calls fn for every non-empty leaf with its coordinates in blocks.
+func (q *QuadField) leaves(fn func(bx, by int, n *quadNode)) {
+	var walk func(n *quadNode, l uint, bx, by int)
+	walk = func(n *quadNode, l uint, bx, by int) {
+		if l == 0 {
+			if n.cells != 0 {
+				fn(bx, by, n)
+			}
+			return
+		}
+		s := 1 << (l - 1)
+		for i, c := range n.quad {
+			if c != nil {
+				walk(c, l-1, bx+i%2*s, by+i/2*s)
+			}
+		}
+	}
+	walk(q.root, q.level, 0, 0)
+}
+
+// Population returns the number of live cells.
+func (q *QuadField) Population() int {
+	n := 0
+	q.leaves(func(_, _ int, l *quadNode) { n += bits.OnesCount64(l.cells) })
+	return n
+}
+
+// Bounds returns the smallest rectangle containing every live cell, or an
+// empty rectangle if there are none.
+func (q *QuadField) Bounds() image.Rectangle {
+	var r image.Rectangle
+	h := q.half()
+	q.leaves(func(bx, by int, l *quadNode) {
+		for c := l.cells; c != 0; c &= c - 1 {
+			i := bits.TrailingZeros64(c)
+			x, y := bx*quadLeafSize+i%quadLeafSize-h, by*quadLeafSize+i/quadLeafSize-h
+			r = r.Union(image.Rect(x, y, x+1, y+1))
+		}
+	})
+	return r
+}
+
+// Field returns a copy of the cells of the field within r as a field with
+// dead edges that follows the rule of the quadtree field, the cell at r.Min
+// being at 0, 0.
+func (q *QuadField) Field(r image.Rectangle) *Field {
+	f := NewFieldWithTopology(r.Dx(), r.Dy(), Plane)
+	f.rule = q.rule
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; x++ {
+			f.set(x, y, q.Alive(r.Min.X+x, r.Min.Y+y))
+		}
+	}
+	return f
+}
+
+// Step advances the field by one generation, computing the blocks that hold
+// live cells and those around them into a new tree.
+func (q *QuadField) Step() {
+	// Grow the root until the blocks along its border are empty, so that
+	// every block that can come alive lies within it.
+	for q.touchesBorder() {
+		q.grow()
+	}
+	blocks := make(map[image.Point]bool)
+	q.leaves(func(bx, by int, _ *quadNode) {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				blocks[image.Pt(bx+dx, by+dy)] = true
+			}
+		}
+	})
+	next := &QuadField{root: &quadNode{}, level: q.level, rule: q.rule, table: q.table, gen: q.gen + 1}
+	for b := range blocks {
+		var around [3][3]uint64
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if n := q.leaf(b.X+dx, b.Y+dy, false); n != nil {
+					around[dy+1][dx+1] = n.cells
+				}
+			}
+		}
+		// alive reports whether the cell at x, y of the block, which may
+		// lie in a block around it, is alive.
+		alive := func(x, y int) int {
+			n := around[(y+quadLeafSize)/quadLeafSize][(x+quadLeafSize)/quadLeafSize]
+			return int(n >> uint((y+quadLeafSize)%quadLeafSize*quadLeafSize+(x+quadLeafSize)%quadLeafSize) & 1)
+		}
+		var cells uint64
+		for y := 0; y < quadLeafSize; y++ {
+			cfg := 0
+			for _, x := range [2]int{-1, 0} {
+				cfg = cfg<<3 | alive(x, y-1)<<2 | alive(x, y)<<1 | alive(x, y+1)
+			}
+			for x := 0; x < quadLeafSize; x++ {
+				cfg = (cfg<<3 | alive(x+1, y-1)<<2 | alive(x+1, y)<<1 | alive(x+1, y+1)) & 511
+				if q.table[cfg] {
+					cells |= 1 << uint(y*quadLeafSize+x)
+				}
+			}
+		}
+		if cells != 0 {
+			next.leaf(b.X, b.Y, true).cells = cells
+		}
+	}
+	*q = *next
+}
+
+// touchesBorder reports whether any live cell lies in a block along the
+// border of the root.
+func (q *QuadField) touchesBorder() bool {
+	last := 1<<q.level - 1
+	border := false
+	q.leaves(func(bx, by int, _ *quadNode) {
+		if bx == 0 || by == 0 || bx == last || by == last {
+			border = true
+		}
+	})
+	return border
+}
+
+// String returns the smallest rectangle of the field containing every live
+// cell as a string, in the format of Life.String.
+func (q *QuadField) String() string {
+	r := q.Bounds()
+	var buf bytes.Buffer
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			b := byte(' ')
+			if q.Alive(x, y) {
+				b = '*'
+			}
+			buf.WriteByte(b)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
@@ -0,0 +1,54 @@
+package life
+
+import "fmt"
+
+// An Anchor is the point of a board that stays in place when it is resized.
+type Anchor uint8
+
+const (
+	TopLeft Anchor = iota
+	Top
+	TopRight
+	Left
+	Center
+	Right
+	BottomLeft
+	Bottom
+	BottomRight
+)
+
+// offset returns the offset at which the cells of a board of the size
+// width, h are placed in one of the size newW, newH when anchored at a.
+func (a Anchor) offset(width, h, newW, newH int) (dx, dy int) {
+	return (newW - width) * int(a%3) / 2, (newH - h) * int(a/3) / 2
+}
+
+// Resize grows or crops the board of the game to newW by newH cells,
+// keeping the cells at the anchor in place. Cells added are dead, and those
+// falling outside the new board are lost. It returns an error, leaving the
+// board as it was, if either size is negative.
+func (grid *Life) Resize(newW, newH int, anchor Anchor) error {
+	if newW < 0 || newH < 0 {
+		return fmt.Errorf("life: invalid board size %d×%d", newW, newH)
+	}
+	if grid.inverted {
+		grid.a.invert()
+		grid.inverted = false
+	}
+	dx, dy := anchor.offset(grid.width, grid.h, newW, newH)
+	f := NewFieldWithTopology(newW, newH, grid.a.top)
+	for x, y := range grid.a.LiveCells() {
+		if x+dx >= 0 && x+dx < newW && y+dy >= 0 && y+dy < newH {
+			f.set(x+dx, y+dy, true)
+		}
+	}
+	for _, g := range [2]*Field{grid.a, grid.b} {
+		g.resize(newW, newH)
+		g.edits++
+	}
+	copy(grid.a.bits, f.bits)
+	grid.width, grid.h = newW, newH
+	grid.history.clear()
+	grid.act.valid = false
+	return nil
+}
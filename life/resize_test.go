@@ -0,0 +1,28 @@
+package life
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestResize checks that resizing keeps the cells at the anchor in place,
+// and that negative sizes are rejected without touching the board.
+func TestResize(t *testing.T) {
+	grid := NewLifeFromField(fieldOf(t, Torus, ".O..", "..O.", "OOO."))
+	if err := grid.Resize(-1, 5, TopLeft); err == nil {
+		t.Error("Resize(-1, 5) gave no error")
+	}
+	if grid.Field().Width() != 4 || grid.Field().Height() != 3 || !slices.Equal(liveCells(grid.Field()), glider) {
+		t.Fatal("Resize(-1, 5) changed the board")
+	}
+	if err := grid.Resize(6, 5, BottomRight); err != nil {
+		t.Fatal(err)
+	}
+	want := pts(3, 2, 4, 3, 2, 4, 3, 4, 4, 4)
+	if got := liveCells(grid.Field()); grid.Field().Width() != 6 || grid.Field().Height() != 5 || !slices.Equal(got, want) {
+		t.Errorf("%d×%d board with cells %v, want 6×5 with %v", grid.Field().Width(), grid.Field().Height(), got, want)
+	}
+	if err := grid.Resize(0, 0, Center); err != nil || grid.Population() != 0 {
+		t.Errorf("Resize(0, 0): population %d, error %v", grid.Population(), err)
+	}
+}
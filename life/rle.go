@@ -0,0 +1,316 @@
+package life
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// rleRun is a run of n cells in a non-zero state read from an RLE pattern
+// body, starting at x, y. Runs are kept whole so that the memory taken by a
+// pattern is in proportion to the size of its body.
+type rleRun struct {
+	x, y, n int
+	state   State
+}
+
+// LoadRLE reads a pattern in the Run Length Encoded format used by Golly and
+// LifeWiki and returns it as a field. The field is sized according to the
+// x and y values of the header line, grown if necessary to hold every cell of
+// the pattern body, and follows the rule given in the header, or the Conway
+// rule if there is none; a masked rule, in the notation of ParseMaskRule, is
+// recorded with the field for NewLifeFromField. A bounded grid following the
+// rule, as in "B3/S23:K40*,20", sets the topology of the field, which is then grown to
+// the size of the grid with the pattern centered. The generation is read
+// from a "#CXRLE Gen=n" line as written by Golly; other comment lines
+// starting with '#' are ignored. Cells of multi-state patterns are alive if their
+// state is not zero. Patterns larger than MaxPatternCells are rejected.
+func LoadRLE(r io.Reader) (*Field, error) {
+	return loadRLE(r, MaxPatternCells)
+}
+
+// loadRLE is LoadRLE for patterns of at most maxCells cells.
+func loadRLE(r io.Reader, maxCells int) (*Field, error) {
+	width, h, name, gen, runs, err := readRLE(r, maxCells)
+	if err != nil {
+		return nil, err
+	}
+	name, grid, bounded := strings.Cut(name, ":")
+	rule, mask := Conway, (*MaskRule)(nil)
+	if name != "" {
+		if rule, mask, err = parseFileRule(name); err != nil {
+			return nil, err
+		}
+	}
+	top, dx, dy := Torus, 0, 0
+	if bounded {
+		gw, gh, t, err := ParseBoundedGrid(":" + grid)
+		if err != nil {
+			return nil, err
+		}
+		top = t
+		if gw >= width && gh >= h {
+			dx, dy = (gw-width)/2, (gh-h)/2
+			width, h = gw, gh
+		}
+	}
+	if err := checkArea(width, h, maxCells); err != nil {
+		return nil, err
+	}
+	f := NewFieldWithTopology(width, h, top)
+	f.rule, f.mask, f.gen = rule, mask, gen
+	for _, c := range runs {
+		for x := c.x; x < c.x+c.n; x++ {
+			f.Set(x+dx, c.y+dy, true)
+		}
+	}
+	return f, nil
+}
+
+// LoadRLEGrid reads a multi-state pattern in the Run Length Encoded format
+// and returns it as a grid along with the rule named in its header, or ""
+// if there is none. In the pattern body, '.' and 'b' are state 0, 'o' and
+// 'A' to 'X' are states 1 to 24, and higher states are written with a
+// prefix letter from 'p' to 'y', "pA" being state 25. Patterns larger than
+// MaxPatternCells are rejected.
+func LoadRLEGrid(r io.Reader) (*Grid, string, error) {
+	width, h, rule, _, runs, err := readRLE(r, MaxPatternCells)
+	if err != nil {
+		return nil, "", err
+	}
+	g := NewGrid(width, h)
+	for _, c := range runs {
+		for x := c.x; x < c.x+c.n; x++ {
+			g.Set(x, c.y, c.state)
+		}
+	}
+	return g, rule, nil
+}
+
+// readRLE reads an RLE pattern and returns its dimensions, the rule named
+// in its header, its generation and its runs of cells in non-zero states,
+// failing if it exceeds maxCells cells.
+func readRLE(r io.Reader, maxCells int) (width, h int, rule string, gen int64, runs []rleRun, err error) {
+	sc := bufio.NewScanner(r)
+	var body strings.Builder
+	header := false
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if xrle, ok := strings.CutPrefix(line, "#CXRLE"); ok {
+			for _, kv := range strings.Fields(xrle) {
+				if v, ok := strings.CutPrefix(kv, "Gen="); ok {
+					if gen, err = strconv.ParseInt(v, 10, 64); err != nil {
+						return 0, 0, "", 0, nil, fmt.Errorf("life: invalid RLE generation %q", v)
+					}
+				}
+			}
+			continue
+		}
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if !header && line[0] == 'x' {
+			if width, h, rule, err = parseRLEHeader(line); err != nil {
+				return 0, 0, "", 0, nil, err
+			}
+			if err := checkArea(width, h, maxCells); err != nil {
+				return 0, 0, "", 0, nil, err
+			}
+			header = true
+			continue
+		}
+		header = true
+		body.WriteString(line)
+		if strings.ContainsRune(line, '!') {
+			break
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return 0, 0, "", 0, nil, err
+	}
+	runs, w, hh, err := decodeRLE(body.String(), maxCells)
+	if err != nil {
+		return 0, 0, "", 0, nil, err
+	}
+	width, h = max(width, w), max(h, hh)
+	if err := checkArea(width, h, maxCells); err != nil {
+		return 0, 0, "", 0, nil, err
+	}
+	return width, h, rule, gen, runs, nil
+}
+
+// parseRLEHeader parses a header line such as "x = 3, y = 3, rule = B3/S23"
+// and returns the pattern dimensions and rule.
+func parseRLEHeader(line string) (width, h int, rule string, err error) {
+	// Commas also appear in the bounded grid of a rule, as in
+	// "rule = B3/S23:T40,20", so parts without '=' continue the previous one.
+	var parts []string
+	for _, p := range strings.Split(line, ",") {
+		if n := len(parts); n > 0 && !strings.Contains(p, "=") {
+			parts[n-1] += "," + p
+			continue
+		}
+		parts = append(parts, p)
+	}
+	for _, kv := range parts {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return 0, 0, "", fmt.Errorf("life: malformed RLE header %q", line)
+		}
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		switch k {
+		case "x", "y":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return 0, 0, "", fmt.Errorf("life: invalid RLE dimension %s = %q", k, v)
+			}
+			if k == "x" {
+				width = n
+			} else {
+				h = n
+			}
+		case "rule":
+			rule = v
+		}
+	}
+	return width, h, rule, nil
+}
+
+// decodeRLE decodes an RLE pattern body and returns the runs of cells in
+// non-zero states it contains along with the extent of the pattern, failing
+// if a run count or the extent exceeds maxCells cells.
+func decodeRLE(body string, maxCells int) (runs []rleRun, width, h int, err error) {
+	x, y, n := 0, 0, 0
+	prefix := 0 // value of a pending 'p' to 'y' state prefix
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c >= '0' && c <= '9':
+			if n > (maxCells-int(c-'0'))/10 {
+				return nil, 0, 0, fmt.Errorf("life: RLE run count exceeds the limit of %d cells", maxCells)
+			}
+			n = n*10 + int(c-'0')
+			continue
+		case c == ' ' || c == '\t':
+			continue
+		case c == '!':
+			return runs, width, h, nil
+		case c >= 'p' && c <= 'y':
+			prefix = 24 * int(c-'p'+1)
+			continue
+		}
+		if n == 0 {
+			n = 1
+		}
+		var s State
+		switch {
+		case c == '$':
+			if y += n; y > maxCells {
+				return nil, 0, 0, fmt.Errorf("life: RLE pattern exceeds the limit of %d cells", maxCells)
+			}
+			x = 0
+		case c == 'b' || c == '.':
+			if x += n; x > maxCells {
+				return nil, 0, 0, fmt.Errorf("life: RLE pattern exceeds the limit of %d cells", maxCells)
+			}
+		case c == 'o':
+			s = 1
+		case c >= 'A' && c <= 'X':
+			if prefix+int(c-'A'+1) > 255 {
+				return nil, 0, 0, fmt.Errorf("life: state out of range in RLE body")
+			}
+			s = State(prefix + int(c-'A'+1))
+		default:
+			return nil, 0, 0, fmt.Errorf("life: unexpected character %q in RLE body", c)
+		}
+		if s != 0 {
+			if err := checkArea(max(width, x+n), max(h, y+1), maxCells); err != nil {
+				return nil, 0, 0, err
+			}
+			runs = append(runs, rleRun{x, y, n, s})
+			x += n
+			width = max(width, x)
+			h = max(h, y+1)
+		}
+		n, prefix = 0, 0
+	}
+	return runs, width, h, nil
+}
+
+// rleLineLen is the maximum length of a line written by WriteRLE.
+const rleLineLen = 70
+
+// WriteRLE writes the field to w in the Run Length Encoded format. The
+// pattern is cropped to the bounding box of its live cells, and the header
+// records the rule of the field, or its masked rule if it has one; see
+// Life.SetStepper. Fields with a topology other than a torus
+// are written whole, with their bounded grid after the rule if Golly has a
+// notation for it. A generation other than 0 is recorded in a "#CXRLE"
+// line, as Golly does.
+func (f *Field) WriteRLE(w io.Writer) error {
+	r := f.liveBounds()
+	var grid string
+	if f.top != Torus {
+		r = image.Rect(0, 0, f.width, f.h)
+		grid, _ = f.top.BoundedGrid(f.width, f.h)
+	}
+	bw := bufio.NewWriter(w)
+	if f.gen != 0 {
+		fmt.Fprintf(bw, "#CXRLE Gen=%d\n", f.gen)
+	}
+	fmt.Fprintf(bw, "x = %d, y = %d, rule = %s%s\n", r.Dx(), r.Dy(), f.ruleName(), grid)
+
+	line := 0
+	emit := func(n int, tag byte) {
+		tok := string(tag)
+		if n > 1 {
+			tok = strconv.Itoa(n) + tok
+		}
+		if line+len(tok) > rleLineLen {
+			bw.WriteByte('\n')
+			line = 0
+		}
+		bw.WriteString(tok)
+		line += len(tok)
+	}
+	// Row ends are deferred so that blank rows collapse into a single run
+	// and the final row needs no terminator.
+	rows := 0
+	run := func(n int, alive bool) {
+		if rows > 0 {
+			emit(rows, '$')
+			rows = 0
+		}
+		emit(n, rleTag(alive))
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		n, alive := 0, false
+		for x := r.Min.X; x < r.Max.X; x++ {
+			b := f.get(x, y)
+			if b != alive && n > 0 {
+				run(n, alive)
+				n = 0
+			}
+			alive = b
+			n++
+		}
+		// Trailing dead cells of a row are implied.
+		if alive {
+			run(n, alive)
+		}
+		rows++
+	}
+	bw.WriteString("!\n")
+	return bw.Flush()
+}
+
+// rleTag returns the RLE tag for a run of cells in the given state.
+func rleTag(alive bool) byte {
+	if alive {
+		return 'o'
+	}
+	return 'b'
+}
@@ -0,0 +1,137 @@
+package life
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule is an outer-totalistic rule for two-state cellular automata on the
+// Moore neighborhood: whether a cell is alive at the next time step depends
+// only on its current state and the number of its eight neighbors that are
+// alive. Rules are written in B/S notation, such as "B3/S23" for Conway's
+// Game of Life, listing the neighbor counts for which a dead cell is born
+// and a live cell survives.
+type Rule struct {
+	birth, survival uint16 // bit n is set if n live neighbors give birth or survival
+}
+
+// Conway is the rule of Conway's Game of Life, B3/S23.
+var Conway = Rule{birth: 1 << 3, survival: 1<<2 | 1<<3}
+
+// ParseRule parses a rule written in B/S notation, such as "B3/S23",
+// "B36/S23" or "B2/S". The letters are case-insensitive, the birth and
+// survival parts may appear in either order, the slash may be omitted, and
+// the older S/B notation without letters, such as "23/3", is also accepted.
+func ParseRule(s string) (Rule, error) {
+	u := strings.ToUpper(strings.TrimSpace(s))
+	p, q, ok := strings.Cut(u, "/")
+	if !ok {
+		// Allow the slash to be omitted, as in "B3S23".
+		i := strings.IndexByte(u, 'S')
+		if !strings.HasPrefix(u, "B") || i < 0 {
+			return Rule{}, fmt.Errorf("life: invalid rule %q", s)
+		}
+		p, q = u[:i], u[i:]
+	}
+	if !strings.HasPrefix(p, "B") && !strings.HasPrefix(p, "S") {
+		// S/B notation.
+		p, q = "S"+p, "B"+q
+	}
+	if p[0] == 'S' {
+		p, q = q, p
+	}
+	if !strings.HasPrefix(p, "B") || !strings.HasPrefix(q, "S") {
+		return Rule{}, fmt.Errorf("life: invalid rule %q", s)
+	}
+	var r Rule
+	var err error
+	if r.birth, err = parseCounts(p[1:]); err != nil {
+		return Rule{}, fmt.Errorf("life: invalid rule %q", s)
+	}
+	if r.survival, err = parseCounts(q[1:]); err != nil {
+		return Rule{}, fmt.Errorf("life: invalid rule %q", s)
+	}
+	return r, nil
+}
+
+// MustParseRule is like ParseRule but panics if the rule cannot be parsed.
+func MustParseRule(s string) Rule {
+	r, err := ParseRule(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// parseCounts parses a list of distinct neighbor counts from 0 to 8 into a
+// bit mask.
+func parseCounts(s string) (uint16, error) {
+	var m uint16
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '8' || m&(1<<(c-'0')) != 0 {
+			return 0, fmt.Errorf("invalid neighbor count %q", c)
+		}
+		m |= 1 << (c - '0')
+	}
+	return m, nil
+}
+
+// String returns the rule in B/S notation.
+func (r Rule) String() string {
+	var b strings.Builder
+	b.WriteByte('B')
+	writeCounts(&b, r.birth)
+	b.WriteString("/S")
+	writeCounts(&b, r.survival)
+	return b.String()
+}
+
+// writeCounts writes the neighbor counts set in the bit mask m.
+func writeCounts(b *strings.Builder, m uint16) {
+	for n := 0; n <= 8; n++ {
+		if m&(1<<n) != 0 {
+			b.WriteByte(byte('0' + n))
+		}
+	}
+}
+
+// Next returns the next state of a cell in the given state with the given
+// number of live neighbors.
+func (r Rule) Next(alive bool, neighbors int) bool {
+	if alive {
+		return r.survival&(1<<neighbors) != 0
+	}
+	return r.birth&(1<<neighbors) != 0
+}
+
+// allCounts has a bit set for every neighbor count from 0 to 8.
+const allCounts = 1<<9 - 1
+
+// B0 reports whether the rule gives birth to cells with no live neighbors,
+// so that an empty background comes alive. Life emulates such rules by
+// alternating phases; see Life.Inverted.
+func (r Rule) B0() bool {
+	return r.birth&1 != 0
+}
+
+// complement returns the rule that computes the complement of the next
+// state under r.
+func (r Rule) complement() Rule {
+	return Rule{birth: ^r.birth & allCounts, survival: ^r.survival & allCounts}
+}
+
+// dual returns the rule that computes the next state under r of a cell
+// whose state and neighbors are complemented.
+func (r Rule) dual() Rule {
+	var d Rule
+	for n := 0; n <= 8; n++ {
+		if r.survival&(1<<(8-n)) != 0 {
+			d.birth |= 1 << n
+		}
+		if r.birth&(1<<(8-n)) != 0 {
+			d.survival |= 1 << n
+		}
+	}
+	return d
+}
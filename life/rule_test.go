@@ -0,0 +1,51 @@
+package life
+
+import "testing"
+
+// TestParseRule checks the notations ParseRule accepts and rejects.
+func TestParseRule(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string // want is "" for an invalid rule
+	}{
+		{"B3/S23", "B3/S23"},
+		{"b36/s23", "B36/S23"},
+		{" B3S23 ", "B3/S23"},
+		{"S23/B3", "B3/S23"},
+		{"23/3", "B3/S23"},
+		{"B2/S", "B2/S"},
+		{"B/S012345678", "B/S012345678"},
+		{"B0123/S45", "B0123/S45"},
+		{"B63/S32", "B36/S23"},
+		{"", ""},
+		{"B3", ""},
+		{"B9/S23", ""},
+		{"B33/S23", ""},
+		{"X3/S23", ""},
+		{"B3/S2a", ""},
+	} {
+		r, err := ParseRule(tt.in)
+		switch {
+		case tt.want == "" && err == nil:
+			t.Errorf("ParseRule(%q) = %v, want an error", tt.in, r)
+		case tt.want != "" && err != nil:
+			t.Errorf("ParseRule(%q): %v", tt.in, err)
+		case tt.want != "" && r.String() != tt.want:
+			t.Errorf("ParseRule(%q) = %v, want %v", tt.in, r, tt.want)
+		}
+	}
+	if MustParseRule("B3/S23") != Conway {
+		t.Error("B3/S23 is not Conway")
+	}
+}
+
+// TestRuleNext checks the transitions of the Conway rule.
+func TestRuleNext(t *testing.T) {
+	for n := 0; n <= 8; n++ {
+		if got, want := Conway.Next(false, n), n == 3; got != want {
+			t.Errorf("Conway.Next(false, %d) = %v", n, got)
+		}
+		if got, want := Conway.Next(true, n), n == 2 || n == 3; got != want {
+			t.Errorf("Conway.Next(true, %d) = %v", n, got)
+		}
+	}
+}
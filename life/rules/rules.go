@@ -0,0 +1,244 @@
+// Package rules provides a registry of named Life-like rules.
+package rules
+
+import (
+	"strings"
+
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life"
+)
+
+// A Preset is a named rule.
+type Preset struct {
+	Name        string
+	Rule        life.Rule
+	Description string
+}
+
+// presets lists the registered rules in the order they are enumerated.
+var presets = []Preset{
+	{"life", life.Conway, "Conway's Game of Life"},
+	{"highlife", life.MustParseRule("B36/S23"), "Life with a small replicator"},
+	{"seeds", life.MustParseRule("B2/S"), "Every live cell dies; explosive growth"},
+	{"day-and-night", life.MustParseRule("B3678/S34678"), "Symmetric under inversion of live and dead cells"},
+	{"life-without-death", life.MustParseRule("B3/S012345678"), "Cells never die; grows ladders and ink blots"},
+	{"2x2", life.MustParseRule("B36/S125"), "Patterns made of 2x2 blocks behave like their own CA"},
+	{"34-life", life.MustParseRule("B34/S34"), "Many small oscillators and spaceships"},
+	{"maze", life.MustParseRule("B3/S12345"), "Grows into maze-like corridors"},
+	{"replicator", life.MustParseRule("B1357/S1357"), "Every pattern is eventually replaced by copies of itself"},
+}
+
+// Presets returns the registered rules.
+func Presets() []Preset {
+	return append([]Preset(nil), presets...)
+}
+
+// Names returns the names of the registered rules.
+func Names() []string {
+	names := make([]string, len(presets))
+	for i, p := range presets {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// Register adds a named rule to the registry, replacing any rule already
+// registered under the same name. It is not safe to call Register
+// concurrently with the other functions of the package.
+func Register(p Preset) {
+	for i := range presets {
+		if normalize(presets[i].Name) == normalize(p.Name) {
+			presets[i] = p
+			return
+		}
+	}
+	presets = append(presets, p)
+}
+
+// Lookup returns the rule registered under the given name. Names are
+// matched ignoring case and punctuation, so "Day & Night" finds
+// "day-and-night".
+func Lookup(name string) (life.Rule, bool) {
+	n := normalize(name)
+	for _, p := range presets {
+		if normalize(p.Name) == n {
+			return p.Rule, true
+		}
+	}
+	return life.Rule{}, false
+}
+
+// Parse returns the rule registered under the given name, or otherwise the
+// rule parsed from s in B/S notation.
+func Parse(s string) (life.Rule, error) {
+	if r, ok := Lookup(s); ok {
+		return r, nil
+	}
+	return life.ParseRule(s)
+}
+
+// normalize returns name in lower case with all characters other than
+// letters and digits removed and "&" spelled out as "and".
+func normalize(name string) string {
+	name = strings.ReplaceAll(strings.ToLower(name), "&", "and")
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, name)
+}
+
+// A GenerationsPreset is a named multi-state rule of the Generations
+// family.
+type GenerationsPreset struct {
+	Name        string
+	Rule        life.Generations
+	Description string
+}
+
+// generations lists the registered Generations rules.
+var generations = []GenerationsPreset{
+	{"brians-brain", mustParseGenerations("/2/3"), "Cells fire once and need a rest; full of gliders"},
+	{"star-wars", mustParseGenerations("345/2/4"), "Spaceships fly through a field of debris"},
+	{"frogs", mustParseGenerations("12/34/3"), "Wriggling frogs that leave a trail"},
+}
+
+// GenerationsPresets returns the registered Generations rules.
+func GenerationsPresets() []GenerationsPreset {
+	return append([]GenerationsPreset(nil), generations...)
+}
+
+// LookupGenerations returns the Generations rule registered under the
+// given name, matched as in Lookup.
+func LookupGenerations(name string) (life.Generations, bool) {
+	n := normalize(name)
+	for _, p := range generations {
+		if normalize(p.Name) == n {
+			return p.Rule, true
+		}
+	}
+	return life.Generations{}, false
+}
+
+// ParseGenerations returns the Generations rule registered under the
+// given name, or otherwise the rule parsed from s by
+// life.ParseGenerations.
+func ParseGenerations(s string) (life.Generations, error) {
+	if r, ok := LookupGenerations(s); ok {
+		return r, nil
+	}
+	return life.ParseGenerations(s)
+}
+
+func mustParseGenerations(s string) life.Generations {
+	r, err := life.ParseGenerations(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// An LtLPreset is a named Larger than Life rule.
+type LtLPreset struct {
+	Name        string
+	Rule        life.LtL
+	Description string
+}
+
+// ltl lists the registered Larger than Life rules.
+var ltl = []LtLPreset{
+	{"bosco", mustParseLtL("R5,C0,M1,S34..58,B34..45,NM"), "Bosco's rule; large spaceships called bugs"},
+	{"majority", mustParseLtL("R4,C0,M1,S41..81,B41..81,NM"), "Cells follow the majority of their neighborhood"},
+	{"waffle", mustParseLtL("R7,C0,M1,S100..200,B75..170,NM"), "Grows a waffle-like lattice"},
+}
+
+// LtLPresets returns the registered Larger than Life rules.
+func LtLPresets() []LtLPreset {
+	return append([]LtLPreset(nil), ltl...)
+}
+
+// LookupLtL returns the Larger than Life rule registered under the given
+// name, matched as in Lookup.
+func LookupLtL(name string) (life.LtL, bool) {
+	n := normalize(name)
+	for _, p := range ltl {
+		if normalize(p.Name) == n {
+			return p.Rule, true
+		}
+	}
+	return life.LtL{}, false
+}
+
+// ParseLtL returns the Larger than Life rule registered under the given
+// name, or otherwise the rule parsed from s by life.ParseLtL.
+func ParseLtL(s string) (life.LtL, error) {
+	if r, ok := LookupLtL(s); ok {
+		return r, nil
+	}
+	return life.ParseLtL(s)
+}
+
+func mustParseLtL(s string) life.LtL {
+	r, err := life.ParseLtL(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// A MargolusPreset is a named Margolus block rule.
+type MargolusPreset struct {
+	Name        string
+	Rule        string // in the notation of life.ParseMargolus
+	Description string
+}
+
+// margolus lists the registered Margolus rules. They are stored as
+// strings because each game needs its own *life.Margolus.
+var margolus = []MargolusPreset{
+	{"critters", "M15,14,13,3,11,5,6,1,7,9,10,2,12,4,8,0", "Reversible rule full of gliders"},
+	{"bbm", "M0,8,4,3,2,5,9,7,1,6,10,11,12,13,14,15", "Billiard-ball machine; balls bounce off each other"},
+	{"tron", "M15,1,2,3,4,5,6,7,8,9,10,11,12,13,14,0", "Reversible rule that grows rectangular fractals"},
+}
+
+// MargolusPresets returns the registered Margolus rules.
+func MargolusPresets() []MargolusPreset {
+	return append([]MargolusPreset(nil), margolus...)
+}
+
+// ParseMargolus returns a new instance of the Margolus rule registered
+// under the given name, or otherwise the rule parsed from s by
+// life.ParseMargolus.
+func ParseMargolus(s string) (*life.Margolus, error) {
+	n := normalize(s)
+	for _, p := range margolus {
+		if normalize(p.Name) == n {
+			return life.ParseMargolus(p.Rule)
+		}
+	}
+	return life.ParseMargolus(s)
+}
+
+// ParseStateRule returns the multi-state rule named by s: Wireworld if s
+// is "wireworld", the colored Conway rules if s is "immigration" or
+// "quadlife", and otherwise the Generations rule returned by
+// ParseGenerations. Brian's Brain, whether given by name or as B2/S/C3, is
+// returned as life.BriansBrain.
+func ParseStateRule(s string) (life.StateRule, error) {
+	switch normalize(s) {
+	case "wireworld":
+		return life.Wireworld{}, nil
+	case "immigration":
+		return life.Immigration{Rule: life.Conway}, nil
+	case "quadlife":
+		return life.QuadLife{Rule: life.Conway}, nil
+	}
+	r, err := ParseGenerations(s)
+	if err != nil {
+		return nil, err
+	}
+	if r.String() == (life.BriansBrain{}).String() {
+		return life.BriansBrain{}, nil
+	}
+	return r, nil
+}
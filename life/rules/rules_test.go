@@ -0,0 +1,73 @@
+package rules
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/collinp1221/CS371-Cycle-4-Game-of-Life/life"
+)
+
+// TestLookup checks that rules are found by name ignoring case and
+// punctuation, and that Parse falls back to B/S notation.
+func TestLookup(t *testing.T) {
+	for name, want := range map[string]string{
+		"life":          "B3/S23",
+		"HighLife":      "B36/S23",
+		"Day & Night":   "B3678/S34678",
+		"day_and_night": "B3678/S34678",
+		"34 Life":       "B34/S34",
+	} {
+		if r, ok := Lookup(name); !ok || r.String() != want {
+			t.Errorf("Lookup(%q) = %v, %v; want %s", name, r, ok, want)
+		}
+	}
+	if r, ok := Lookup("no-such-rule"); ok {
+		t.Errorf("Lookup of an unknown rule = %v", r)
+	}
+	if r, err := Parse("seeds"); err != nil || r.String() != "B2/S" {
+		t.Errorf("Parse(\"seeds\") = %v, %v", r, err)
+	}
+	if r, err := Parse("B1/S1"); err != nil || r.String() != "B1/S1" {
+		t.Errorf("Parse(\"B1/S1\") = %v, %v", r, err)
+	}
+	if _, err := Parse("no-such-rule"); err == nil {
+		t.Error("Parse of an unknown name: no error")
+	}
+	if names := Names(); len(names) != len(Presets()) || names[0] != "life" {
+		t.Errorf("Names() = %v", names)
+	}
+}
+
+// TestRegister checks that registering a rule adds it, or replaces the one
+// registered under the same name.
+func TestRegister(t *testing.T) {
+	saved := slices.Clone(presets)
+	defer func() { presets = saved }()
+	Register(Preset{"Move", life.MustParseRule("B368/S245"), "Many spaceships"})
+	Register(Preset{"HIGH life", life.MustParseRule("B36/S238"), "HighLife with S8"})
+	if r, ok := Lookup("move"); !ok || r.String() != "B368/S245" {
+		t.Errorf("Lookup(\"move\") after Register = %v, %v", r, ok)
+	}
+	if r, _ := Lookup("highlife"); r.String() != "B36/S238" {
+		t.Errorf("Lookup(\"highlife\") after replacing it = %v", r)
+	}
+	if n := len(Names()); n != len(saved)+1 {
+		t.Errorf("%d rules after registering one new one, want %d", n, len(saved)+1)
+	}
+}
+
+// TestLookupGenerations checks that every Generations rule registered is
+// found by its name, and unknown names are not.
+func TestLookupGenerations(t *testing.T) {
+	for _, p := range GenerationsPresets() {
+		if r, ok := LookupGenerations(p.Name); !ok || r.String() != p.Rule.String() {
+			t.Errorf("LookupGenerations(%q) = %v, %v; want %v", p.Name, r, ok, p.Rule)
+		}
+	}
+	if r, ok := LookupGenerations("Brian's Brain"); !ok || r.States() != 3 {
+		t.Errorf("LookupGenerations(\"Brian's Brain\") = %v, %v; want a rule of 3 states", r, ok)
+	}
+	if _, ok := LookupGenerations("life"); ok {
+		t.Error("LookupGenerations found the two-state rule life")
+	}
+}
@@ -0,0 +1,107 @@
+package life
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RunOptions configure a run of a game by Run.
+type RunOptions struct {
+	// Generations is the number of generations to step through, or 0 for
+	// no limit.
+	Generations int64
+
+	// StepsPerSecond is the rate at which generations are computed, or 0
+	// for as fast as possible.
+	StepsPerSecond float64
+
+	// FrameRate is the rate at which Frame is called, or 0 to call it
+	// after every step.
+	FrameRate float64
+
+	// Frame, if not nil, is called between two steps whenever a frame is
+	// due, and once more with final set when the run ends. Frames that
+	// fall due while a step is running are dropped, so that neither rate
+	// drifts when steps are slow. An error from Frame ends the run.
+	Frame func(final bool) error
+
+	// Stop, if not nil, is called after each step and ends the run if it
+	// returns true.
+	Stop func() bool
+
+	// StepTime, if not nil, is called after each step with the time it
+	// took.
+	StepTime func(d time.Duration)
+}
+
+// Run steps the game as opts say until it has computed opts.Generations
+// generations, opts.Stop returns true or ctx is done, in which case it
+// returns the error of ctx once the final frame is drawn.
+func (grid *Life) Run(ctx context.Context, opts RunOptions) error {
+	return RunSteps(ctx, grid.Step, opts)
+}
+
+// RunSteps is like Life.Run for any game, calling step to compute each
+// generation.
+func RunSteps(ctx context.Context, step func(), opts RunOptions) error {
+	if opts.StepsPerSecond < 0 {
+		return fmt.Errorf("life: invalid rate of %v steps per second", opts.StepsPerSecond)
+	}
+	if opts.FrameRate < 0 {
+		return fmt.Errorf("life: invalid frame rate %v", opts.FrameRate)
+	}
+	var steps, frames <-chan time.Time
+	if opts.StepsPerSecond > 0 {
+		t := time.NewTicker(time.Duration(float64(time.Second) / opts.StepsPerSecond))
+		defer t.Stop()
+		steps = t.C
+	}
+	if opts.FrameRate > 0 {
+		t := time.NewTicker(time.Duration(float64(time.Second) / opts.FrameRate))
+		defer t.Stop()
+		frames = t.C
+	}
+	var err error
+loop:
+	for n := int64(0); opts.Generations == 0 || n < opts.Generations; n++ {
+		if steps != nil {
+			select {
+			case <-steps:
+			case <-ctx.Done():
+				err = ctx.Err()
+				break loop
+			}
+		} else if err = ctx.Err(); err != nil {
+			break
+		}
+		start := time.Now()
+		step()
+		if opts.StepTime != nil {
+			opts.StepTime(time.Since(start))
+		}
+		if opts.Stop != nil && opts.Stop() {
+			break
+		}
+		if opts.Frame == nil {
+			continue
+		}
+		due := frames == nil
+		select {
+		case <-frames:
+			due = true
+		default:
+		}
+		if due {
+			if err := opts.Frame(false); err != nil {
+				return err
+			}
+		}
+	}
+	if opts.Frame != nil {
+		if err := opts.Frame(true); err != nil {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,62 @@
+package life
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestRun checks how runs end and how often their hooks are called.
+func TestRun(t *testing.T) {
+	grid := NewLifeFromField(soup("B3/S23"))
+	frames, final, timed := 0, 0, 0
+	err := grid.Run(context.Background(), RunOptions{
+		Generations: 5,
+		Frame: func(last bool) error {
+			if last {
+				final++
+			} else {
+				frames++
+			}
+			return nil
+		},
+		StepTime: func(time.Duration) { timed++ },
+	})
+	if err != nil || grid.Generation() != 5 || frames != 5 || final != 1 || timed != 5 {
+		t.Errorf("run to generation %d with %d frames, %d final and %d timed steps, error %v; want 5, 5, 1, 5",
+			grid.Generation(), frames, final, timed, err)
+	}
+
+	n := 0
+	if err := RunSteps(context.Background(), func() { n++ }, RunOptions{Stop: func() bool { return n == 3 }}); err != nil || n != 3 {
+		t.Errorf("stopped after %d steps with error %v, want 3", n, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n, final = 0, 0
+	err = RunSteps(ctx, func() {
+		if n++; n == 10 {
+			cancel()
+		}
+	}, RunOptions{StepsPerSecond: 1000, Frame: func(last bool) error {
+		if last {
+			final++
+		}
+		return nil
+	}})
+	if !errors.Is(err, context.Canceled) || n != 10 || final != 1 {
+		t.Errorf("cancelled run: %d steps, %d final frames, error %v; want 10, 1 and context.Canceled", n, final, err)
+	}
+
+	stop := errors.New("stop")
+	n = 0
+	if err := RunSteps(context.Background(), func() { n++ }, RunOptions{Frame: func(bool) error { return stop }}); err != stop || n != 1 {
+		t.Errorf("run ended by its frame after %d steps with error %v, want 1 and %v", n, err, stop)
+	}
+	for _, opts := range []RunOptions{{StepsPerSecond: -1}, {FrameRate: -1}} {
+		if err := RunSteps(context.Background(), func() {}, opts); err == nil {
+			t.Errorf("RunSteps(%+v): no error", opts)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package life
+
+// Union returns a field holding the cells alive in f or g.
+func (f *Field) Union(g *Field) *Field {
+	return f.combine(g, func(a, b uint64) uint64 { return a | b })
+}
+
+// Intersect returns a field holding the cells alive in both f and g.
+func (f *Field) Intersect(g *Field) *Field {
+	return f.combine(g, func(a, b uint64) uint64 { return a & b })
+}
+
+// Subtract returns a field holding the cells alive in f but not in g.
+func (f *Field) Subtract(g *Field) *Field {
+	return f.combine(g, func(a, b uint64) uint64 { return a &^ b })
+}
+
+// Xor returns a field holding the cells alive in exactly one of f and g.
+func (f *Field) Xor(g *Field) *Field {
+	return f.combine(g, func(a, b uint64) uint64 { return a ^ b })
+}
+
+// combine returns a copy of f, with its rule and topology, whose words are those of f and g combined by
+// op, 64 cells at a time. The fields are meant to have the same size; if
+// they do not, the cells of g beyond f are ignored and those it lacks count
+// as dead.
+func (f *Field) combine(g *Field, op func(a, b uint64) uint64) *Field {
+	c := f.Clone()
+	tail := f.tailMask()
+	for y := 0; y < f.h; y++ {
+		for i := 0; i < f.stride; i++ {
+			k := c.index(i, y)
+			c.bits[k] = op(f.bits[k], g.wordAt(i, y))
+			if i == f.stride-1 {
+				c.bits[k] &= tail
+			}
+		}
+	}
+	return c
+}
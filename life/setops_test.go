@@ -0,0 +1,41 @@
+package life
+
+import (
+	"image"
+	"slices"
+	"testing"
+)
+
+// TestSetOps checks the set operations on fields, including the words past
+// the first of a row, and that the other field is cut to the size of the
+// first.
+func TestSetOps(t *testing.T) {
+	a := NewFieldWithTopology(70, 2, Plane)
+	b := NewField(72, 3)
+	for _, p := range pts(0, 0, 1, 0, 65, 1) {
+		a.Set(p.X, p.Y, true)
+	}
+	for _, p := range pts(1, 0, 65, 1, 66, 1, 71, 0, 0, 2) {
+		b.Set(p.X, p.Y, true)
+	}
+	for _, tt := range []struct {
+		name string
+		f    *Field
+		want []image.Point
+	}{
+		{"Union", a.Union(b), pts(0, 0, 1, 0, 65, 1, 66, 1)},
+		{"Intersect", a.Intersect(b), pts(1, 0, 65, 1)},
+		{"Subtract", a.Subtract(b), pts(0, 0)},
+		{"Xor", a.Xor(b), pts(0, 0, 66, 1)},
+	} {
+		if got := liveCells(tt.f); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: cells %v, want %v", tt.name, got, tt.want)
+		}
+		if tt.f.Width() != 70 || tt.f.Height() != 2 || tt.f.Topology() != Plane {
+			t.Errorf("%s: %d×%d field on %v, want 70×2 on the plane", tt.name, tt.f.Width(), tt.f.Height(), tt.f.Topology())
+		}
+	}
+	if !slices.Equal(liveCells(a), pts(0, 0, 1, 0, 65, 1)) {
+		t.Error("the operations changed their field")
+	}
+}
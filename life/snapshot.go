@@ -0,0 +1,43 @@
+package life
+
+// A Snapshot is an immutable copy of the cells of a field at the time it
+// was taken. It shares the words of the field until the field is next
+// changed, by Set or by a step of the game holding it, so that taking one
+// costs next to nothing, and it can be read from any goroutine while the
+// field moves on.
+type Snapshot struct {
+	f Field
+}
+
+// Snapshot returns a snapshot of the field. It must be taken while nothing
+// changes the field, such as between two steps of the game holding it.
+func (f *Field) Snapshot() *Snapshot {
+	f.shared = true
+	return &Snapshot{f: Field{bits: f.bits, stride: f.stride, width: f.width, h: f.h, rule: f.rule, mask: f.mask, top: f.top, gen: f.gen, shared: true}}
+}
+
+// Width returns the width of the field.
+func (s *Snapshot) Width() int { return s.f.width }
+
+// Height returns the height of the field.
+func (s *Snapshot) Height() int { return s.f.h }
+
+// Rule returns the rule the field followed.
+func (s *Snapshot) Rule() Rule { return s.f.rule }
+
+// Topology returns the topology of the field.
+func (s *Snapshot) Topology() Topology { return s.f.top }
+
+// Alive reports whether the specified cell was alive, mapping coordinates
+// outside the field as Field.Alive does.
+func (s *Snapshot) Alive(x, y int) bool { return s.f.Alive(x, y) }
+
+// Population returns the number of live cells.
+func (s *Snapshot) Population() int { return s.f.Population() }
+
+// Field returns a field holding the cells of the snapshot, which the caller
+// may change freely. It too shares the words of the snapshot until then.
+func (s *Snapshot) Field() *Field {
+	f := s.f
+	return &f
+}
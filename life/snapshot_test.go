@@ -0,0 +1,37 @@
+package life
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestSnapshot checks that a snapshot keeps the cells of the time it was
+// taken while the field, the game holding it and fields made from the
+// snapshot move on.
+func TestSnapshot(t *testing.T) {
+	grid := NewLifeFromField(fieldOf(t, Plane, ".O....", "..O...", "OOO...", "......", "......"))
+	grid.SetRule(MustParseRule("B36/S23"))
+	s := grid.Field().Snapshot()
+	grid.Field().Set(5, 4, true)
+	grid.Step()
+	check := func(when string) {
+		t.Helper()
+		if got := liveCells(s.Field()); !slices.Equal(got, glider) || s.Population() != 5 || !s.Alive(1, 0) {
+			t.Errorf("%s: snapshot holds %v, want %v", when, got, glider)
+		}
+	}
+	check("after a step")
+	if s.Width() != 6 || s.Height() != 5 || s.Rule() != MustParseRule("B36/S23") || s.Topology() != Plane {
+		t.Errorf("snapshot of a %d×%d %v field on %v", s.Width(), s.Height(), s.Rule(), s.Topology())
+	}
+	f := s.Field()
+	f.Set(0, 0, true)
+	f.Set(1, 0, false)
+	check("after changing a field made from it")
+	if got := liveCells(f); !slices.Equal(got, pts(0, 0, 2, 1, 0, 2, 1, 2, 2, 2)) {
+		t.Errorf("changed field holds %v", got)
+	}
+	if grid.Field().Alive(0, 0) {
+		t.Error("the game sees changes to a field made from a snapshot")
+	}
+}
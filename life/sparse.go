@@ -0,0 +1,146 @@
+package life
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+)
+
+// SparseField is an unbounded plane of cells evolving under a B/S rule that
+// stores the set of its live cells, so that each step only visits the live
+// cells and their neighbors. On sparse boards it is much faster than a
+// Field, and unlike an Infinite plane it costs nothing for the empty space
+// around isolated cells.
+type SparseField struct {
+	live map[image.Point]struct{}
+	rule Rule
+	gen  int64 // number of steps taken
+}
+
+// NewSparseField returns an empty sparse field that follows the Conway
+// rule.
+func NewSparseField() *SparseField {
+	return &SparseField{live: make(map[image.Point]struct{}), rule: Conway}
+}
+
+// NewSparseFieldFromField returns a sparse field whose cells at
+// 0 ≤ x < width and 0 ≤ y < height are those of f and which follows the rule
+// of f.
+func NewSparseFieldFromField(f *Field) (*SparseField, error) {
+	s := NewSparseField()
+	if err := s.SetRule(f.rule); err != nil {
+		return nil, err
+	}
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; x++ {
+			if f.get(x, y) {
+				s.live[image.Pt(x, y)] = struct{}{}
+			}
+		}
+	}
+	return s, nil
+}
+
+// Rule returns the rule the field follows.
+func (s *SparseField) Rule() Rule { return s.rule }
+
+// SetRule changes the rule the field follows. Rules with B0 are rejected,
+// as they would bring the whole empty plane to life.
+func (s *SparseField) SetRule(r Rule) error {
+	if r.B0() {
+		return fmt.Errorf("life: rule %v with B0 is not supported on a sparse field", r)
+	}
+	s.rule = r
+	return nil
+}
+
+// Generation returns the number of steps taken.
+func (s *SparseField) Generation() int64 { return s.gen }
+
+// Set sets the state of the specified cell to the given value.
+func (s *SparseField) Set(x, y int, b bool) {
+	if b {
+		s.live[image.Pt(x, y)] = struct{}{}
+	} else {
+		delete(s.live, image.Pt(x, y))
+	}
+}
+
+// Alive reports whether the specified cell is alive.
+func (s *SparseField) Alive(x, y int) bool {
+	_, ok := s.live[image.Pt(x, y)]
+	return ok
+}
+
+// Population returns the number of live cells.
+func (s *SparseField) Population() int { return len(s.live) }
+
+// Bounds returns the smallest rectangle containing every live cell, or an
+// empty rectangle if there are none.
+func (s *SparseField) Bounds() image.Rectangle {
+	var r image.Rectangle
+	for p := range s.live {
+		r = r.Union(image.Rectangle{p, p.Add(image.Pt(1, 1))})
+	}
+	return r
+}
+
+// Field returns a copy of the cells of the sparse field within r as a field
+// with dead edges that follows the rule of the sparse field, the cell at
+// r.Min being at 0, 0.
+func (s *SparseField) Field(r image.Rectangle) *Field {
+	f := NewFieldWithTopology(r.Dx(), r.Dy(), Plane)
+	f.rule = s.rule
+	for p := range s.live {
+		if p.In(r) {
+			f.set(p.X-r.Min.X, p.Y-r.Min.Y, true)
+		}
+	}
+	return f
+}
+
+// Step advances the field by one generation.
+func (s *SparseField) Step() {
+	// Count the live neighbors of every live cell and every cell next to
+	// one; other cells cannot come to life, as the rule has no B0.
+	counts := make(map[image.Point]int, 3*len(s.live))
+	for p := range s.live {
+		if _, ok := counts[p]; !ok {
+			counts[p] = 0
+		}
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if dx != 0 || dy != 0 {
+					counts[p.Add(image.Pt(dx, dy))]++
+				}
+			}
+		}
+	}
+	next := make(map[image.Point]struct{}, len(s.live))
+	for p, n := range counts {
+		_, alive := s.live[p]
+		if s.rule.Next(alive, n) {
+			next[p] = struct{}{}
+		}
+	}
+	s.live = next
+	s.gen++
+}
+
+// String returns the smallest rectangle of the sparse field containing
+// every live cell as a string, in the format of Life.String.
+func (s *SparseField) String() string {
+	r := s.Bounds()
+	var buf bytes.Buffer
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			b := byte(' ')
+			if s.Alive(x, y) {
+				b = '*'
+			}
+			buf.WriteByte(b)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
@@ -0,0 +1,125 @@
+package life
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stochastic is a probabilistic variant of a B/S rule in which births and
+// survivals only happen with a given probability for each neighbor count.
+// Its random numbers come from a source of its own, so that runs seeded
+// alike evolve alike.
+type Stochastic struct {
+	birth, survival [9]float64 // probability for each neighbor count
+	rand            *rand.Rand
+}
+
+// ParseStochastic parses a stochastic rule in B/S notation in which each
+// group of neighbor counts may be followed by its probability in
+// parentheses, the default being 1. For instance, in "B3(0.98)/S23(0.99)"
+// cells are born with 3 neighbors 98% of the time and survive with 2 or 3
+// neighbors 99% of the time, and in "B3/S2(0.5)3" cells with 3 neighbors
+// always survive. The random numbers of the rule are drawn from src, or
+// from a source seeded with the current time if src is nil.
+func ParseStochastic(s string, src rand.Source) (*Stochastic, error) {
+	u := strings.ToUpper(strings.TrimSpace(s))
+	p, q, ok := strings.Cut(u, "/")
+	if !ok || !strings.HasPrefix(p, "B") || !strings.HasPrefix(q, "S") {
+		return nil, fmt.Errorf("life: invalid stochastic rule %q", s)
+	}
+	if src == nil {
+		src = rand.NewSource(time.Now().UnixNano())
+	}
+	r := &Stochastic{rand: rand.New(src)}
+	if err := parseProbabilities(p[1:], &r.birth); err != nil {
+		return nil, fmt.Errorf("life: invalid stochastic rule %q: %v", s, err)
+	}
+	if err := parseProbabilities(q[1:], &r.survival); err != nil {
+		return nil, fmt.Errorf("life: invalid stochastic rule %q: %v", s, err)
+	}
+	return r, nil
+}
+
+// parseProbabilities parses neighbor counts, each group of which may be
+// followed by a probability in parentheses, into p.
+func parseProbabilities(s string, p *[9]float64) error {
+	var group []int
+	for s != "" {
+		c := s[0]
+		switch {
+		case c >= '0' && c <= '8':
+			n := int(c - '0')
+			if p[n] != 0 {
+				return fmt.Errorf("repeated neighbor count %q", c)
+			}
+			p[n] = 1
+			group = append(group, n)
+			s = s[1:]
+		case c == '(':
+			end := strings.IndexByte(s, ')')
+			if end < 0 || len(group) == 0 {
+				return fmt.Errorf("misplaced probability")
+			}
+			v, err := strconv.ParseFloat(s[1:end], 64)
+			if err != nil || v <= 0 || v > 1 {
+				return fmt.Errorf("invalid probability %q", s[1:end])
+			}
+			for _, n := range group {
+				p[n] = v
+			}
+			group, s = group[:0], s[end+1:]
+		default:
+			return fmt.Errorf("invalid neighbor count %q", c)
+		}
+	}
+	return nil
+}
+
+// String returns the rule in the notation of ParseStochastic.
+func (r *Stochastic) String() string {
+	var buf strings.Builder
+	buf.WriteByte('B')
+	writeProbabilities(&buf, &r.birth)
+	buf.WriteString("/S")
+	writeProbabilities(&buf, &r.survival)
+	return buf.String()
+}
+
+// writeProbabilities writes the neighbor counts with a non-zero probability
+// in p, grouping successive counts of equal probability and omitting a
+// final probability of 1.
+func writeProbabilities(buf *strings.Builder, p *[9]float64) {
+	last := 0.0
+	for n, v := range p {
+		if v == 0 {
+			continue
+		}
+		if last != 0 && v != last {
+			// A group followed by another needs its probability even
+			// if it is 1.
+			fmt.Fprintf(buf, "(%v)", last)
+		}
+		buf.WriteByte(byte('0' + n))
+		last = v
+	}
+	if last != 0 && last != 1 {
+		fmt.Fprintf(buf, "(%v)", last)
+	}
+}
+
+// Step implements Stepper.
+func (r *Stochastic) Step(dst, src *Field) {
+	for y := 0; y < src.h; y++ {
+		for x := 0; x < src.width; x++ {
+			p := &r.birth
+			if src.get(x, y) {
+				p = &r.survival
+			}
+			q := p[Moore(src, x, y)]
+			dst.set(x, y, q == 1 || q > 0 && r.rand.Float64() < q)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package life
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestParseStochastic checks that String writes rules as they are parsed,
+// and that malformed probabilities are rejected.
+func TestParseStochastic(t *testing.T) {
+	for _, s := range []string{"B3(0.98)/S23(0.99)", "B3/S2(0.5)3", "B36/S23", "B1(0.25)2(0.5)/S"} {
+		r, err := ParseStochastic(s, rand.NewSource(1))
+		if err != nil {
+			t.Errorf("ParseStochastic(%q): %v", s, err)
+		} else if r.String() != s {
+			t.Errorf("ParseStochastic(%q).String() = %q", s, r)
+		}
+	}
+	for _, s := range []string{"B3", "B3(0)/S23", "B3(1.5)/S23", "B(0.5)3/S23", "B3(0.5/S23", "B33/S23", "B9/S23"} {
+		if _, err := ParseStochastic(s, nil); err == nil {
+			t.Errorf("ParseStochastic(%q): no error", s)
+		}
+	}
+}
+
+// TestStochasticStep checks that certain rules step as the Rule, that runs
+// from sources seeded alike are alike, and that births happen about as
+// often as their probability.
+func TestStochasticStep(t *testing.T) {
+	r, err := ParseStochastic("B3/S23", rand.NewSource(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := NewLifeFromField(soup("B3/S23"))
+	grid := want.Clone()
+	grid.SetStepper(r)
+	for gen := 1; gen <= 20; gen++ {
+		want.Step()
+		grid.Step()
+		if !grid.Field().Equal(want.Field()) {
+			t.Fatalf("B3/S23 differs from the Rule at generation %d", gen)
+		}
+	}
+
+	run := func(seed int64) *Field {
+		r, err := ParseStochastic("B3(0.5)/S23(0.9)", rand.NewSource(seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		grid := NewLifeFromField(soup("B3/S23"))
+		grid.SetStepper(r)
+		grid.StepN(10)
+		return grid.Field()
+	}
+	if !run(7).Equal(run(7)) {
+		t.Error("runs seeded alike differ")
+	}
+	if run(7).Equal(run(8)) {
+		t.Error("runs seeded differently are alike")
+	}
+
+	// Under B3, only the two cells beside the middle of a blinker are born.
+	half, err := ParseStochastic("B3(0.5)/S", rand.NewSource(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := fieldOf(t, Plane, ".....", ".....", ".OOO.", ".....", ".....")
+	dst := NewFieldWithTopology(5, 5, Plane)
+	born := 0
+	const tries = 2000
+	for i := 0; i < tries; i++ {
+		half.Step(dst, src)
+		born += dst.Population()
+	}
+	if born < 0.45*2*tries || born > 0.55*2*tries {
+		t.Errorf("%d births in %d tries of 2 cells, want about half", born, tries)
+	}
+}
@@ -0,0 +1,47 @@
+package life
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+)
+
+// WriteSVG writes the field to w as an SVG image, using the cell size and
+// colors of opt as in Image. Horizontal runs of live cells are merged into a
+// single rectangle to keep the document small. A generation other than 0
+// is recorded in the description of the document.
+func (f *Field) WriteSVG(w io.Writer, opt *ImageOptions) error {
+	n := opt.cellSize()
+	p := opt.palette()
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n",
+		f.width*n, f.h*n, f.width, f.h)
+	if c := generationComment(f.gen); c != "" {
+		fmt.Fprintf(bw, "<desc>%s</desc>\n", c)
+	}
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="%s"/>`+"\n", f.width, f.h, svgColor(p[0]))
+	fmt.Fprintf(bw, `<g fill="%s">`+"\n", svgColor(p[1]))
+	for y := 0; y < f.h; y++ {
+		for x := 0; x < f.width; {
+			if !f.get(x, y) {
+				x++
+				continue
+			}
+			x0 := x
+			for x < f.width && f.get(x, y) {
+				x++
+			}
+			fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="1"/>`+"\n", x0, y, x-x0)
+		}
+	}
+	fmt.Fprintln(bw, "</g>")
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// svgColor returns c in the #rrggbb notation. Opacity is ignored.
+func svgColor(c color.Color) string {
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x", nc.R, nc.G, nc.B)
+}
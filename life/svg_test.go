@@ -0,0 +1,47 @@
+package life
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestWriteSVG checks that runs of live cells are merged into rectangles,
+// that the document parses as XML and that it records the generation.
+func TestWriteSVG(t *testing.T) {
+	f := fieldOf(t, Torus, "OOO.O", ".....", ".OO..")
+	f.SetGeneration(4)
+	var buf bytes.Buffer
+	if err := f.WriteSVG(&buf, &ImageOptions{CellSize: 10, Alive: color.RGBA{0, 128, 255, 255}}); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	for _, want := range []string{
+		`width="50" height="30" viewBox="0 0 5 3"`,
+		`<desc>Generation 4</desc>`,
+		`<rect width="5" height="3" fill="#ffffff"/>`,
+		`<g fill="#0080ff">`,
+		`<rect x="0" y="0" width="3" height="1"/>`,
+		`<rect x="4" y="0" width="1" height="1"/>`,
+		`<rect x="1" y="2" width="2" height="1"/>`,
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("SVG lacks %s:\n%s", want, s)
+		}
+	}
+	if n := strings.Count(s, "<rect"); n != 4 {
+		t.Errorf("SVG has %d rectangles, want 4", n)
+	}
+	d := xml.NewDecoder(&buf)
+	for {
+		if _, err := d.Token(); err != nil {
+			if err != io.EOF {
+				t.Errorf("invalid XML: %v", err)
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package life
+
+import (
+	"io"
+	"sync"
+)
+
+// SyncedLife is a game that can be used from several goroutines at once.
+// Steps and edits hold a lock on the game, while reads, such as renderings
+// of the board, share one. Like the renderings and snapshots, Set and Alive
+// deal in the true states of cells, whether or not the field of the game is
+// inverted; see Life.Inverted.
+type SyncedLife struct {
+	mu   sync.RWMutex
+	grid *Life
+}
+
+// Synced returns a wrapper of grid safe for concurrent use. The game must no
+// longer be used directly.
+func Synced(grid *Life) *SyncedLife {
+	return &SyncedLife{grid: grid}
+}
+
+// Step advances the game by one generation.
+func (s *SyncedLife) Step() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.grid.Step()
+}
+
+// StepN advances the game by n generations, holding the lock throughout.
+func (s *SyncedLife) StepN(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.grid.StepN(n)
+}
+
+// Set sets the state of the specified cell of the current generation, which
+// must be in the field, to the given value.
+func (s *SyncedLife) Set(x, y int, b bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.grid.Field().Set(x, y, b != s.grid.inverted)
+}
+
+// Alive reports whether the specified cell of the current generation is
+// alive, mapping coordinates outside the field as Field.Alive does.
+func (s *SyncedLife) Alive(x, y int) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.grid.Alive(x, y)
+}
+
+// Generation returns the number of the current generation.
+func (s *SyncedLife) Generation() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.grid.Generation()
+}
+
+// Population returns the number of live cells in the current generation.
+func (s *SyncedLife) Population() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.grid.Population()
+}
+
+// Render writes the game board to w as Life.Render does. The text is built
+// in a buffer of its own, and written once the lock is released, so that
+// several goroutines can render the board at once and a slow writer holds
+// back none of them.
+func (s *SyncedLife) Render(w io.Writer) error {
+	s.mu.RLock()
+	buf := s.grid.appendText(nil)
+	s.mu.RUnlock()
+	_, err := w.Write(buf)
+	return err
+}
+
+// String returns the game board as a string.
+func (s *SyncedLife) String() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return string(s.grid.appendText(nil))
+}
+
+// Snapshot returns a snapshot of the true state of the current generation,
+// which can be read without holding the lock.
+func (s *SyncedLife) Snapshot() *Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.grid.inverted {
+		return s.grid.State().Snapshot()
+	}
+	return s.grid.Field().Snapshot()
+}
+
+// Do calls fn with the game while holding the lock, for anything the
+// wrapper does not provide. The game must not be kept beyond the call.
+func (s *SyncedLife) Do(fn func(grid *Life)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.grid)
+}
@@ -0,0 +1,77 @@
+package life
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestSynced checks that a game stepped and read from several goroutines
+// at once ends in the same state as one stepped alone.
+func TestSynced(t *testing.T) {
+	want := NewLifeFromField(fieldOf(t, Torus, ".O......", "..O.....", "OOO.....", "........", "........", "........"))
+	s := Synced(NewLifeFromField(want.Field().Clone()))
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				s.Step()
+			}
+			s.StepN(3)
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				var b strings.Builder
+				if err := s.Render(&b); err != nil {
+					t.Error(err)
+				}
+				if n := s.Population(); n != 5 {
+					t.Errorf("population %d at generation %d, want 5", n, s.Generation())
+				}
+				_ = s.String()
+				_ = s.Alive(0, 0)
+				if snap := s.Snapshot(); snap.Population() != 5 {
+					t.Errorf("snapshot population %d, want 5", snap.Population())
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	want.StepN(32)
+	if s.Generation() != 32 || s.String() != want.String() {
+		t.Errorf("generation %d:\n%s\nwant generation 32:\n%s", s.Generation(), s, want)
+	}
+	s.Do(func(grid *Life) {
+		if !grid.Field().Equal(want.Field()) {
+			t.Error("Do: field differs from the game stepped alone")
+		}
+	})
+}
+
+// TestSyncedInverted checks that Set, Alive and Snapshot deal in the true
+// states of cells while the field of the game is inverted.
+func TestSyncedInverted(t *testing.T) {
+	f := NewField(4, 4)
+	f.SetRule(MustParseRule("B0123478/S01234678"))
+	s := Synced(NewLifeFromField(f))
+	s.Step()
+	s.Do(func(grid *Life) {
+		if !grid.Inverted() {
+			t.Fatal("game under a B0 rule without S8 is not inverted")
+		}
+	})
+	// The empty board comes alive in full, so clear one cell of it.
+	s.Set(1, 2, false)
+	if s.Alive(1, 2) || !s.Alive(2, 1) || s.Population() != 15 {
+		t.Errorf("after Set(1, 2, false): Alive(1, 2) = %v, Alive(2, 1) = %v, population %d", s.Alive(1, 2), s.Alive(2, 1), s.Population())
+	}
+	dead := s.Snapshot().Field()
+	dead.invert()
+	if got, want := liveCells(dead), pts(1, 2); !slices.Equal(got, want) {
+		t.Errorf("snapshot dead cells %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,288 @@
+package life
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Edge is the behavior of a field beyond the ends of one of its axes.
+type Edge uint8
+
+const (
+	// Wrap joins the ends of the axis, so that cells beyond one end are
+	// those at the other.
+	Wrap Edge = iota
+
+	// DeadEdge makes every cell beyond the ends of the axis dead.
+	DeadEdge
+
+	// Mirror reflects the field at the ends of the axis, so that the cell
+	// beyond an end is the one at that end, the next is the one before it,
+	// and so on.
+	Mirror
+
+	// Flip joins the ends of the axis with a twist, so that cells beyond
+	// one end are those at the other with the other coordinate reversed.
+	Flip
+)
+
+// Topology gives the behavior of a field beyond the ends of each of its
+// axes. For instance, Topology{X: Wrap, Y: DeadEdge} is a horizontal
+// cylinder. The ends of a wrapping axis may also be joined with a shift
+// along the other axis, making a twisted torus as needed by some agars
+// and wicks.
+type Topology struct {
+	X, Y Edge
+
+	// ShiftX is the number of cells by which x moves when crossing the top
+	// or bottom edge, if the y axis wraps, and ShiftY that by which y moves
+	// when crossing the left or right edge, if the x axis wraps.
+	ShiftX, ShiftY int
+}
+
+// Torus is the topology in which both axes wrap, the default.
+var Torus = Topology{X: Wrap, Y: Wrap}
+
+// Plane is the topology in which cells beyond every edge are dead, so that
+// a field behaves as a bounded region of the infinite plane. Patterns that
+// would interact with their own images on a small torus are better run on
+// it.
+var Plane = Topology{X: DeadEdge, Y: DeadEdge}
+
+// The non-orientable surfaces: a Klein bottle, whose top and bottom edges
+// are joined with a twist and left and right ones without, a Möbius band,
+// whose left and right edges are joined with a twist and top and bottom
+// ones are dead, and a projective plane, also known as a cross-surface,
+// with both pairs of edges joined with a twist.
+var (
+	KleinBottle     = Topology{X: Wrap, Y: Flip}
+	MobiusBand      = Topology{X: Flip, Y: DeadEdge}
+	ProjectivePlane = Topology{X: Flip, Y: Flip}
+)
+
+// String returns the name of the topology in the notation of
+// ParseTopology.
+func (t Topology) String() string {
+	return t.X.String() + shiftString(t.ShiftX) + "/" + t.Y.String() + shiftString(t.ShiftY)
+}
+
+// shiftString returns a shift as written after an edge behavior, or "" if
+// it is zero.
+func shiftString(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%+d", n)
+}
+
+// String returns the name of the edge behavior.
+func (e Edge) String() string {
+	switch e {
+	case Wrap:
+		return "wrap"
+	case DeadEdge:
+		return "dead"
+	case Mirror:
+		return "mirror"
+	case Flip:
+		return "flip"
+	}
+	return "unknown"
+}
+
+// ParseTopology parses a topology written as the behaviors of the x and y
+// axes separated by a slash, each of "wrap", "dead", "mirror" or "flip",
+// such as "wrap/dead" for a horizontal cylinder, or as a single behavior for
+// both axes. The names "torus" and "plane" stand for "wrap" and "dead", and
+// "klein", "mobius" and "projective" for the non-orientable surfaces. A
+// signed shift may follow the behavior of an axis, as in "wrap+1/wrap" for a
+// torus whose top and bottom edges are joined with x moving by one cell,
+// provided the other axis wraps.
+func ParseTopology(s string) (Topology, error) {
+	bad := func() (Topology, error) { return Topology{}, fmt.Errorf("life: invalid topology %q", s) }
+	xs, ys, ok := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "/")
+	switch xs {
+	case "klein":
+		return KleinBottle, nil
+	case "mobius", "möbius":
+		return MobiusBand, nil
+	case "projective", "cross-surface":
+		return ProjectivePlane, nil
+	}
+	if !ok {
+		ys = xs
+	}
+	xs, sx, okx := cutShift(xs)
+	ys, sy, oky := cutShift(ys)
+	x, okx2 := parseEdge(xs)
+	y, oky2 := parseEdge(ys)
+	if !okx || !oky || !okx2 || !oky2 {
+		return bad()
+	}
+	top := Topology{X: x, Y: y, ShiftX: sx, ShiftY: sy}
+	if sx != 0 && y != Wrap || sy != 0 && x != Wrap || sx != 0 && sy != 0 {
+		return bad()
+	}
+	return top, nil
+}
+
+// cutShift splits a signed shift such as "+1" off the end of s, returning
+// a shift of zero if there is none.
+func cutShift(s string) (rest string, shift int, ok bool) {
+	i := strings.IndexAny(s, "+-")
+	if i < 0 {
+		return s, 0, true
+	}
+	n, err := strconv.Atoi(s[i:])
+	return s[:i], n, err == nil
+}
+
+// parseEdge parses the name of an edge behavior.
+func parseEdge(s string) (Edge, bool) {
+	switch s {
+	case "wrap", "torus":
+		return Wrap, true
+	case "dead", "plane":
+		return DeadEdge, true
+	case "mirror":
+		return Mirror, true
+	case "flip":
+		return Flip, true
+	}
+	return 0, false
+}
+
+// ParseBoundedGrid parses the size and topology of a bounded grid in the
+// notation Golly appends to rules, such as ":T40,20" for a torus of width
+// 40 and height 20, ":P40,20" for a plane, ":K40*,20" for a Klein bottle
+// whose top and bottom edges, of length 40, are joined with a twist, and
+// ":C40,20" for a cross-surface. A torus may have a shift after either
+// size, as in ":T40+1,20" for one whose top and bottom edges, of length 40,
+// are joined with x moving by one cell. Spheres and unbounded axes are not
+// supported.
+func ParseBoundedGrid(s string) (width, h int, top Topology, err error) {
+	bad := func() (int, int, Topology, error) {
+		return 0, 0, Topology{}, fmt.Errorf("life: invalid bounded grid %q", s)
+	}
+	spec, ok := strings.CutPrefix(strings.TrimSpace(s), ":")
+	if !ok || spec == "" {
+		return bad()
+	}
+	ws, hs, ok := strings.Cut(spec[1:], ",")
+	if !ok {
+		return bad()
+	}
+	ws, wshift, okw := cutShift(ws)
+	hs, hshift, okh := cutShift(hs)
+	if !okw || !okh || (wshift != 0 || hshift != 0) && spec[0] != 'T' && spec[0] != 't' {
+		return bad()
+	}
+	ws, wtwist := strings.CutSuffix(ws, "*")
+	hs, htwist := strings.CutSuffix(hs, "*")
+	if width, err = strconv.Atoi(ws); err != nil || width <= 0 {
+		return bad()
+	}
+	if h, err = strconv.Atoi(hs); err != nil || h <= 0 {
+		return bad()
+	}
+	switch spec[0] {
+	case 'T', 't':
+		if wshift != 0 && hshift != 0 {
+			return bad()
+		}
+		top = Topology{X: Wrap, Y: Wrap, ShiftX: wshift, ShiftY: hshift}
+	case 'P', 'p':
+		top = Plane
+	case 'K', 'k':
+		// The twisted edges are the pair whose length is starred.
+		switch {
+		case wtwist && !htwist:
+			top = Topology{X: Wrap, Y: Flip}
+		case htwist && !wtwist:
+			top = Topology{X: Flip, Y: Wrap}
+		default:
+			return bad()
+		}
+		wtwist, htwist = false, false
+	case 'C', 'c':
+		top = ProjectivePlane
+	default:
+		return bad()
+	}
+	if wtwist || htwist {
+		return bad()
+	}
+	return width, h, top, nil
+}
+
+// BoundedGrid returns the topology with the given size in the notation of
+// ParseBoundedGrid, reporting false if Golly has no notation for it.
+func (t Topology) BoundedGrid(width, h int) (string, bool) {
+	var kind, ws, hs string
+	switch (Topology{X: t.X, Y: t.Y}) {
+	case Torus:
+		kind, ws, hs = "T", shiftString(t.ShiftX), shiftString(t.ShiftY)
+	case Plane:
+		kind = "P"
+	case Topology{X: Wrap, Y: Flip}:
+		kind, ws = "K", "*"
+	case Topology{X: Flip, Y: Wrap}:
+		kind, hs = "K", "*"
+	case ProjectivePlane:
+		kind = "C"
+	default:
+		return "", false
+	}
+	return fmt.Sprintf(":%s%d%s,%d%s", kind, width, ws, h, hs), true
+}
+
+// NewFieldWithTopology returns an empty field of the specified width and
+// height with the given topology that follows the Conway rule.
+func NewFieldWithTopology(width, h int, top Topology) *Field {
+	f := NewField(width, h)
+	f.top = top
+	return f
+}
+
+// Topology returns the topology of the field.
+func (f *Field) Topology() Topology { return f.top }
+
+// SetTopology sets the topology of the field.
+func (f *Field) SetTopology(top Topology) { f.top = top }
+
+// floorDiv returns a divided by b rounded towards negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// wrap returns v wrapped into the range [0, n), for any v.
+func wrap(v, n int) int {
+	return (v%n + n) % n
+}
+
+// edge maps coordinate v of an axis of length n with edge behavior e into
+// the range [0, n), reporting false if it lies beyond a dead edge and
+// whether the other coordinate is to be reversed.
+func edge(v, n int, e Edge) (c int, ok, flip bool) {
+	if v >= 0 && v < n {
+		return v, true, false
+	}
+	switch e {
+	case DeadEdge:
+		return 0, false, false
+	case Mirror:
+		v = wrap(v, 2*n)
+		if v >= n {
+			v = 2*n - 1 - v
+		}
+		return v, true, false
+	case Flip:
+		return wrap(v, n), true, wrap(v, 2*n) >= n
+	}
+	return wrap(v, n), true, false
+}
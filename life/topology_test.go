@@ -0,0 +1,244 @@
+package life
+
+import (
+	"image"
+	"testing"
+)
+
+// TestParseTopology checks the topologies parsed from their names, and
+// that String writes them back in the same notation.
+func TestParseTopology(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want Topology
+	}{
+		{"wrap", Torus},
+		{"torus", Torus},
+		{"Wrap/Dead", Topology{X: Wrap, Y: DeadEdge}},
+		{" dead/mirror ", Topology{X: DeadEdge, Y: Mirror}},
+		{"mirror", Topology{X: Mirror, Y: Mirror}},
+	} {
+		got, err := ParseTopology(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseTopology(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+			continue
+		}
+		if back, err := ParseTopology(got.String()); err != nil || back != got {
+			t.Errorf("ParseTopology(%q) = %v, %v; want %v", got.String(), back, err, got)
+		}
+	}
+	for _, in := range []string{"", "round", "wrap/", "wrap/dead/mirror"} {
+		if top, err := ParseTopology(in); err == nil {
+			t.Errorf("ParseTopology(%q) = %v with no error", in, top)
+		}
+	}
+}
+
+// TestWrapEdges checks the cells that those just beyond each edge of a
+// field stand for under each edge behavior.
+func TestWrapEdges(t *testing.T) {
+	for _, tt := range []struct {
+		top    Topology
+		x, y   int
+		wx, wy int
+		ok     bool
+	}{
+		{Torus, -1, 0, 4, 0, true},
+		{Torus, 5, 3, 0, 0, true},
+		{Topology{X: Wrap, Y: DeadEdge}, -1, 2, 4, 2, true},
+		{Topology{X: Wrap, Y: DeadEdge}, 1, -1, 0, 0, false},
+		{Topology{X: Wrap, Y: DeadEdge}, 1, 3, 0, 0, false},
+		{Topology{X: Mirror, Y: Mirror}, -1, 0, 0, 0, true},
+		{Topology{X: Mirror, Y: Mirror}, -2, 3, 1, 2, true},
+		{Topology{X: Mirror, Y: Mirror}, 6, -1, 3, 0, true},
+		{Topology{X: DeadEdge, Y: Wrap}, 2, -1, 2, 2, true},
+		{Topology{X: DeadEdge, Y: Wrap}, 5, 1, 0, 0, false},
+	} {
+		f := NewFieldWithTopology(5, 3, tt.top)
+		x, y, ok := f.Wrap(tt.x, tt.y)
+		if ok != tt.ok || ok && (x != tt.wx || y != tt.wy) {
+			t.Errorf("%v: Wrap(%d, %d) = %d, %d, %v; want %d, %d, %v", tt.top, tt.x, tt.y, x, y, ok, tt.wx, tt.wy, tt.ok)
+		}
+		if ok {
+			f.Set(x, y, true)
+			if !f.Alive(tt.x, tt.y) {
+				t.Errorf("%v: Alive(%d, %d) is false with cell %d, %d alive", tt.top, tt.x, tt.y, x, y)
+			}
+		} else if f.Fill(true); f.Alive(tt.x, tt.y) {
+			t.Errorf("%v: Alive(%d, %d) beyond a dead edge is true", tt.top, tt.x, tt.y)
+		}
+	}
+}
+
+// TestPlane checks that a field on the plane steps as the same cells
+// inside a larger field whose border is kept dead.
+func TestPlane(t *testing.T) {
+	f := randomField(70, 20, 5)
+	f.SetTopology(Plane)
+	big := NewField(72, 22)
+	for x, y := range f.LiveCells() {
+		big.Set(x+1, y+1, true)
+	}
+	grid, bigGrid := NewLifeFromField(f), NewLifeFromField(big)
+	for gen := 1; gen <= 20; gen++ {
+		grid.Step()
+		bigGrid.Step()
+		b := bigGrid.Field()
+		b.FillRect(0, 0, 72, 1, false)
+		b.FillRect(0, 21, 72, 22, false)
+		b.FillRect(0, 0, 1, 22, false)
+		b.FillRect(71, 0, 72, 22, false)
+		if !grid.Field().Equal(b.Crop(image.Rect(1, 1, 71, 21))) {
+			t.Fatalf("generation %d differs from the larger field", gen)
+		}
+	}
+	if top, err := ParseTopology("plane"); err != nil || top != Plane {
+		t.Errorf("ParseTopology(\"plane\") = %v, %v; want %v", top, err, Plane)
+	}
+}
+
+// TestTwistedEdges checks the cells beyond edges joined with a twist, and
+// the names of the non-orientable surfaces.
+func TestTwistedEdges(t *testing.T) {
+	for _, tt := range []struct {
+		top    Topology
+		x, y   int
+		wx, wy int
+		ok     bool
+	}{
+		{KleinBottle, 1, -1, 3, 2, true},
+		{KleinBottle, 1, 3, 3, 0, true},
+		{KleinBottle, -1, 0, 4, 0, true},
+		{MobiusBand, -1, 0, 4, 2, true},
+		{MobiusBand, 5, 2, 0, 0, true},
+		{MobiusBand, 0, -1, 0, 0, false},
+		{ProjectivePlane, 2, -1, 2, 2, true},
+		{ProjectivePlane, -1, 1, 4, 1, true},
+	} {
+		f := NewFieldWithTopology(5, 3, tt.top)
+		if x, y, ok := f.Wrap(tt.x, tt.y); ok != tt.ok || ok && (x != tt.wx || y != tt.wy) {
+			t.Errorf("%v: Wrap(%d, %d) = %d, %d, %v; want %d, %d, %v", tt.top, tt.x, tt.y, x, y, ok, tt.wx, tt.wy, tt.ok)
+		}
+	}
+	for name, want := range map[string]Topology{"klein": KleinBottle, "Möbius": MobiusBand, "cross-surface": ProjectivePlane, "wrap/flip": KleinBottle} {
+		if top, err := ParseTopology(name); err != nil || top != want {
+			t.Errorf("ParseTopology(%q) = %v, %v; want %v", name, top, err, want)
+		}
+	}
+}
+
+// TestParseBoundedGrid checks the bounded grids parsed from Golly's
+// notation, and that BoundedGrid writes them back.
+func TestParseBoundedGrid(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		w, h int
+		top  Topology
+	}{
+		{":T40,20", 40, 20, Torus},
+		{":P3,4", 3, 4, Plane},
+		{":K40*,20", 40, 20, KleinBottle},
+		{":K40,20*", 40, 20, Topology{X: Flip, Y: Wrap}},
+		{":C10,10", 10, 10, ProjectivePlane},
+	} {
+		w, h, top, err := ParseBoundedGrid(tt.in)
+		if err != nil || w != tt.w || h != tt.h || top != tt.top {
+			t.Errorf("ParseBoundedGrid(%q) = %d, %d, %v, %v; want %d, %d, %v", tt.in, w, h, top, err, tt.w, tt.h, tt.top)
+		} else if s, ok := top.BoundedGrid(w, h); !ok || s != tt.in {
+			t.Errorf("%v.BoundedGrid(%d, %d) = %q, %v; want %q", top, w, h, s, ok, tt.in)
+		}
+	}
+	for _, in := range []string{"", "T40,20", ":T40", ":T0,20", ":K40,20", ":K40*,20*", ":S40,40", ":P40*,20"} {
+		if _, _, _, err := ParseBoundedGrid(in); err == nil {
+			t.Errorf("ParseBoundedGrid(%q): no error", in)
+		}
+	}
+	if s, ok := (Topology{X: Mirror, Y: Wrap}).BoundedGrid(4, 4); ok {
+		t.Errorf("mirror/wrap BoundedGrid = %q, want none", s)
+	}
+}
+
+// TestWrapFar checks that coordinates many times the size of the field
+// away from it are mapped as those of the cells they are a whole number of
+// periods of the topology away from.
+func TestWrapFar(t *testing.T) {
+	f := NewField(5, 3)
+	if x, y, ok := f.Wrap(-1000001, 2000000002); !ok || x != 4 || y != 1 {
+		t.Errorf("Wrap(-1000001, 2000000002) = %d, %d, %v; want 4, 1, true", x, y, ok)
+	}
+	for _, top := range []Topology{Torus, {X: Mirror, Y: Mirror}, KleinBottle, MobiusBand, ProjectivePlane} {
+		f.SetTopology(top)
+		// Mirrored and twisted axes repeat every two lengths, and dead ones
+		// not at all.
+		px, py := 10, 6
+		for x := -12; x < 12; x++ {
+			for y := -7; y < 7; y++ {
+				wx, wy, ok := f.Wrap(x, y)
+				for _, k := range []int{-1 << 40, -3, 7, 1 << 40} {
+					if fx, fy, fok := f.Wrap(x+k*px, y); fx != wx || fy != wy || fok != ok {
+						t.Fatalf("%v: Wrap(%d, %d) = %d, %d, %v; want %d, %d, %v as for %d, %d", top, x+k*px, y, fx, fy, fok, wx, wy, ok, x, y)
+					}
+					if fx, fy, fok := f.Wrap(x, y+k*py); top.Y != DeadEdge && (fx != wx || fy != wy || fok != ok) {
+						t.Fatalf("%v: Wrap(%d, %d) = %d, %d, %v; want %d, %d, %v as for %d, %d", top, x, y+k*py, fx, fy, fok, wx, wy, ok, x, y)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestShiftedWrap checks the cells beyond the edges of twisted tori, their
+// notation, and that games on them step as the rule applied cell by cell.
+func TestShiftedWrap(t *testing.T) {
+	for _, tt := range []struct {
+		top    Topology
+		x, y   int
+		wx, wy int
+	}{
+		{Topology{X: Wrap, Y: Wrap, ShiftX: 1}, 2, 3, 3, 0},
+		{Topology{X: Wrap, Y: Wrap, ShiftX: 1}, 2, -1, 1, 2},
+		{Topology{X: Wrap, Y: Wrap, ShiftX: 1}, 4, 6, 1, 0},
+		{Topology{X: Wrap, Y: Wrap, ShiftY: -2}, 5, 0, 0, 1},
+		{Topology{X: Wrap, Y: Wrap, ShiftY: -2}, -1, 2, 4, 1},
+	} {
+		f := NewFieldWithTopology(5, 3, tt.top)
+		if x, y, ok := f.Wrap(tt.x, tt.y); !ok || x != tt.wx || y != tt.wy {
+			t.Errorf("%v: Wrap(%d, %d) = %d, %d, %v; want %d, %d, true", tt.top, tt.x, tt.y, x, y, ok, tt.wx, tt.wy)
+		}
+	}
+	for _, in := range []string{"wrap+1/wrap", "wrap/wrap-3"} {
+		if top, err := ParseTopology(in); err != nil || top.String() != in {
+			t.Errorf("ParseTopology(%q) = %v, %v", in, top, err)
+		}
+	}
+	for _, in := range []string{"wrap+1/dead", "dead/wrap+1", "wrap+1/wrap-1", "wrap+x/wrap"} {
+		if top, err := ParseTopology(in); err == nil {
+			t.Errorf("ParseTopology(%q) = %v with no error", in, top)
+		}
+	}
+	for _, in := range []string{":T40+1,20", ":T40,20-3"} {
+		if w, h, top, err := ParseBoundedGrid(in); err != nil {
+			t.Errorf("ParseBoundedGrid(%q): %v", in, err)
+		} else if s, _ := top.BoundedGrid(w, h); s != in {
+			t.Errorf("ParseBoundedGrid(%q) written back as %q", in, s)
+		}
+	}
+	for _, in := range []string{":P40+1,20", ":T40+1,20+1"} {
+		if _, _, _, err := ParseBoundedGrid(in); err == nil {
+			t.Errorf("ParseBoundedGrid(%q): no error", in)
+		}
+	}
+	for _, top := range []Topology{{X: Wrap, Y: Wrap, ShiftX: 3}, {X: Wrap, Y: Wrap, ShiftY: -1}} {
+		f := randomField(70, 9, 6)
+		f.SetTopology(top)
+		grid := NewLifeFromField(f.Clone())
+		grid.Step()
+		for y := 0; y < f.Height(); y++ {
+			for x := 0; x < f.Width(); x++ {
+				if grid.Field().Alive(x, y) != f.Next(x, y) {
+					t.Fatalf("%v: cell %d, %d differs from the rule applied cell by cell", top, x, y)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package life
+
+// Rotate90 returns a copy of the field rotated a quarter turn clockwise.
+func (f *Field) Rotate90() *Field {
+	top := Topology{X: f.top.Y, Y: f.top.X, ShiftX: -f.top.ShiftY, ShiftY: -f.top.ShiftX}
+	return f.transformed(f.h, f.width, top, func(x, y int) (int, int) { return f.h - 1 - y, x })
+}
+
+// FlipH returns a copy of the field mirrored left to right.
+func (f *Field) FlipH() *Field {
+	top := Topology{X: f.top.X, Y: f.top.Y, ShiftX: -f.top.ShiftX, ShiftY: -f.top.ShiftY}
+	return f.transformed(f.width, f.h, top, func(x, y int) (int, int) { return f.width - 1 - x, y })
+}
+
+// FlipV returns a copy of the field mirrored top to bottom.
+func (f *Field) FlipV() *Field {
+	top := Topology{X: f.top.X, Y: f.top.Y, ShiftX: -f.top.ShiftX, ShiftY: -f.top.ShiftY}
+	return f.transformed(f.width, f.h, top, func(x, y int) (int, int) { return x, f.h - 1 - y })
+}
+
+// Transpose returns a copy of the field mirrored along its main diagonal,
+// the cell at x, y moving to y, x.
+func (f *Field) Transpose() *Field {
+	top := Topology{X: f.top.Y, Y: f.top.X, ShiftX: f.top.ShiftY, ShiftY: f.top.ShiftX}
+	return f.transformed(f.h, f.width, top, func(x, y int) (int, int) { return y, x })
+}
+
+// transformed returns a field of the given size and topology that follows
+// the rule of f, whose live cells are those of f moved to the coordinates
+// that to returns for them. Mirroring an axis reverses the shifts with
+// which the axes of a twisted torus are joined, and swapping the axes swaps
+// them, so that the transformed field evolves as the transformed f.
+func (f *Field) transformed(width, h int, top Topology, to func(x, y int) (int, int)) *Field {
+	t := NewFieldWithTopology(width, h, top)
+	t.rule, t.gen = f.rule, f.gen
+	for x, y := range f.LiveCells() {
+		u, v := to(x, y)
+		t.set(u, v, true)
+	}
+	return t
+}
@@ -0,0 +1,72 @@
+package life
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// TestTransforms checks where each transform moves the cells of a glider,
+// and how the transforms compose.
+func TestTransforms(t *testing.T) {
+	f := fieldOf(t, Torus, ".O..", "..O.", "OOO.")
+	for _, tt := range []struct {
+		name string
+		g    *Field
+		w, h int
+		want []string
+	}{
+		{"Rotate90", f.Rotate90(), 3, 4, []string{"O..", "O.O", "OO.", "..."}},
+		{"FlipH", f.FlipH(), 4, 3, []string{"..O.", ".O..", ".OOO"}},
+		{"FlipV", f.FlipV(), 4, 3, []string{"OOO.", "..O.", ".O.."}},
+		{"Transpose", f.Transpose(), 3, 4, []string{"..O", "O.O", ".OO", "..."}},
+		{"Rotate90 four times", f.Rotate90().Rotate90().Rotate90().Rotate90(), 4, 3, []string{".O..", "..O.", "OOO."}},
+		{"FlipH of Rotate90", f.Rotate90().FlipH(), 3, 4, []string{"..O", "O.O", ".OO", "..."}},
+	} {
+		if tt.g.Width() != tt.w || tt.g.Height() != tt.h {
+			t.Errorf("%s: %d×%d field, want %d×%d", tt.name, tt.g.Width(), tt.g.Height(), tt.w, tt.h)
+		} else if got, want := liveCells(tt.g), liveCells(fieldOf(t, Torus, tt.want...)); !slices.Equal(got, want) {
+			t.Errorf("%s: cells %v, want %v", tt.name, got, want)
+		}
+	}
+}
+
+// TestTransformsCommute checks that a transformed field evolves as the
+// field does, transformed, on twisted and non-orientable surfaces.
+func TestTransformsCommute(t *testing.T) {
+	for _, top := range []Topology{
+		Torus,
+		{X: Wrap, Y: Wrap, ShiftX: 3},
+		{X: Wrap, Y: Wrap, ShiftY: -2},
+		KleinBottle,
+		MobiusBand,
+	} {
+		rng := rand.New(rand.NewSource(6))
+		f := NewFieldWithTopology(14, 10, top)
+		for y := 0; y < f.Height(); y++ {
+			for x := 0; x < f.Width(); x++ {
+				f.Set(x, y, rng.Intn(3) == 0)
+			}
+		}
+		for _, tr := range []struct {
+			name string
+			fn   func(*Field) *Field
+		}{
+			{"Rotate90", (*Field).Rotate90},
+			{"FlipH", (*Field).FlipH},
+			{"FlipV", (*Field).FlipV},
+			{"Transpose", (*Field).Transpose},
+		} {
+			a := NewLifeFromField(tr.fn(f))
+			b := NewLifeFromField(f.Clone())
+			for gen := 1; gen <= 8; gen++ {
+				a.Step()
+				b.Step()
+				if !a.Field().Equal(tr.fn(b.Field())) {
+					t.Errorf("%v: %s of the field differs at generation %d", top, tr.name, gen)
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package life
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Weighted is a two-state rule over a weighted square neighborhood: each
+// cell of the neighborhood contributes its weight to a sum when it is
+// alive, and a dead cell is born, or a live cell survives, if the sum is
+// one of the birth or survival sums. Weighted implements Stepper.
+type Weighted struct {
+	// Weights is a square matrix of odd side, centered on the cell, giving
+	// the weight of each neighbor; the center weight applies to the cell
+	// itself.
+	Weights  [][]int
+	Birth    map[int]bool
+	Survival map[int]bool
+}
+
+// LoadWeighted reads a weighted rule from a small text format. Blank lines
+// and lines starting with '#' are ignored; a "weights" line is followed by
+// the rows of the weight matrix, and "birth" and "survival" lines list the
+// sums for which cells are born and survive. For example, Conway's Life is
+//
+//	weights
+//	1 1 1
+//	1 0 1
+//	1 1 1
+//	birth 3
+//	survival 2 3
+func LoadWeighted(r io.Reader) (Weighted, error) {
+	w := Weighted{Birth: make(map[int]bool), Survival: make(map[int]bool)}
+	sc := bufio.NewScanner(r)
+	rows := -1 // number of weight rows still expected, or -1 outside a matrix
+	for n := 1; sc.Scan(); n++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if rows > 0 || rows == 0 && len(w.Weights) == 0 {
+			row := make([]int, len(fields))
+			for i, s := range fields {
+				v, err := strconv.Atoi(s)
+				if err != nil {
+					return Weighted{}, fmt.Errorf("life: invalid weight %q on line %d", s, n)
+				}
+				row[i] = v
+			}
+			if len(w.Weights) == 0 {
+				if len(row)%2 == 0 {
+					return Weighted{}, fmt.Errorf("life: weight matrix on line %d must have an odd side", n)
+				}
+				rows = len(row)
+			} else if len(row) != len(w.Weights[0]) {
+				return Weighted{}, fmt.Errorf("life: weight row on line %d has %d entries, want %d", n, len(row), len(w.Weights[0]))
+			}
+			w.Weights = append(w.Weights, row)
+			rows--
+			continue
+		}
+		var set map[int]bool
+		switch fields[0] {
+		case "weights":
+			if len(w.Weights) > 0 {
+				return Weighted{}, fmt.Errorf("life: duplicate weights on line %d", n)
+			}
+			rows = 0
+			continue
+		case "birth":
+			set = w.Birth
+		case "survival":
+			set = w.Survival
+		default:
+			return Weighted{}, fmt.Errorf("life: unknown keyword %q on line %d", fields[0], n)
+		}
+		for _, s := range fields[1:] {
+			v, err := strconv.Atoi(s)
+			if err != nil {
+				return Weighted{}, fmt.Errorf("life: invalid sum %q on line %d", s, n)
+			}
+			set[v] = true
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return Weighted{}, err
+	}
+	if len(w.Weights) == 0 || rows > 0 {
+		return Weighted{}, fmt.Errorf("life: missing or incomplete weight matrix")
+	}
+	return w, nil
+}
+
+// String returns the rule in the format read by LoadWeighted.
+func (w Weighted) String() string {
+	var b strings.Builder
+	b.WriteString("weights\n")
+	for _, row := range w.Weights {
+		for i, v := range row {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(strconv.Itoa(v))
+		}
+		b.WriteByte('\n')
+	}
+	for _, part := range []struct {
+		name string
+		set  map[int]bool
+	}{{"birth", w.Birth}, {"survival", w.Survival}} {
+		sums := make([]int, 0, len(part.set))
+		for v, ok := range part.set {
+			if ok {
+				sums = append(sums, v)
+			}
+		}
+		sort.Ints(sums)
+		b.WriteString(part.name)
+		for _, v := range sums {
+			b.WriteByte(' ')
+			b.WriteString(strconv.Itoa(v))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Step implements Stepper.
+func (w Weighted) Step(dst, src *Field) {
+	r := len(w.Weights) / 2
+	for y := 0; y < src.h; y++ {
+		for x := 0; x < src.width; x++ {
+			sum := 0
+			for j, row := range w.Weights {
+				for i, v := range row {
+					if v != 0 && src.Alive(x+i-r, y+j-r) {
+						sum += v
+					}
+				}
+			}
+			if src.get(x, y) {
+				dst.set(x, y, w.Survival[sum])
+			} else {
+				dst.set(x, y, w.Birth[sum])
+			}
+		}
+	}
+}
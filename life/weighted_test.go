@@ -0,0 +1,87 @@
+package life
+
+import (
+	"strings"
+	"testing"
+)
+
+// conwayWeighted is Conway's Life as a weighted rule, as in the
+// documentation of LoadWeighted.
+const conwayWeighted = `# Conway's Life
+weights
+1 1 1
+1 0 1
+1 1 1
+
+birth 3
+survival 2 3
+`
+
+// TestLoadWeighted checks that the weighted form of Conway's Life steps as
+// the Rule, that String writes what LoadWeighted reads, and that malformed
+// files are rejected.
+func TestLoadWeighted(t *testing.T) {
+	w, err := LoadWeighted(strings.NewReader(conwayWeighted))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := NewLifeFromField(soup("B3/S23"))
+	grid := want.Clone()
+	grid.SetStepper(w)
+	for gen := 1; gen <= 20; gen++ {
+		want.Step()
+		grid.Step()
+		if !grid.Field().Equal(want.Field()) {
+			t.Fatalf("weighted Conway differs from B3/S23 at generation %d", gen)
+		}
+	}
+	v, err := LoadWeighted(strings.NewReader(w.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.String() != w.String() {
+		t.Errorf("String() read back as\n%s\nwant\n%s", v, w)
+	}
+
+	for _, in := range []string{
+		"birth 3\n",
+		"weights\n1 1\n1 1\n",
+		"weights\n1 1 1\n1 0\n1 1 1\n",
+		"weights\n1 1 1\n1 0 1\n",
+		"weights\n1 x 1\n1 0 1\n1 1 1\n",
+		"weights\n1\nweights\n1\n",
+		"weights\n1\nbirth three\n",
+		"weights\n1\ndeath 3\n",
+	} {
+		if _, err := LoadWeighted(strings.NewReader(in)); err == nil {
+			t.Errorf("LoadWeighted(%q): no error", in)
+		}
+	}
+}
+
+// TestWeightedStep checks that weights are summed, including negative
+// ones and that of the cell itself.
+func TestWeightedStep(t *testing.T) {
+	w, err := LoadWeighted(strings.NewReader("weights\n0 2 0\n-1 1 1\n0 0 0\nbirth 2\nsurvival 3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		rows []string
+		next bool
+	}{
+		{[]string{".O.", "...", "..."}, true},  // 2 from the north
+		{[]string{".O.", "O..", "..."}, false}, // 2 - 1
+		{[]string{"...", "..O", "..."}, false}, // 1 from the east
+		{[]string{".O.", ".O.", "..."}, true},  // 2 + 1 for the cell itself
+		{[]string{".O.", ".OO", "..."}, false}, // 2 + 1 + 1
+		{[]string{"OOO", "...", "OOO"}, true},  // 2, the other cells weighing 0
+	} {
+		src := fieldOf(t, Plane, tt.rows...)
+		dst := NewFieldWithTopology(3, 3, Plane)
+		w.Step(dst, src)
+		if dst.Alive(1, 1) != tt.next {
+			t.Errorf("%v: center alive %v, want %v", tt.rows, dst.Alive(1, 1), tt.next)
+		}
+	}
+}
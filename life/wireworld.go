@@ -0,0 +1,50 @@
+package life
+
+import "image/color"
+
+// States of the Wireworld automaton, numbered as in Golly.
+const (
+	Empty     State = iota // empty space
+	Head                   // electron head
+	Tail                   // electron tail
+	Conductor              // wire
+)
+
+// Wireworld is the four-state rule of Brian Silverman's Wireworld, in
+// which electrons travel along wires. Electron heads become tails, tails
+// become conductors, and conductors become heads if exactly one or two of
+// their neighbors are heads. Empty cells stay empty.
+type Wireworld struct{}
+
+// States returns the number of states of the rule.
+func (Wireworld) States() int { return 4 }
+
+// String returns the name of the rule.
+func (Wireworld) String() string { return "WireWorld" }
+
+// Next implements StateRule.
+func (Wireworld) Next(g *Grid, x, y int) State {
+	switch g.At(x, y) {
+	case Head:
+		return Tail
+	case Tail:
+		return Conductor
+	case Conductor:
+		if n := g.Count(x, y, Head); n == 1 || n == 2 {
+			return Head
+		}
+		return Conductor
+	}
+	return Empty
+}
+
+// Palette implements Colorer, drawing heads blue, tails red and conductors
+// yellow on black.
+func (Wireworld) Palette() color.Palette {
+	return color.Palette{
+		color.Black,
+		color.RGBA{0x00, 0x80, 0xff, 0xff},
+		color.RGBA{0xff, 0x40, 0x20, 0xff},
+		color.RGBA{0xff, 0xd0, 0x00, 0xff},
+	}
+}
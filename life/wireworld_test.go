@@ -0,0 +1,62 @@
+package life
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestWireworld checks that an electron loaded from a multi-state RLE file
+// travels along its wire, its tail behind it.
+func TestWireworld(t *testing.T) {
+	g, rule, err := LoadRLEGrid(strings.NewReader("x = 10, y = 3, rule = WireWorld\n$.BA6C!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule != (Wireworld{}).String() {
+		t.Errorf("rule %q, want WireWorld", rule)
+	}
+	m := NewAutomatonFromGrid(g, Wireworld{})
+	for gen := 1; gen <= 6; gen++ {
+		m.Step()
+		for x := 0; x < 10; x++ {
+			want := Empty
+			switch {
+			case x == gen+2:
+				want = Head
+			case x == gen+1:
+				want = Tail
+			case x >= 1 && x <= 8:
+				want = Conductor
+			}
+			if s := m.Grid().At(x, 1); s != want {
+				t.Fatalf("generation %d: cell %d in state %d, want %d", gen, x, s, want)
+			}
+		}
+	}
+	if p := (Wireworld{}).Palette(); len(p) != (Wireworld{}).States() {
+		t.Errorf("%d colors for %d states", len(p), (Wireworld{}).States())
+	}
+}
+
+// TestLoadRLEGrid checks the notation of the states of multi-state RLE
+// files.
+func TestLoadRLEGrid(t *testing.T) {
+	g, rule, err := LoadRLEGrid(strings.NewReader("x = 5, y = 2\no.2X$pAyO!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule != "" {
+		t.Errorf("rule %q, want none", rule)
+	}
+	for _, c := range []struct {
+		x, y int
+		s    State
+	}{{0, 0, 1}, {1, 0, 0}, {2, 0, 24}, {3, 0, 24}, {0, 1, 25}, {1, 1, 255}, {4, 1, 0}} {
+		if s := g.At(c.x, c.y); s != c.s {
+			t.Errorf("cell %d, %d in state %d, want %d", c.x, c.y, s, c.s)
+		}
+	}
+	if _, _, err := LoadRLEGrid(strings.NewReader("x = 2, y = 1\npZ!")); err == nil {
+		t.Error("state past 255: no error")
+	}
+}